@@ -238,7 +238,7 @@ func TestProcessAttestations_OK(t *testing.T) {
 		require.NoError(t, err)
 		sigs[i] = sig
 	}
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	block := testutil.NewBeaconBlock()
 	block.Block.Body.Attestations = []*ethpb.Attestation{att}
@@ -282,7 +282,7 @@ func TestProcessAggregatedAttestation_OverlappingBits(t *testing.T) {
 		require.NoError(t, err)
 		sigs[i] = sig
 	}
-	att1.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att1.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	aggBits2 := bitfield.NewBitlist(4)
 	aggBits2.SetBitAt(1, true)
@@ -305,7 +305,7 @@ func TestProcessAggregatedAttestation_OverlappingBits(t *testing.T) {
 		require.NoError(t, err)
 		sigs[i] = sig
 	}
-	att2.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att2.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	_, err = attaggregation.AggregatePair(att1, att2)
 	assert.ErrorContains(t, aggregation.ErrBitsOverlap.Error(), err)
@@ -347,7 +347,7 @@ func TestProcessAggregatedAttestation_NoOverlappingBits(t *testing.T) {
 		require.NoError(t, err)
 		sigs[i] = sig
 	}
-	att1.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att1.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	aggBits2 := bitfield.NewBitlist(9)
 	aggBits2.SetBitAt(2, true)
@@ -370,7 +370,7 @@ func TestProcessAggregatedAttestation_NoOverlappingBits(t *testing.T) {
 		require.NoError(t, err)
 		sigs[i] = sig
 	}
-	att2.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att2.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	aggregatedAtt, err := attaggregation.AggregatePair(att1, att2)
 	require.NoError(t, err)
@@ -605,7 +605,7 @@ func TestVerifyIndexedAttestation_OK(t *testing.T) {
 			require.NoError(t, err)
 			sig = append(sig, validatorSig)
 		}
-		aggSig := bls.AggregateSignatures(sig)
+		aggSig := bls.MustAggregateSignatures(sig)
 		marshalledSig := aggSig.Marshal()
 
 		tt.attestation.Signature = marshalledSig
@@ -716,7 +716,7 @@ func TestVerifyAttestations_VerifiesMultipleAttestations(t *testing.T) {
 		att1.AggregationBits.SetBitAt(uint64(i), true)
 		sigs = append(sigs, keys[u].Sign(root[:]))
 	}
-	att1.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att1.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	comm2, err := helpers.BeaconCommitteeFromState(st, 1 /*slot*/, 1 /*committeeIndex*/)
 	require.NoError(t, err)
@@ -738,7 +738,7 @@ func TestVerifyAttestations_VerifiesMultipleAttestations(t *testing.T) {
 		att2.AggregationBits.SetBitAt(uint64(i), true)
 		sigs = append(sigs, keys[u].Sign(root[:]))
 	}
-	att2.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att2.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	b := testutil.NewBeaconBlock()
 	b.Block.Body.Attestations = []*ethpb.Attestation{att1, att2}
@@ -791,7 +791,7 @@ func TestVerifyAttestations_HandlesPlannedFork(t *testing.T) {
 		att1.AggregationBits.SetBitAt(uint64(i), true)
 		sigs = append(sigs, keys[u].Sign(root[:]))
 	}
-	att1.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att1.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	comm2, err := helpers.BeaconCommitteeFromState(st, 1*params.BeaconConfig().SlotsPerEpoch+1 /*slot*/, 1 /*committeeIndex*/)
 	require.NoError(t, err)
@@ -815,7 +815,7 @@ func TestVerifyAttestations_HandlesPlannedFork(t *testing.T) {
 		att2.AggregationBits.SetBitAt(uint64(i), true)
 		sigs = append(sigs, keys[u].Sign(root[:]))
 	}
-	att2.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att2.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	b := testutil.NewBeaconBlock()
 	b.Block.Body.Attestations = []*ethpb.Attestation{att1, att2}
@@ -862,7 +862,7 @@ func TestRetrieveAttestationSignatureSet_VerifiesMultipleAttestations(t *testing
 		att1.AggregationBits.SetBitAt(uint64(i), true)
 		sigs = append(sigs, keys[u].Sign(root[:]))
 	}
-	att1.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att1.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	comm2, err := helpers.BeaconCommitteeFromState(st, 1 /*slot*/, 1 /*committeeIndex*/)
 	require.NoError(t, err)
@@ -884,7 +884,7 @@ func TestRetrieveAttestationSignatureSet_VerifiesMultipleAttestations(t *testing
 		att2.AggregationBits.SetBitAt(uint64(i), true)
 		sigs = append(sigs, keys[u].Sign(root[:]))
 	}
-	att2.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att2.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	set, err := blocks.AttestationSignatureSet(ctx, st, []*ethpb.Attestation{att1, att2})
 	require.NoError(t, err)
@@ -55,7 +55,7 @@ func TestProcessAttesterSlashings_RegressionSlashableIndices(t *testing.T) {
 		sig := privKeys[index].Sign(signingRoot[:])
 		aggSigs = append(aggSigs, sig)
 	}
-	aggregateSig := bls.AggregateSignatures(aggSigs)
+	aggregateSig := bls.MustAggregateSignatures(aggSigs)
 	att1.Signature = aggregateSig.Marshal()
 
 	root2 := [32]byte{'d', 'o', 'u', 'b', 'l', 'e', '2'}
@@ -75,7 +75,7 @@ func TestProcessAttesterSlashings_RegressionSlashableIndices(t *testing.T) {
 		sig := privKeys[index].Sign(signingRoot[:])
 		aggSigs = append(aggSigs, sig)
 	}
-	aggregateSig = bls.AggregateSignatures(aggSigs)
+	aggregateSig = bls.MustAggregateSignatures(aggSigs)
 	att2.Signature = aggregateSig.Marshal()
 
 	slashings := []*ethpb.AttesterSlashing{
@@ -31,6 +31,49 @@ func IsAggregator(committeeCount uint64, slotSig []byte) (bool, error) {
 	return binary.LittleEndian.Uint64(b[:8])%modulo == 0, nil
 }
 
+// VerifySelectionProof returns true if the proof is a valid signature of the slot by the
+// public key under the selection proof domain. Whether the proof makes the validator an
+// aggregator is checked separately by IsAggregator, which depends on the committee length.
+//
+// Spec pseudocode definition:
+//   def get_slot_signature(state: BeaconState, slot: Slot, privkey: int) -> BLSSignature:
+//    domain = get_domain(state, DOMAIN_SELECTION_PROOF, compute_epoch_at_slot(slot))
+//    signing_root = compute_signing_root(slot, domain)
+//    return bls.Sign(privkey, signing_root)
+func VerifySelectionProof(pub bls.PublicKey, slot uint64, proof bls.Signature, domain []byte) bool {
+	if pub == nil || proof == nil {
+		return false
+	}
+	root, err := ComputeSigningRoot(slot, domain)
+	if err != nil {
+		return false
+	}
+	return proof.Verify(pub, root[:])
+}
+
+// VerifyAggregateAndProofSignature returns true if the signature is a valid signature of
+// the aggregate and proof with the given hash tree root by the aggregator's public key under
+// the aggregate and proof domain. It is shared by the validator, which checks its signature
+// before submission, and by anyone verifying a signed aggregate and proof on its own.
+//
+// Spec pseudocode definition:
+//   def get_aggregate_and_proof_signature(state: BeaconState,
+//                                         aggregate_and_proof: AggregateAndProof,
+//                                         privkey: int) -> BLSSignature:
+//    aggregate = aggregate_and_proof.aggregate
+//    domain = get_domain(state, DOMAIN_AGGREGATE_AND_PROOF, compute_epoch_at_slot(aggregate.data.slot))
+//    signing_root = compute_signing_root(aggregate_and_proof, domain)
+//    return bls.Sign(privkey, signing_root)
+func VerifyAggregateAndProofSignature(pub bls.PublicKey, aggAndProofRoot [32]byte, sig bls.Signature, domain []byte) bool {
+	root, err := signingData(func() ([32]byte, error) {
+		return aggAndProofRoot, nil
+	}, domain)
+	if err != nil {
+		return false
+	}
+	return bls.VerifyStrictRoot(pub, root, sig)
+}
+
 // AggregateSignature returns the aggregated signature of the input attestations.
 //
 // Spec pseudocode definition:
@@ -46,7 +89,7 @@ func AggregateSignature(attestations []*ethpb.Attestation) (bls.Signature, error
 			return nil, err
 		}
 	}
-	return bls.AggregateSignatures(sigs), nil
+	return bls.AggregateSignatures(sigs)
 }
 
 // IsAggregated returns true if the attestation is an aggregated attestation,
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -43,6 +44,60 @@ func TestAttestation_IsAggregator(t *testing.T) {
 	})
 }
 
+func TestAttestation_VerifySelectionProof(t *testing.T) {
+	priv, err := bls.RandKey()
+	require.NoError(t, err)
+	otherPriv, err := bls.RandKey()
+	require.NoError(t, err)
+	domain := bytesutil.PadTo(params.BeaconConfig().DomainSelectionProof[:], 32)
+	slot := uint64(5)
+	root, err := helpers.ComputeSigningRoot(slot, domain)
+	require.NoError(t, err)
+	proof := priv.Sign(root[:])
+
+	assert.Equal(t, true, helpers.VerifySelectionProof(priv.PublicKey(), slot, proof, domain))
+	assert.Equal(t, false, helpers.VerifySelectionProof(otherPriv.PublicKey(), slot, proof, domain), "Proof verified for another key")
+	assert.Equal(t, false, helpers.VerifySelectionProof(priv.PublicKey(), slot+1, proof, domain), "Proof verified for another slot")
+	otherDomain := bytesutil.PadTo(params.BeaconConfig().DomainBeaconAttester[:], 32)
+	assert.Equal(t, false, helpers.VerifySelectionProof(priv.PublicKey(), slot, proof, otherDomain), "Proof verified for another domain")
+	assert.Equal(t, false, helpers.VerifySelectionProof(priv.PublicKey(), slot, nil, domain))
+}
+
+func TestAttestation_VerifyAggregateAndProofSignature(t *testing.T) {
+	priv, err := bls.RandKey()
+	require.NoError(t, err)
+	domain := bytesutil.PadTo(params.BeaconConfig().DomainAggregateAndProof[:], 32)
+	aggAndProof := &ethpb.AggregateAttestationAndProof{
+		AggregatorIndex: 3,
+		Aggregate: &ethpb.Attestation{
+			AggregationBits: bitfield.NewBitlist(1),
+			Data: &ethpb.AttestationData{
+				BeaconBlockRoot: make([]byte, 32),
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			},
+			Signature: make([]byte, 96),
+		},
+		SelectionProof: make([]byte, 96),
+	}
+	aggAndProofRoot, err := aggAndProof.HashTreeRoot()
+	require.NoError(t, err)
+	signingRoot, err := helpers.ComputeSigningRoot(aggAndProof, domain)
+	require.NoError(t, err)
+	sig := priv.Sign(signingRoot[:])
+	assert.Equal(t, true, helpers.VerifyAggregateAndProofSignature(priv.PublicKey(), aggAndProofRoot, sig, domain))
+
+	// A signature of an aggregate and proof by another aggregator does not verify.
+	aggAndProof.AggregatorIndex = 4
+	tamperedRoot, err := helpers.ComputeSigningRoot(aggAndProof, domain)
+	require.NoError(t, err)
+	tampered := priv.Sign(tamperedRoot[:])
+	assert.Equal(t, false, helpers.VerifyAggregateAndProofSignature(priv.PublicKey(), aggAndProofRoot, tampered, domain), "Tampered signature verified")
+	otherDomain := bytesutil.PadTo(params.BeaconConfig().DomainBeaconAttester[:], 32)
+	assert.Equal(t, false, helpers.VerifyAggregateAndProofSignature(priv.PublicKey(), aggAndProofRoot, sig, otherDomain), "Signature verified for another domain")
+	assert.Equal(t, false, helpers.VerifyAggregateAndProofSignature(priv.PublicKey(), aggAndProofRoot, nil, domain))
+}
+
 func TestAttestation_AggregateSignature(t *testing.T) {
 	t.Run("verified", func(t *testing.T) {
 		pubkeys := make([]bls.PublicKey, 0, 100)
@@ -72,6 +72,24 @@ func signingData(rootFunc func() ([32]byte, error), domain []byte) ([32]byte, er
 	return container.HashTreeRoot()
 }
 
+// SignWithDomain signs the signing root which mixes the domain into the hash tree root of
+// an object, so callers holding a secret key do not compute the signing root themselves.
+func SignWithDomain(sk bls.SecretKey, objectRoot [32]byte, domain []byte) (bls.Signature, error) {
+	if sk == nil {
+		return nil, errors.New("nil secret key")
+	}
+	if len(domain) != 32 {
+		return nil, errors.Errorf("domain must be 32 bytes, received %d", len(domain))
+	}
+	signingRoot, err := signingData(func() ([32]byte, error) {
+		return objectRoot, nil
+	}, domain)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	return sk.Sign(signingRoot[:]), nil
+}
+
 // ComputeDomainVerifySigningRoot computes domain and verifies signing root of an object given the beacon state, validator index and signature.
 func ComputeDomainVerifySigningRoot(state *state.BeaconState, index, epoch uint64, obj interface{}, domain [4]byte, sig []byte) error {
 	v, err := state.ValidatorAtIndex(index)
@@ -7,7 +7,9 @@ import (
 	fuzz "github.com/google/gofuzz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	ethereum_beacon_p2p_v1 "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
@@ -19,6 +21,25 @@ func TestSigningRoot_ComputeOK(t *testing.T) {
 	assert.NoError(t, err, "Could not compute signing root of block")
 }
 
+func TestSignWithDomain(t *testing.T) {
+	priv, err := bls.RandKey()
+	require.NoError(t, err)
+	objectRoot := [32]byte{'o', 'b', 'j', 'e', 'c', 't'}
+	domain := bytesutil.PadTo([]byte{'d', 'o', 'm', 'a', 'i', 'n'}, 32)
+
+	sig, err := helpers.SignWithDomain(priv, objectRoot, domain)
+	require.NoError(t, err)
+	// The hash tree root of a container of two 32 byte fields is the hash of the fields.
+	signingRoot := hashutil.Hash(append(objectRoot[:], domain...))
+	require.Equal(t, true, sig.Verify(priv.PublicKey(), signingRoot[:]))
+	require.Equal(t, false, sig.Verify(priv.PublicKey(), objectRoot[:]), "Signature of the object root verified")
+
+	_, err = helpers.SignWithDomain(priv, objectRoot, domain[:4])
+	require.ErrorContains(t, "domain must be 32 bytes, received 4", err)
+	_, err = helpers.SignWithDomain(nil, objectRoot, domain)
+	require.ErrorContains(t, "nil secret key", err)
+}
+
 func TestComputeDomain_OK(t *testing.T) {
 	tests := []struct {
 		epoch      uint64
@@ -407,7 +407,7 @@ func createFullBlockWithOperations(t *testing.T) (*beaconstate.BeaconState,
 	require.NoError(t, err)
 	sig0 := privKeys[0].Sign(hashTreeRoot[:])
 	sig1 := privKeys[1].Sign(hashTreeRoot[:])
-	aggregateSig := bls.AggregateSignatures([]bls.Signature{sig0, sig1})
+	aggregateSig := bls.MustAggregateSignatures([]bls.Signature{sig0, sig1})
 	att1.Signature = aggregateSig.Marshal()
 
 	mockRoot3 := [32]byte{'B'}
@@ -425,7 +425,7 @@ func createFullBlockWithOperations(t *testing.T) (*beaconstate.BeaconState,
 	require.NoError(t, err)
 	sig0 = privKeys[0].Sign(hashTreeRoot[:])
 	sig1 = privKeys[1].Sign(hashTreeRoot[:])
-	aggregateSig = bls.AggregateSignatures([]bls.Signature{sig0, sig1})
+	aggregateSig = bls.MustAggregateSignatures([]bls.Signature{sig0, sig1})
 	att2.Signature = aggregateSig.Marshal()
 
 	attesterSlashings := []*ethpb.AttesterSlashing{
@@ -467,7 +467,7 @@ func createFullBlockWithOperations(t *testing.T) (*beaconstate.BeaconState,
 		sig := privKeys[indice].Sign(hashTreeRoot[:])
 		sigs[i] = sig
 	}
-	blockAtt.Signature = bls.AggregateSignatures(sigs).Marshal()
+	blockAtt.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	exit := &ethpb.SignedVoluntaryExit{
 		Exit: &ethpb.VoluntaryExit{
@@ -786,7 +786,7 @@ func TestProcessBlk_AttsBasedOnValidatorCount(t *testing.T) {
 			sig := privKeys[indice].Sign(hashTreeRoot[:])
 			sigs[i] = sig
 		}
-		att.Signature = bls.AggregateSignatures(sigs).Marshal()
+		att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 		atts[i] = att
 	}
 
@@ -36,6 +36,7 @@ import (
 	regularsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
 	initialsync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/event"
@@ -76,6 +77,7 @@ type BeaconNode struct {
 	opFeed            *event.Feed
 	forkChoiceStore   forkchoice.ForkChoicer
 	stateGen          *stategen.State
+	blsEntropySource  *os.File
 }
 
 // NewBeaconNode creates a new node instance, sets up configuration options, and registers
@@ -98,10 +100,37 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 	cmd.ConfigureBeaconChain(cliCtx)
 	flags.ConfigureGlobalFlags(cliCtx)
 
+	var blsEntropySource *os.File
+	if entropyFile := featureconfig.Get().BLSExtraEntropyFile; entropyFile != "" {
+		f, err := os.Open(entropyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not open BLS entropy source")
+		}
+		if err := bls.SetExtraEntropySource(f); err != nil {
+			if closeErr := f.Close(); closeErr != nil {
+				log.WithError(closeErr).Error("Could not close BLS entropy source")
+			}
+			return nil, errors.Wrapf(err, "could not use %s as a BLS entropy source", entropyFile)
+		}
+		blsEntropySource = f
+		log.WithField("path", entropyFile).Info("Mixing extra entropy into batch signature verification")
+	}
+	if traceFile := featureconfig.Get().BLSVerificationTraceFile; traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create BLS verification trace file")
+		}
+		bls.SetVerificationTrace(f)
+		log.WithField("path", traceFile).Warn("Tracing BLS signature verifications")
+	}
+
 	if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
 		chainConfigFileName := cliCtx.String(cmd.ChainConfigFileFlag.Name)
 		params.LoadChainConfigFile(chainConfigFileName)
 	}
+	if err := bls.CheckDomainSeparationTag(); err != nil {
+		return nil, err
+	}
 
 	if cliCtx.Bool(flags.HistoricalSlasherNode.Name) {
 		c := params.BeaconConfig()
@@ -166,6 +195,7 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		exitPool:          voluntaryexits.NewPool(),
 		slashingsPool:     slashings.NewPool(),
 		stateSummaryCache: cache.NewStateSummaryCache(),
+		blsEntropySource:  blsEntropySource,
 	}
 
 	if err := beacon.startDB(cliCtx); err != nil {
@@ -280,6 +310,15 @@ func (b *BeaconNode) Close() {
 	if err := b.db.Close(); err != nil {
 		log.Errorf("Failed to close database: %v", err)
 	}
+	if b.blsEntropySource != nil {
+		// Stop reading from the source before closing it.
+		if err := bls.SetExtraEntropySource(nil); err != nil {
+			log.Errorf("Failed to remove BLS entropy source: %v", err)
+		}
+		if err := b.blsEntropySource.Close(); err != nil {
+			log.Errorf("Failed to close BLS entropy source: %v", err)
+		}
+	}
 	close(b.stop)
 }
 
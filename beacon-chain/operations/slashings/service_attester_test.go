@@ -33,8 +33,8 @@ func validAttesterSlashingForValIdx(t *testing.T, beaconState *state.BeaconState
 		allSig1 = append(allSig1, sigFromBytes1)
 		allSig2 = append(allSig2, sigFromBytes2)
 	}
-	aggSig1 := bls.AggregateSignatures(allSig1)
-	aggSig2 := bls.AggregateSignatures(allSig2)
+	aggSig1 := bls.MustAggregateSignatures(allSig1)
+	aggSig2 := bls.MustAggregateSignatures(allSig2)
 	aggSlashing := &ethpb.AttesterSlashing{
 		Attestation_1: &ethpb.IndexedAttestation{
 			AttestingIndices: valIdx,
@@ -254,7 +254,7 @@ func generateAtt(state *beaconstate.BeaconState, index uint64, privKeys []bls.Se
 		sigs[i] = sig
 	}
 
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	return att, nil
 }
@@ -295,7 +295,7 @@ func generateUnaggregatedAtt(state *beaconstate.BeaconState, index uint64, privK
 		sigs[i] = sig
 	}
 
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 
 	return att, nil
 }
@@ -1881,7 +1881,7 @@ func TestProposer_FilterAttestation(t *testing.T) {
 						sig := privKeys[indice].Sign(hashTreeRoot[:])
 						sigs[i] = sig
 					}
-					atts[i].Signature = bls.AggregateSignatures(sigs).Marshal()
+					atts[i].Signature = bls.MustAggregateSignatures(sigs).Marshal()
 				}
 				return atts
 			},
@@ -144,27 +144,34 @@ func (s *Service) validateAggregatedAtt(ctx context.Context, signed *ethpb.Signe
 		return pubsub.ValidationReject
 	}
 
-	// Verify selection signature, aggregator signature and attestation signature are valid.
-	// We use batch verify here to save compute.
-	aggregatorSigSet, err := aggSigSet(bs, signed)
+	// Verify the aggregator signature with the same check the validator client runs on its
+	// own signature before submission.
+	valid, err := verifyAggregatorSignature(bs, signed)
 	if err != nil {
-		traceutil.AnnotateError(span, errors.Wrapf(err, "Could not get aggregator sig set %d", signed.Message.AggregatorIndex))
+		traceutil.AnnotateError(span, errors.Wrapf(err, "Could not verify aggregator signature %d", signed.Message.AggregatorIndex))
 		return pubsub.ValidationIgnore
 	}
+	if !valid {
+		traceutil.AnnotateError(span, errors.Errorf("Could not verify aggregator signature %d", signed.Message.AggregatorIndex))
+		return pubsub.ValidationReject
+	}
+
+	// Verify selection signature and attestation signature are valid.
+	// We use batch verify here to save compute.
 	attSigSet, err := blocks.AttestationSignatureSet(ctx, bs, []*ethpb.Attestation{signed.Message.Aggregate})
 	if err != nil {
 		traceutil.AnnotateError(span, errors.Wrapf(err, "Could not verify aggregator signature %d", signed.Message.AggregatorIndex))
 		return pubsub.ValidationIgnore
 	}
 	set := bls.NewSet()
-	set.Join(selectionSigSet).Join(aggregatorSigSet).Join(attSigSet)
-	valid, err := set.Verify()
+	set.Join(selectionSigSet).Join(attSigSet)
+	valid, err = set.Verify()
 	if err != nil {
 		traceutil.AnnotateError(span, errors.Errorf("Could not join signature set"))
 		return pubsub.ValidationIgnore
 	}
 	if !valid {
-		traceutil.AnnotateError(span, errors.Errorf("Could not verify selection or aggregator or attestation signature"))
+		traceutil.AnnotateError(span, errors.Errorf("Could not verify selection or attestation signature"))
 		return pubsub.ValidationReject
 	}
 
@@ -268,29 +275,30 @@ func validateSelectionIndex(ctx context.Context, bs *stateTrie.BeaconState, data
 	}, nil
 }
 
-// This returns aggregator signature set which can be used to batch verify.
-func aggSigSet(s *stateTrie.BeaconState, a *ethpb.SignedAggregateAttestationAndProof) (*bls.SignatureSet, error) {
+// This returns whether the aggregator signed the aggregate and proof. A signature which
+// cannot be deserialized does not verify.
+func verifyAggregatorSignature(s *stateTrie.BeaconState, a *ethpb.SignedAggregateAttestationAndProof) (bool, error) {
 	v, err := s.ValidatorAtIndex(a.Message.AggregatorIndex)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	publicKey, err := bls.PublicKeyFromBytes(v.PublicKey)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
 	epoch := helpers.SlotToEpoch(a.Message.Aggregate.Data.Slot)
 	d, err := helpers.Domain(s.Fork(), epoch, params.BeaconConfig().DomainAggregateAndProof, s.GenesisValidatorRoot())
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	root, err := helpers.ComputeSigningRoot(a.Message, d)
+	root, err := a.Message.HashTreeRoot()
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	return &bls.SignatureSet{
-		Signatures: [][]byte{a.Signature},
-		PublicKeys: []bls.PublicKey{publicKey},
-		Messages:   [][32]byte{root},
-	}, nil
+	sig, err := bls.SignatureFromBytes(a.Signature)
+	if err != nil {
+		return false, nil
+	}
+	return helpers.VerifyAggregateAndProofSignature(publicKey, root, sig, d), nil
 }
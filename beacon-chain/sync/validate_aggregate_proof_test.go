@@ -335,7 +335,7 @@ func TestValidateAggregateAndProof_CanValidate(t *testing.T) {
 		sig := privKeys[indice].Sign(hashTreeRoot[:])
 		sigs[i] = sig
 	}
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 	ai := committee[0]
 	sig, err := helpers.ComputeDomainAndSign(beaconState, 0, att.Data.Slot, params.BeaconConfig().DomainSelectionProof, privKeys[ai])
 	require.NoError(t, err)
@@ -385,6 +385,43 @@ func TestValidateAggregateAndProof_CanValidate(t *testing.T) {
 	assert.NotNil(t, msg.ValidatorData, "Did not set validator data")
 }
 
+func TestVerifyAggregatorSignature(t *testing.T) {
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 8)
+	signed := &ethpb.SignedAggregateAttestationAndProof{
+		Message: &ethpb.AggregateAttestationAndProof{
+			Aggregate: &ethpb.Attestation{
+				Data: &ethpb.AttestationData{
+					BeaconBlockRoot: make([]byte, 32),
+					Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+					Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				},
+				AggregationBits: bitfield.NewBitlist(1),
+				Signature:       make([]byte, 96),
+			},
+			AggregatorIndex: 1,
+			SelectionProof:  make([]byte, 96),
+		},
+	}
+	var err error
+	signed.Signature, err = helpers.ComputeDomainAndSign(beaconState, 0, signed.Message, params.BeaconConfig().DomainAggregateAndProof, privKeys[1])
+	require.NoError(t, err)
+	valid, err := verifyAggregatorSignature(beaconState, signed)
+	require.NoError(t, err)
+	assert.Equal(t, true, valid)
+
+	// Signed by another validator than the aggregator.
+	signed.Signature, err = helpers.ComputeDomainAndSign(beaconState, 0, signed.Message, params.BeaconConfig().DomainAggregateAndProof, privKeys[2])
+	require.NoError(t, err)
+	valid, err = verifyAggregatorSignature(beaconState, signed)
+	require.NoError(t, err)
+	assert.Equal(t, false, valid)
+
+	signed.Signature = make([]byte, 96)
+	valid, err = verifyAggregatorSignature(beaconState, signed)
+	require.NoError(t, err)
+	assert.Equal(t, false, valid)
+}
+
 func TestVerifyIndexInCommittee_SeenAggregatorEpoch(t *testing.T) {
 	db, _ := dbtest.SetupDB(t)
 	p := p2ptest.NewTestP2P(t)
@@ -422,7 +459,7 @@ func TestVerifyIndexInCommittee_SeenAggregatorEpoch(t *testing.T) {
 		sig := privKeys[indice].Sign(hashTreeRoot[:])
 		sigs[i] = sig
 	}
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 	ai := committee[0]
 	sig, err := helpers.ComputeDomainAndSign(beaconState, 0, att.Data.Slot, params.BeaconConfig().DomainSelectionProof, privKeys[ai])
 	require.NoError(t, err)
@@ -528,7 +565,7 @@ func TestValidateAggregateAndProof_BadBlock(t *testing.T) {
 		sig := privKeys[indice].Sign(hashTreeRoot[:])
 		sigs[i] = sig
 	}
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 	ai := committee[0]
 	sig, err := helpers.ComputeDomainAndSign(beaconState, 0, att.Data.Slot, params.BeaconConfig().DomainSelectionProof, privKeys[ai])
 	require.NoError(t, err)
@@ -616,7 +653,7 @@ func TestValidateAggregateAndProof_RejectWhenAttEpochDoesntEqualTargetEpoch(t *t
 		sig := privKeys[indice].Sign(hashTreeRoot[:])
 		sigs[i] = sig
 	}
-	att.Signature = bls.AggregateSignatures(sigs).Marshal()
+	att.Signature = bls.MustAggregateSignatures(sigs).Marshal()
 	ai := committee[0]
 	sig, err := helpers.ComputeDomainAndSign(beaconState, 0, att.Data.Slot, params.BeaconConfig().DomainSelectionProof, privKeys[ai])
 	require.NoError(t, err)
@@ -1765,6 +1765,13 @@ def prysm_deps():
         version = "v0.0.0-20180823135443-60711f1a8329",
     )
 
+    go_repository(
+        name = "com_github_makiuchi_d_gozxing",
+        importpath = "github.com/makiuchi-d/gozxing",
+        sum = "h1:xxqijhoedi+/lZlhINteGbywIrewVdVv2wl9r5O9S1I=",
+        version = "v0.1.1",
+    )
+
     go_repository(
         name = "com_github_manifoldco_promptui",
         importpath = "github.com/manifoldco/promptui",
@@ -2333,6 +2340,13 @@ def prysm_deps():
         version = "v1.6.0",
     )
 
+    go_repository(
+        name = "com_github_skip2_go_qrcode",
+        importpath = "github.com/skip2/go-qrcode",
+        sum = "h1:MRM5ITcdelLK2j1vwZ3Je0FKVCfqOLp5zO6trqMLYs0=",
+        version = "v0.0.0-20200617195104-da1b6568686e",
+    )
+
     go_repository(
         name = "com_github_smartystreets_assertions",
         importpath = "github.com/smartystreets/assertions",
@@ -3092,8 +3106,8 @@ def prysm_deps():
     go_repository(
         name = "org_golang_x_text",
         importpath = "golang.org/x/text",
-        sum = "h1:0YWbFKbhXG/wIiuHDSKpS0Iy7FSA+u45VtBMfQcFTTc=",
-        version = "v0.3.4",
+        sum = "h1:olpwvP2KacW1ZWvsR7uQhoyTYvKAupfQrRGBFM352Gk=",
+        version = "v0.3.7",
     )
     go_repository(
         name = "org_golang_x_time",
@@ -78,7 +78,10 @@ func AggregatePair(a1, a2 *ethpb.Attestation) (*ethpb.Attestation, error) {
 		return nil, err
 	}
 
-	aggregatedSig := aggregateSignatures([]bls.Signature{baseSig, newSig})
+	aggregatedSig, err := aggregateSignatures([]bls.Signature{baseSig, newSig})
+	if err != nil {
+		return nil, err
+	}
 	baseAtt.Signature = aggregatedSig.Marshal()
 	baseAtt.AggregationBits = newBits
 
@@ -14,8 +14,8 @@ import (
 func BenchmarkAggregateAttestations_Aggregate(b *testing.B) {
 	// Override expensive BLS aggregation method with cheap no-op such that this benchmark profiles
 	// the logic of aggregation selection rather than BLS logic.
-	aggregateSignatures = func(sigs []common.Signature) common.Signature {
-		return sigs[0]
+	aggregateSignatures = func(sigs []common.Signature) (common.Signature, error) {
+		return sigs[0], nil
 	}
 	signatureFromBytes = func(sig []byte) (common.Signature, error) {
 		return bls.NewAggregateSignature(), nil
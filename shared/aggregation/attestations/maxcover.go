@@ -94,10 +94,14 @@ func (al attList) aggregate(coverage bitfield.Bitlist) (*ethpb.Attestation, erro
 		}
 		signs[i] = sig
 	}
+	aggregatedSig, err := aggregateSignatures(signs)
+	if err != nil {
+		return nil, err
+	}
 	return &ethpb.Attestation{
 		AggregationBits: coverage,
 		Data:            stateTrie.CopyAttestationData(al[0].Data),
-		Signature:       aggregateSignatures(signs).Marshal(),
+		Signature:       aggregatedSig.Marshal(),
 	}, nil
 }
 
@@ -1,16 +1,22 @@
 // Package bls implements a go-wrapper around a library implementing the
 // the BLS12-381 curve and signature scheme. This package exposes a public API for
 // verifying and aggregating BLS signatures used by Ethereum 2.0.
+//
+// Keys are derived from a seed with DeriveMasterSK, and along EIP-2334 paths with
+// DeriveKeyFromPath, for whichever backend is enabled.
 package bls
 
 import (
+	"io"
 	"math/big"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared/bls/blst"
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
 	"github.com/prysmaticlabs/prysm/shared/bls/herumi"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
 // SecretKeyFromBytes creates a BLS private key from a BigEndian byte slice.
@@ -45,6 +51,25 @@ func PublicKeyFromBytes(pubKey []byte) (PublicKey, error) {
 	return herumi.PublicKeyFromBytes(pubKey)
 }
 
+// ClearPublicKeyCache removes every decoded public key from the cache used by
+// PublicKeyFromBytes, for whichever backend is enabled.
+func ClearPublicKeyCache() {
+	if featureconfig.Get().EnableBlst {
+		blst.ClearPublicKeyCache()
+		return
+	}
+	herumi.ClearPublicKeyCache()
+}
+
+// PublicKeysFromBytes creates BLS public keys from BigEndian byte slices. An error names
+// the index of the first malformed key.
+func PublicKeysFromBytes(pubKeys [][]byte) ([]PublicKey, error) {
+	if featureconfig.Get().EnableBlst {
+		return blst.PublicKeysFromBytes(pubKeys)
+	}
+	return herumi.PublicKeysFromBytes(pubKeys)
+}
+
 // SignatureFromBytes creates a BLS signature from a LittleEndian byte slice.
 func SignatureFromBytes(sig []byte) (Signature, error) {
 	if featureconfig.Get().EnableBlst {
@@ -53,6 +78,17 @@ func SignatureFromBytes(sig []byte) (Signature, error) {
 	return herumi.SignatureFromBytes(sig)
 }
 
+// SignaturesFromBytes creates BLS signatures from LittleEndian byte slices, deserializing
+// them concurrently with at most the given number of workers where the backend allows it.
+// Signatures are returned in the order of the input, and an error names the lowest index
+// of a malformed signature.
+func SignaturesFromBytes(sigs [][]byte, workers int) ([]Signature, error) {
+	if featureconfig.Get().EnableBlst {
+		return blst.SignaturesFromBytes(sigs, workers)
+	}
+	return herumi.SignaturesFromBytes(sigs, workers)
+}
+
 // AggregatePublicKeys aggregates the provided raw public keys into a single key.
 func AggregatePublicKeys(pubs [][]byte) (PublicKey, error) {
 	if featureconfig.Get().EnableBlst {
@@ -62,13 +98,24 @@ func AggregatePublicKeys(pubs [][]byte) (PublicKey, error) {
 }
 
 // AggregateSignatures converts a list of signatures into a single, aggregated sig.
-func AggregateSignatures(sigs []common.Signature) common.Signature {
+// It returns ErrNoSignatures if the list is empty.
+func AggregateSignatures(sigs []common.Signature) (common.Signature, error) {
 	if featureconfig.Get().EnableBlst {
 		return blst.AggregateSignatures(sigs)
 	}
 	return herumi.AggregateSignatures(sigs)
 }
 
+// MustAggregateSignatures is like AggregateSignatures but panics if the signatures can
+// not be aggregated. It is meant for callers which always have signatures to aggregate.
+func MustAggregateSignatures(sigs []common.Signature) common.Signature {
+	sig, err := AggregateSignatures(sigs)
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
+
 // VerifyMultipleSignatures verifies multiple signatures for distinct messages securely.
 func VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey) (bool, error) {
 	if featureconfig.Get().EnableBlst {
@@ -87,6 +134,184 @@ func VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []common.P
 	return herumi.VerifyMultipleSignatures(rawSigs, msgs, pubKeys)
 }
 
+// VerifyMultipleSignaturesParallel verifies multiple signatures for distinct messages like
+// VerifyMultipleSignatures, but with blst splits very large sets into partitions which are
+// verified concurrently by GOMAXPROCS workers. Identical entries are only verified once.
+// With herumi the set is verified serially.
+func VerifyMultipleSignaturesParallel(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey) (bool, error) {
+	if featureconfig.Get().EnableBlst {
+		return blst.VerifyMultipleSignaturesParallel(sigs, msgs, pubKeys, 0 /* workers */)
+	}
+	return VerifyMultipleSignatures(sigs, msgs, pubKeys)
+}
+
+// VerifyMultipleSignaturesReportFailures verifies multiple signatures for distinct messages
+// like VerifyMultipleSignatures, but when the batch fails it bisects the batch, verifying
+// each half in turn, to find the indices of the failing entries, so callers such as block
+// processing can reject only the invalid entries. A batch which verifies costs no more than
+// with VerifyMultipleSignatures, and only a few failures in a large batch need far fewer
+// verifications than checking every entry.
+//
+// Labels are opaque caller metadata, such as a validator public key and slot, returned for
+// the failing entries so failures can be mapped back to meaningful identities. Labels may be
+// nil, otherwise there must be one per signature. Entries whose signature cannot be
+// deserialized count as failing, mismatched input lengths are an error, and an empty batch
+// does not verify.
+func VerifyMultipleSignaturesReportFailures(
+	sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey, labels []string,
+) (bool, []int, []string, error) {
+	if len(msgs) != len(sigs) || len(pubKeys) != len(sigs) {
+		return false, nil, nil, errors.Errorf(
+			"provided signatures, pubkeys and messages have differing lengths. S: %d, P: %d, M: %d",
+			len(sigs), len(pubKeys), len(msgs),
+		)
+	}
+	if labels != nil && len(labels) != len(sigs) {
+		return false, nil, nil, errors.Errorf("provided %d labels for %d signatures", len(labels), len(sigs))
+	}
+	if len(sigs) == 0 {
+		return false, nil, nil, nil
+	}
+	failedIndices := bisectFailures(sigs, msgs, pubKeys, 0, false /* knownFailing */)
+	if len(failedIndices) == 0 {
+		return true, nil, nil, nil
+	}
+	var failedLabels []string
+	if labels != nil {
+		failedLabels = make([]string, len(failedIndices))
+		for i, index := range failedIndices {
+			failedLabels[i] = labels[index]
+		}
+	}
+	return false, failedIndices, failedLabels, nil
+}
+
+// Returns the indices, offset into the full batch, of the failing entries of a range of
+// the batch. A range known to contain a failure is not verified again before being split.
+func bisectFailures(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey, offset int, knownFailing bool) []int {
+	if !knownFailing {
+		verified, err := VerifyMultipleSignatures(sigs, msgs, pubKeys)
+		if err == nil && verified {
+			return nil
+		}
+	}
+	if len(sigs) == 1 {
+		return []int{offset}
+	}
+	mid := len(sigs) / 2
+	failedIndices := bisectFailures(sigs[:mid], msgs[:mid], pubKeys[:mid], offset, false)
+	// When the first half is valid, the failure must be in the second half.
+	secondKnownFailing := len(failedIndices) == 0
+	return append(failedIndices, bisectFailures(sigs[mid:], msgs[mid:], pubKeys[mid:], offset+mid, secondKnownFailing)...)
+}
+
+// BatchVerifier verifies batches of signatures for distinct messages, reusing a single
+// secure random generator across batches when blst is enabled.
+type BatchVerifier struct {
+	blstVerifier *blst.BatchVerifier
+}
+
+// NewBatchVerifier creates a batch verifier which is safe for concurrent use.
+func NewBatchVerifier() *BatchVerifier {
+	if featureconfig.Get().EnableBlst {
+		return &BatchVerifier{blstVerifier: blst.NewBatchVerifier()}
+	}
+	return &BatchVerifier{}
+}
+
+// Verify multiple signatures for distinct messages securely.
+func (b *BatchVerifier) Verify(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey) (bool, error) {
+	if b.blstVerifier != nil {
+		return b.blstVerifier.Verify(sigs, msgs, pubKeys)
+	}
+	return VerifyMultipleSignatures(sigs, msgs, pubKeys)
+}
+
+// AggregationBuilder aggregates signatures as they are added, so streaming signatures
+// into an aggregate does not require collecting them all first. It is safe for concurrent use.
+type AggregationBuilder struct {
+	blstBuilder *blst.AggregationBuilder
+	lock        sync.Mutex
+	agg         common.Signature
+	err         error
+}
+
+// NewAggregationBuilder creates an empty aggregation builder.
+func NewAggregationBuilder() *AggregationBuilder {
+	if featureconfig.Get().EnableBlst {
+		return &AggregationBuilder{blstBuilder: blst.NewAggregationBuilder()}
+	}
+	return &AggregationBuilder{}
+}
+
+// Add folds a signature into the aggregate. Signatures which cannot be aggregated are
+// reported by Finalize.
+func (b *AggregationBuilder) Add(sig common.Signature) {
+	if b.blstBuilder != nil {
+		b.blstBuilder.Add(sig)
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	switch {
+	case b.err != nil:
+	case sig == nil:
+		b.err = errors.New("could not aggregate a nil signature")
+	case b.agg == nil:
+		b.agg = sig.Copy()
+	default:
+		b.agg, b.err = herumi.AggregateSignatures([]common.Signature{b.agg, sig})
+	}
+}
+
+// Finalize returns the aggregate of the signatures added so far, or ErrNoSignatures if
+// none were added.
+func (b *AggregationBuilder) Finalize() (common.Signature, error) {
+	if b.blstBuilder != nil {
+		return b.blstBuilder.Finalize()
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.agg == nil {
+		return nil, ErrNoSignatures
+	}
+	return b.agg.Copy(), nil
+}
+
+// CheckDomainSeparationTag returns an error if the BLS implementation in use does not
+// support the domain separation tag of the network config. It is meant to be called at
+// startup once the chain config is loaded, as herumi always uses its own tag, so a
+// network configured with another one would otherwise sign under the wrong ciphersuite.
+func CheckDomainSeparationTag() error {
+	if featureconfig.Get().EnableBlst {
+		return nil
+	}
+	if tag := params.BeaconConfig().BLSDomainSeparationTag; tag != herumi.DomainSeparationTag {
+		return errors.Errorf("domain separation tag %q is only supported with the blst implementation", tag)
+	}
+	return nil
+}
+
+// SetExtraEntropySource mixes an additional entropy source, such as a hardware RNG,
+// into the random coefficients used by VerifyMultipleSignatures.
+func SetExtraEntropySource(r io.Reader) error {
+	if featureconfig.Get().EnableBlst {
+		return blst.SetExtraEntropySource(r)
+	}
+	return errors.New("extra entropy sources are only supported by blst")
+}
+
+// SetVerificationTrace writes the inputs and result of every signature verification to w,
+// as one JSON encoded VerificationTrace per line, so that disagreements with the consensus
+// spec tests can be diffed against a reference implementation. Passing a nil writer
+// disables tracing, which is the default and costs nothing beyond a flag check.
+func SetVerificationTrace(w io.Writer) {
+	common.SetVerificationTrace(w)
+}
+
 // NewAggregateSignature creates a blank aggregate signature.
 func NewAggregateSignature() common.Signature {
 	if featureconfig.Get().EnableBlst {
@@ -118,3 +343,50 @@ func VerifyCompressed(signature, pub, msg []byte) bool {
 	}
 	return sig.Verify(pk, msg)
 }
+
+// VerifyCompressedBatch verifies independent triples of compressed signatures, public keys
+// and messages, whose messages differ so they can not be aggregated. With blst, the triples
+// are checked in a single pairing batch. It returns an error if the lengths differ.
+func VerifyCompressedBatch(sigs, pubs, msgs [][]byte) (bool, error) {
+	if featureconfig.Get().EnableBlst {
+		return blst.VerifyCompressedBatch(sigs, pubs, msgs)
+	}
+	if len(sigs) != len(pubs) || len(sigs) != len(msgs) {
+		return false, errors.Errorf("provided signatures, pubkeys and messages have differing lengths. S: %d, P: %d,M %d",
+			len(sigs), len(pubs), len(msgs))
+	}
+	// herumi only batches 32 byte messages, so each triple is verified on its own.
+	for i := range sigs {
+		if !VerifyCompressed(sigs[i], pubs[i], msgs[i]) {
+			return false, nil
+		}
+	}
+	return len(sigs) > 0, nil
+}
+
+// VerifyStrictRoot verifies a signature over a 32 byte signing root. Taking the root
+// as a fixed size array prevents callers from verifying over raw object bytes
+// instead of their hash tree root.
+func VerifyStrictRoot(pub PublicKey, root [32]byte, sig Signature) bool {
+	if pub == nil || sig == nil {
+		return false
+	}
+	return sig.Verify(pub, root[:])
+}
+
+// VerifyMixedAggregate verifies an aggregate signature over a single message against
+// public keys where each entry may either be an individual validator's key or a key
+// pre-aggregated from a stable subset of validators, such as a sync subcommittee.
+// Since an aggregate public key verifies like any other key, stable subsets only need
+// to be aggregated once instead of on every verification.
+func VerifyMixedAggregate(sig Signature, pubKeys []PublicKey, msg [32]byte) bool {
+	if sig == nil || len(pubKeys) == 0 {
+		return false
+	}
+	for _, pub := range pubKeys {
+		if pub == nil {
+			return false
+		}
+	}
+	return sig.FastAggregateVerify(pubKeys, msg)
+}
@@ -1,10 +1,12 @@
 package bls
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 )
 
@@ -50,6 +52,34 @@ func TestDisallowZeroPublicKeys(t *testing.T) {
 	})
 }
 
+func TestInfiniteSignature(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		priv, err := RandKey()
+		require.NoError(t, err)
+		sig := priv.Sign([]byte{'m', 's', 'g'})
+		require.Equal(t, false, sig.IsInfinite())
+		require.Equal(t, false, priv.PublicKey().IsInfinite())
+
+		// The infinite signature is read, and fails verification, by default.
+		infiniteSig, err := SignatureFromBytes(InfiniteSignature[:])
+		require.NoError(t, err)
+		require.Equal(t, true, infiniteSig.IsInfinite())
+		require.Equal(t, false, infiniteSig.Verify(priv.PublicKey(), []byte{'m', 's', 'g'}))
+		reset()
+
+		reset = featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst, RejectInfiniteSignatures: true})
+		_, err = SignatureFromBytes(InfiniteSignature[:])
+		require.Equal(t, ErrInfiniteSignature, err)
+		_, err = SignatureFromBytes(sig.Marshal())
+		require.NoError(t, err)
+		// The infinite public key is always rejected.
+		_, err = PublicKeyFromBytes(InfinitePublicKey[:])
+		require.Equal(t, ErrInfinitePubKey, err)
+		reset()
+	}
+}
+
 func TestDisallowZeroPublicKeys_AggregatePubkeys(t *testing.T) {
 	flags := &featureconfig.Flags{}
 
@@ -70,3 +100,295 @@ func TestDisallowZeroPublicKeys_AggregatePubkeys(t *testing.T) {
 		require.Equal(t, common.ErrInfinitePubKey, err)
 	})
 }
+
+func TestVerifyStrictRoot(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		priv, err := RandKey()
+		require.NoError(t, err)
+		otherPriv, err := RandKey()
+		require.NoError(t, err)
+		root := [32]byte{'r', 'o', 'o', 't'}
+		sig := priv.Sign(root[:])
+
+		require.Equal(t, true, VerifyStrictRoot(priv.PublicKey(), root, sig))
+		require.Equal(t, false, VerifyStrictRoot(otherPriv.PublicKey(), root, sig))
+		require.Equal(t, false, VerifyStrictRoot(priv.PublicKey(), [32]byte{'b', 'a', 'd'}, sig))
+		require.Equal(t, false, VerifyStrictRoot(nil, root, sig))
+		require.Equal(t, false, VerifyStrictRoot(priv.PublicKey(), root, nil))
+		reset()
+	}
+}
+
+func TestBatchVerifier(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		verifier := NewBatchVerifier()
+		sigs := make([][]byte, 4)
+		msgs := make([][32]byte, 4)
+		pubKeys := make([]PublicKey, 4)
+		for i := 0; i < len(sigs); i++ {
+			priv, err := RandKey()
+			require.NoError(t, err)
+			msgs[i] = [32]byte{'m', 's', 'g', byte(i)}
+			sigs[i] = priv.Sign(msgs[i][:]).Marshal()
+			pubKeys[i] = priv.PublicKey()
+		}
+		verified, err := verifier.Verify(sigs, msgs, pubKeys)
+		require.NoError(t, err)
+		require.Equal(t, true, verified)
+
+		msgs[0] = [32]byte{'b', 'a', 'd'}
+		verified, err = verifier.Verify(sigs, msgs, pubKeys)
+		require.NoError(t, err)
+		require.Equal(t, false, verified)
+		reset()
+	}
+}
+
+func TestVerifyMultipleSignaturesReportFailures(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		sigs := make([][]byte, 8)
+		msgs := make([][32]byte, 8)
+		pubKeys := make([]PublicKey, 8)
+		labels := make([]string, 8)
+		for i := 0; i < len(sigs); i++ {
+			priv, err := RandKey()
+			require.NoError(t, err)
+			msgs[i] = [32]byte{'m', 's', 'g', byte(i)}
+			sigs[i] = priv.Sign(msgs[i][:]).Marshal()
+			pubKeys[i] = priv.PublicKey()
+			labels[i] = fmt.Sprintf("validator %d slot %d", i, 5+i/4)
+		}
+		verified, failedIndices, failedLabels, err := VerifyMultipleSignaturesReportFailures(sigs, msgs, pubKeys, labels)
+		require.NoError(t, err)
+		require.Equal(t, true, verified)
+		require.Equal(t, 0, len(failedIndices))
+		require.Equal(t, 0, len(failedLabels))
+
+		// Corrupt entry 2 with a signature over another message,
+		// and entry 5 with bytes which are not a signature at all.
+		sigs[2] = sigs[3]
+		sigs[5] = make([]byte, len(sigs[5]))
+		verified, failedIndices, failedLabels, err = VerifyMultipleSignaturesReportFailures(sigs, msgs, pubKeys, labels)
+		require.NoError(t, err)
+		require.Equal(t, false, verified)
+		require.DeepEqual(t, []int{2, 5}, failedIndices)
+		require.DeepEqual(t, []string{labels[2], labels[5]}, failedLabels)
+
+		// Labels are optional.
+		verified, failedIndices, failedLabels, err = VerifyMultipleSignaturesReportFailures(sigs, msgs, pubKeys, nil)
+		require.NoError(t, err)
+		require.Equal(t, false, verified)
+		require.DeepEqual(t, []int{2, 5}, failedIndices)
+		require.Equal(t, 0, len(failedLabels))
+
+		verified, failedIndices, _, err = VerifyMultipleSignaturesReportFailures(nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, false, verified)
+		require.Equal(t, 0, len(failedIndices))
+
+		_, _, _, err = VerifyMultipleSignaturesReportFailures(sigs, msgs, pubKeys, labels[:2])
+		require.ErrorContains(t, "provided 2 labels for 8 signatures", err)
+		_, _, _, err = VerifyMultipleSignaturesReportFailures(sigs, msgs[:4], pubKeys, nil)
+		require.ErrorContains(t, "differing lengths", err)
+		reset()
+	}
+}
+
+func TestCheckDomainSeparationTag(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	require.NoError(t, CheckDomainSeparationTag())
+
+	cfg := params.BeaconConfig().Copy()
+	cfg.BLSDomainSeparationTag = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_PRYSM_TESTNET_"
+	params.OverrideBeaconConfig(cfg)
+	require.ErrorContains(t, "only supported with the blst implementation", CheckDomainSeparationTag())
+
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: true})
+	defer reset()
+	require.NoError(t, CheckDomainSeparationTag())
+}
+
+func TestVerifyMixedAggregate(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		msg := [32]byte{'m', 's', 'g'}
+		sigs := make([]Signature, 5)
+		pubKeys := make([]PublicKey, 5)
+		for i := 0; i < len(sigs); i++ {
+			priv, err := RandKey()
+			require.NoError(t, err)
+			sigs[i] = priv.Sign(msg[:])
+			pubKeys[i] = priv.PublicKey()
+		}
+		aggSig := MustAggregateSignatures(sigs)
+		// Pre-aggregate the first three keys and keep the rest individual.
+		preAggregated, err := AggregatePublicKeys([][]byte{
+			pubKeys[0].Marshal(), pubKeys[1].Marshal(), pubKeys[2].Marshal(),
+		})
+		require.NoError(t, err)
+		mixed := []PublicKey{preAggregated, pubKeys[3], pubKeys[4]}
+
+		require.Equal(t, true, VerifyMixedAggregate(aggSig, mixed, msg))
+		require.Equal(t, true, VerifyMixedAggregate(aggSig, pubKeys, msg))
+		require.Equal(t, false, VerifyMixedAggregate(aggSig, mixed[:2], msg))
+		require.Equal(t, false, VerifyMixedAggregate(aggSig, mixed, [32]byte{'b', 'a', 'd'}))
+		require.Equal(t, false, VerifyMixedAggregate(aggSig, []PublicKey{preAggregated, nil}, msg))
+		require.Equal(t, false, VerifyMixedAggregate(aggSig, nil, msg))
+		require.Equal(t, false, VerifyMixedAggregate(nil, mixed, msg))
+		reset()
+	}
+}
+
+func TestAggregateSignatures(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		msg := [32]byte{'m', 's', 'g'}
+		sigs := make([]Signature, 3)
+		pubKeys := make([]PublicKey, 3)
+		for i := 0; i < len(sigs); i++ {
+			priv, err := RandKey()
+			require.NoError(t, err)
+			sigs[i] = priv.Sign(msg[:])
+			pubKeys[i] = priv.PublicKey()
+		}
+
+		_, err := AggregateSignatures(nil)
+		require.ErrorContains(t, ErrNoSignatures.Error(), err)
+		_, err = AggregateSignatures([]Signature{})
+		require.ErrorContains(t, ErrNoSignatures.Error(), err)
+
+		single, err := AggregateSignatures(sigs[:1])
+		require.NoError(t, err)
+		require.Equal(t, true, single.Equals(sigs[0]), "Aggregate of one signature is not that signature")
+		require.Equal(t, true, single.FastAggregateVerify(pubKeys[:1], msg))
+
+		multi, err := AggregateSignatures(sigs)
+		require.NoError(t, err)
+		require.Equal(t, true, multi.FastAggregateVerify(pubKeys, msg))
+		require.Equal(t, false, multi.FastAggregateVerify(pubKeys[:2], msg))
+		require.Equal(t, true, MustAggregateSignatures(sigs).Equals(multi))
+		reset()
+	}
+}
+
+func TestAggregationBuilder(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		msg := [32]byte{'m', 's', 'g'}
+		sigs := make([]Signature, 3)
+		for i := 0; i < len(sigs); i++ {
+			priv, err := RandKey()
+			require.NoError(t, err)
+			sigs[i] = priv.Sign(msg[:])
+		}
+
+		builder := NewAggregationBuilder()
+		_, err := builder.Finalize()
+		require.ErrorContains(t, ErrNoSignatures.Error(), err)
+		for _, sig := range sigs {
+			builder.Add(sig)
+		}
+		aggregate, err := builder.Finalize()
+		require.NoError(t, err)
+		require.Equal(t, true, aggregate.Equals(MustAggregateSignatures(sigs)), "Aggregate differs from AggregateSignatures")
+
+		builder.Add(nil)
+		_, err = builder.Finalize()
+		require.ErrorContains(t, "nil", err)
+		reset()
+	}
+}
+
+func TestMustAggregateSignatures_PanicsOnEmptyInput(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic aggregating no signatures")
+		}
+	}()
+	MustAggregateSignatures(nil)
+}
+
+func TestVerifyCompressedBatch(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		sigs := make([][]byte, 3)
+		pubs := make([][]byte, 3)
+		msgs := make([][]byte, 3)
+		for i := 0; i < len(sigs); i++ {
+			priv, err := RandKey()
+			require.NoError(t, err)
+			// Messages differ, so the signatures can not be aggregated.
+			msgs[i] = []byte{'m', 's', 'g', byte(i)}
+			sigs[i] = priv.Sign(msgs[i]).Marshal()
+			pubs[i] = priv.PublicKey().Marshal()
+		}
+
+		verified, err := VerifyCompressedBatch(sigs, pubs, msgs)
+		require.NoError(t, err)
+		require.Equal(t, true, verified, "Valid triples did not verify")
+
+		// A valid signature of another triple's message.
+		corrupted := [][]byte{sigs[0], sigs[2], sigs[2]}
+		verified, err = VerifyCompressedBatch(corrupted, pubs, msgs)
+		require.NoError(t, err)
+		require.Equal(t, false, verified, "Corrupted triple verified")
+		malformed := [][]byte{sigs[0], {1, 2, 3}, sigs[2]}
+		verified, err = VerifyCompressedBatch(malformed, pubs, msgs)
+		require.NoError(t, err)
+		require.Equal(t, false, verified, "Malformed signature verified")
+
+		_, err = VerifyCompressedBatch(sigs, pubs[:2], msgs)
+		require.ErrorContains(t, "differing lengths", err)
+		verified, err = VerifyCompressedBatch(nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, false, verified, "Empty batch verified")
+		reset()
+	}
+}
+
+func TestSignatureEquals_SkipBLSVerify(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		priv, err := RandKey()
+		require.NoError(t, err)
+		sig := priv.Sign([]byte("hello"))
+		other := priv.Sign([]byte("other"))
+		require.Equal(t, false, sig.Equals(other))
+		reset()
+
+		reset = featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst, SkipBLSVerify: true})
+		require.Equal(t, true, sig.Equals(other))
+		reset()
+	}
+}
+
+func TestSignature_SkipBLSVerifyPlaceholders(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst, SkipBLSVerify: true})
+		priv, err := RandKey()
+		require.NoError(t, err)
+		placeholder := make([]byte, 96)
+		signed := priv.Sign([]byte("hello"))
+		read, err := SignatureFromBytes([]byte{'b', 'a', 'd'})
+		require.NoError(t, err)
+
+		copied := read.Copy()
+		require.DeepEqual(t, placeholder, copied.Marshal())
+		aggregated := MustAggregateSignatures([]Signature{signed, copied, read})
+		require.NotNil(t, aggregated)
+		require.Equal(t, 3, aggregated.ContributorCount())
+		require.DeepEqual(t, placeholder, aggregated.Marshal())
+		require.DeepEqual(t, placeholder, aggregated.Copy().Marshal())
+		require.Equal(t, true, aggregated.Verify(priv.PublicKey(), []byte("hello")))
+		reset()
+
+		// Placeholders stay safe to marshal once verification is enabled again.
+		reset = featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		require.DeepEqual(t, placeholder, aggregated.Marshal())
+		require.DeepEqual(t, placeholder, aggregated.Copy().Marshal())
+		reset()
+	}
+}
@@ -0,0 +1,69 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+// AggregationBuilder aggregates signatures as they are added, rather than collecting
+// them all before calling AggregateSignatures, so streaming thousands of signatures into
+// an aggregate takes constant memory. It is safe for concurrent use.
+type AggregationBuilder struct {
+	lock         sync.Mutex
+	agg          *blstAggregateSignature
+	added        int
+	contributors int
+	err          error
+}
+
+// NewAggregationBuilder creates an empty aggregation builder.
+func NewAggregationBuilder() *AggregationBuilder {
+	return &AggregationBuilder{}
+}
+
+// Add folds a signature into the aggregate. A signature which is not a blst signature
+// cannot be aggregated, which is reported by Finalize.
+func (b *AggregationBuilder) Add(sig common.Signature) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.added++
+	s, ok := sig.(*Signature)
+	if !ok || s == nil {
+		if b.err == nil {
+			b.err = errors.New("could not aggregate a nil or non blst signature")
+		}
+		return
+	}
+	b.contributors += s.contributors
+	if featureconfig.Get().SkipBLSVerify {
+		// Signatures read with SkipBLSVerify set are placeholders without a point.
+		return
+	}
+	if b.agg == nil {
+		b.agg = new(blstAggregateSignature)
+	}
+	b.agg.Add(s.s)
+}
+
+// Finalize returns the aggregate of the signatures added so far. There is no aggregate
+// of no signatures, so it returns an error if none were added.
+func (b *AggregationBuilder) Finalize() (common.Signature, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.added == 0 {
+		return nil, common.ErrNoSignatures
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.agg == nil {
+		return &Signature{contributors: b.contributors}, nil
+	}
+	return &Signature{s: b.agg.ToAffine(), contributors: b.contributors}, nil
+}
@@ -0,0 +1,57 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestAggregationBuilder_Concurrent(t *testing.T) {
+	const numSigs, numWorkers = 1000, 8
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	sigs := make([]common.Signature, numSigs)
+	pubKeys := make([]common.PublicKey, numSigs)
+	for i := 0; i < numSigs; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		sigs[i] = priv.Sign(msg[:])
+		pubKeys[i] = priv.PublicKey()
+	}
+
+	builder := NewAggregationBuilder()
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < numSigs; i += numWorkers {
+				builder.Add(sigs[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	aggregate, err := builder.Finalize()
+	require.NoError(t, err)
+	serial, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	assert.DeepEqual(t, serial.Marshal(), aggregate.Marshal())
+	assert.Equal(t, numSigs, aggregate.(*Signature).contributors)
+	assert.Equal(t, true, aggregate.FastAggregateVerify(pubKeys, msg))
+}
+
+func TestAggregationBuilder_Finalize(t *testing.T) {
+	_, err := NewAggregationBuilder().Finalize()
+	assert.Equal(t, common.ErrNoSignatures, err)
+
+	builder := NewAggregationBuilder()
+	builder.Add(nil)
+	_, err = builder.Finalize()
+	assert.ErrorContains(t, "could not aggregate a nil or non blst signature", err)
+}
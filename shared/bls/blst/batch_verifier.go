@@ -0,0 +1,41 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"sync"
+
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/rand"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// BatchVerifier verifies batches of signatures with the same algorithm as
+// VerifyMultipleSignatures, but reuses a single secure random generator across
+// batches rather than setting up a new one per call. It is safe for concurrent use.
+type BatchVerifier struct {
+	lock     sync.Mutex
+	randFunc func(*blst.Scalar)
+}
+
+// NewBatchVerifier creates a batch verifier backed by a cryptographically
+// secure random generator.
+func NewBatchVerifier() *BatchVerifier {
+	return &BatchVerifier{
+		randFunc: newRandFunc(rand.NewGenerator()),
+	}
+}
+
+// Verify a non-singular set of signatures and its respective pubkeys and messages.
+func (b *BatchVerifier) Verify(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey) (bool, error) {
+	return verifyMultipleSignatures(sigs, msgs, pubKeys, b.lockedRandFunc)
+}
+
+// The underlying generator keeps internal read state, so scalar
+// generation is serialized across concurrent batches.
+func (b *BatchVerifier) lockedRandFunc(scalar *blst.Scalar) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.randFunc(scalar)
+}
@@ -0,0 +1,65 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func generateBatch(t *testing.T, n int) ([][]byte, [][32]byte, []common.PublicKey) {
+	pubkeys := make([]common.PublicKey, n)
+	sigs := make([][]byte, n)
+	msgs := make([][32]byte, n)
+	for i := 0; i < n; i++ {
+		msgs[i] = [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys[i] = priv.PublicKey()
+		sigs[i] = priv.Sign(msgs[i][:]).Marshal()
+	}
+	return sigs, msgs, pubkeys
+}
+
+func TestBatchVerifier_Verify(t *testing.T) {
+	verifier := NewBatchVerifier()
+	sigs, msgs, pubkeys := generateBatch(t, 16)
+	for i := 0; i < 3; i++ {
+		verified, err := verifier.Verify(sigs, msgs, pubkeys)
+		require.NoError(t, err)
+		assert.Equal(t, true, verified, "Batch did not verify")
+	}
+
+	// A batch with a signature over the wrong message must not verify.
+	msgs[3] = [32]byte{'b', 'a', 'd'}
+	verified, err := verifier.Verify(sigs, msgs, pubkeys)
+	require.NoError(t, err)
+	assert.Equal(t, false, verified, "Invalid batch verified")
+
+	_, err = verifier.Verify(sigs, msgs[:2], pubkeys)
+	assert.ErrorContains(t, "differing lengths", err)
+}
+
+func TestBatchVerifier_ConcurrentUse(t *testing.T) {
+	verifier := NewBatchVerifier()
+	sigs, msgs, pubkeys := generateBatch(t, 8)
+	var wg sync.WaitGroup
+	results := make([]bool, 8)
+	for i := 0; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			verified, err := verifier.Verify(sigs, msgs, pubkeys)
+			results[i] = err == nil && verified
+		}(i)
+	}
+	wg.Wait()
+	for i, ok := range results {
+		assert.Equal(t, true, ok, "Batch %d did not verify", i)
+	}
+}
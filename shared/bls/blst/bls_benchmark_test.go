@@ -4,6 +4,8 @@
 package blst_test
 
 import (
+	"fmt"
+	"runtime"
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/bls/blst"
@@ -41,7 +43,8 @@ func BenchmarkSignature_AggregateVerify(b *testing.B) {
 		sigs = append(sigs, sig)
 		msgs = append(msgs, msg)
 	}
-	aggregated := blst.Aggregate(sigs)
+	aggregated, err := blst.Aggregate(sigs)
+	require.NoError(b, err)
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -52,6 +55,31 @@ func BenchmarkSignature_AggregateVerify(b *testing.B) {
 	}
 }
 
+func BenchmarkSignatureFromBytes(b *testing.B) {
+	sk, err := blst.RandKey()
+	require.NoError(b, err)
+	sig := sk.Sign([]byte("Some msg")).Marshal()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := blst.SignatureFromBytes(sig); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSignaturesFromBytes(b *testing.B) {
+	sigs, _, _ := generateBenchmarkBatch(b, 1000)
+	for _, workers := range []int{1, runtime.NumCPU()} {
+		b.Run(fmt.Sprintf("Workers_%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := blst.SignaturesFromBytes(sigs, workers)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
 func BenchmarkSecretKey_Marshal(b *testing.B) {
 	key, err := blst.RandKey()
 	require.NoError(b, err)
@@ -63,3 +91,117 @@ func BenchmarkSecretKey_Marshal(b *testing.B) {
 		_ = err
 	}
 }
+
+func generateBenchmarkBatch(b *testing.B, n int) ([][]byte, [][32]byte, []common.PublicKey) {
+	pubkeys := make([]common.PublicKey, n)
+	sigs := make([][]byte, n)
+	msgs := make([][32]byte, n)
+	for i := 0; i < n; i++ {
+		msgs[i] = [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := blst.RandKey()
+		require.NoError(b, err)
+		pubkeys[i] = priv.PublicKey()
+		sigs[i] = priv.Sign(msgs[i][:]).Marshal()
+	}
+	return sigs, msgs, pubkeys
+}
+
+func BenchmarkVerifyMultipleSignatures(b *testing.B) {
+	sigs, msgs, pubkeys := generateBenchmarkBatch(b, 128)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verified, err := blst.VerifyMultipleSignatures(sigs, msgs, pubkeys)
+		require.NoError(b, err)
+		if !verified {
+			b.Fatal("could not verify batch")
+		}
+	}
+}
+
+func BenchmarkBatchVerifier_Verify(b *testing.B) {
+	sigs, msgs, pubkeys := generateBenchmarkBatch(b, 128)
+	verifier := blst.NewBatchVerifier()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verified, err := verifier.Verify(sigs, msgs, pubkeys)
+		require.NoError(b, err)
+		if !verified {
+			b.Fatal("could not verify batch")
+		}
+	}
+}
+
+func BenchmarkVerifyMultipleSignaturesParallel(b *testing.B) {
+	for _, n := range []int{100, 1024, 5000} {
+		sigs, msgs, pubkeys := generateBenchmarkBatch(b, n)
+		b.Run(fmt.Sprintf("Serial_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				verified, err := blst.VerifyMultipleSignatures(sigs, msgs, pubkeys)
+				require.NoError(b, err)
+				if !verified {
+					b.Fatal("could not verify batch")
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Parallel_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				verified, err := blst.VerifyMultipleSignaturesParallel(sigs, msgs, pubkeys, runtime.GOMAXPROCS(0))
+				require.NoError(b, err)
+				if !verified {
+					b.Fatal("could not verify batch")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAggregateMultiplePubkeys(b *testing.B) {
+	_, _, pubkeys := generateBenchmarkBatch(b, 128)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := blst.AggregateMultiplePubkeys(pubkeys)
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkPublicKey_AggregateChained(b *testing.B) {
+	_, _, pubkeys := generateBenchmarkBatch(b, 128)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		aggregated := pubkeys[0].Copy()
+		for _, pubkey := range pubkeys[1:] {
+			aggregated = aggregated.Aggregate(pubkey)
+		}
+	}
+}
+
+func BenchmarkSecretKey_SignBatch(b *testing.B) {
+	sk, err := blst.RandKey()
+	require.NoError(b, err)
+	msgs := make([][]byte, 16)
+	for i := range msgs {
+		msgs[i] = []byte{'s', 'i', 'g', 'n', 'e', 'd', byte(i)}
+	}
+
+	b.Run("Loop", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sigs := make([]common.Signature, len(msgs))
+			for j, msg := range msgs {
+				sigs[j] = sk.Sign(msg)
+			}
+		}
+	})
+	b.Run("Batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sk.SignBatch(msgs)
+		}
+	})
+}
@@ -5,4 +5,7 @@
 // This implementation uses the library written by Supranational, blst.
 //
 // Only linux_amd64 is supported at the moment.
+//
+// Keys are derived from a seed with bls.DeriveMasterSK, which shares the EIP-2333
+// derivation with the herumi backend.
 package blst
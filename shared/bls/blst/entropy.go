@@ -0,0 +1,102 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/shared/rand"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+var (
+	extraEntropy     io.Reader
+	extraEntropyLock sync.Mutex
+
+	// extraEntropyReadFailures tracks the batches verified without extra entropy
+	// because the extra entropy source could not be read.
+	extraEntropyReadFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bls_extra_entropy_read_failures",
+		Help: "The number of batch verifications for which the extra entropy source could not be read.",
+	})
+)
+
+// SetExtraEntropySource configures an additional source of randomness, such as a
+// hardware RNG device, which is mixed into the random coefficients used by
+// VerifyMultipleSignatures. The source is sampled before use to ensure it is readable
+// and does not return constant output. Passing a nil reader restores the default of
+// only using the base generator.
+func SetExtraEntropySource(r io.Reader) error {
+	if r != nil {
+		if err := validateEntropySource(r); err != nil {
+			return err
+		}
+	}
+	extraEntropyLock.Lock()
+	defer extraEntropyLock.Unlock()
+	extraEntropy = r
+	return nil
+}
+
+func validateEntropySource(r io.Reader) error {
+	first := make([]byte, scalarBytes)
+	second := make([]byte, scalarBytes)
+	if _, err := io.ReadFull(r, first); err != nil {
+		return errors.Wrap(err, "could not read from entropy source")
+	}
+	if _, err := io.ReadFull(r, second); err != nil {
+		return errors.Wrap(err, "could not read from entropy source")
+	}
+	if bytes.Equal(first, second) {
+		return errors.New("entropy source returned repeated output")
+	}
+	return nil
+}
+
+// newRandFunc returns the function used to generate the random scalars of a single
+// batch verification. A seed is read from the extra entropy source, if any, once per
+// batch and expanded with SHA-256 into bytes which are XOR'd into the output of the
+// base generator, so the result is never less random than the base generator alone
+// and the source is not read for every scalar. A failing extra source leaves the base
+// output untouched.
+func newRandFunc(randGen *rand.Rand) func(*blst.Scalar) {
+	seed, hasSeed := readExtraEntropySeed()
+	var counter uint64
+	return func(scalar *blst.Scalar) {
+		var rbytes [scalarBytes]byte
+		randGen.Read(rbytes[:])
+		if hasSeed {
+			var input [scalarBytes + 8]byte
+			copy(input[:], seed[:])
+			binary.LittleEndian.PutUint64(input[scalarBytes:], counter)
+			counter++
+			extra := sha256.Sum256(input[:])
+			for i := range rbytes {
+				rbytes[i] ^= extra[i]
+			}
+		}
+		scalar.FromBEndian(rbytes[:])
+	}
+}
+
+func readExtraEntropySeed() ([scalarBytes]byte, bool) {
+	var seed [scalarBytes]byte
+	extraEntropyLock.Lock()
+	defer extraEntropyLock.Unlock()
+	if extraEntropy == nil {
+		return seed, false
+	}
+	if _, err := io.ReadFull(extraEntropy, seed[:]); err != nil {
+		extraEntropyReadFailures.Inc()
+		return seed, false
+	}
+	return seed, true
+}
@@ -0,0 +1,118 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"bytes"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/rand"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// counterReader is a deterministic stand-in for a hardware entropy source.
+type counterReader struct {
+	next  byte
+	reads int
+}
+
+func (c *counterReader) Read(p []byte) (int, error) {
+	c.reads++
+	for i := range p {
+		p[i] = c.next
+		c.next++
+	}
+	return len(p), nil
+}
+
+func TestSetExtraEntropySource_MixesIntoCoefficients(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetExtraEntropySource(nil))
+	}()
+
+	base := new(blst.Scalar)
+	newRandFunc(mrand.New(mrand.NewSource(1)))(base)
+
+	require.NoError(t, SetExtraEntropySource(&counterReader{}))
+	mixed := new(blst.Scalar)
+	newRandFunc(mrand.New(mrand.NewSource(1)))(mixed)
+
+	assert.Equal(t, false, bytes.Equal(base.Serialize(), mixed.Serialize()), "Extra entropy was not mixed in")
+}
+
+func TestSetExtraEntropySource_ReadOncePerBatch(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetExtraEntropySource(nil))
+	}()
+	source := &counterReader{}
+	require.NoError(t, SetExtraEntropySource(source))
+	validationReads := source.reads
+
+	randFunc := newRandFunc(rand.NewDeterministicGenerator())
+	first := new(blst.Scalar)
+	randFunc(first)
+	second := new(blst.Scalar)
+	randFunc(second)
+	assert.Equal(t, validationReads+1, source.reads)
+	assert.Equal(t, false, bytes.Equal(first.Serialize(), second.Serialize()), "Repeated coefficient")
+}
+
+func TestSetExtraEntropySource_ReadFailure(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetExtraEntropySource(nil))
+	}()
+	// The source only holds enough bytes to pass validation.
+	sample := make([]byte, 2*scalarBytes)
+	sample[0] = 1
+	require.NoError(t, SetExtraEntropySource(bytes.NewReader(sample)))
+
+	failures := testutil.ToFloat64(extraEntropyReadFailures)
+	mixed := new(blst.Scalar)
+	newRandFunc(mrand.New(mrand.NewSource(1)))(mixed)
+	assert.Equal(t, failures+1, testutil.ToFloat64(extraEntropyReadFailures))
+
+	// The base generator output is used as is.
+	require.NoError(t, SetExtraEntropySource(nil))
+	base := new(blst.Scalar)
+	newRandFunc(mrand.New(mrand.NewSource(1)))(base)
+	assert.DeepEqual(t, base.Serialize(), mixed.Serialize())
+}
+
+func TestSetExtraEntropySource_RejectsBadSources(t *testing.T) {
+	assert.ErrorContains(t, "could not read from entropy source", SetExtraEntropySource(bytes.NewReader([]byte{1})))
+	assert.ErrorContains(t, "repeated output", SetExtraEntropySource(bytes.NewReader(make([]byte, 2*scalarBytes))))
+}
+
+func TestMultipleSignatureVerification_ExtraEntropy(t *testing.T) {
+	require.NoError(t, SetExtraEntropySource(&counterReader{}))
+	defer func() {
+		require.NoError(t, SetExtraEntropySource(nil))
+	}()
+
+	pubkeys := make([]common.PublicKey, 0, 10)
+	sigs := make([][]byte, 0, 10)
+	var msgs [][32]byte
+	for i := 0; i < 10; i++ {
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]).Marshal())
+		msgs = append(msgs, msg)
+	}
+	verify, err := VerifyMultipleSignatures(sigs, msgs, pubkeys)
+	require.NoError(t, err)
+	assert.Equal(t, true, verify, "Signature did not verify")
+
+	// A bad signature must still be caught with the mixed coefficients.
+	sigs[0], sigs[1] = sigs[1], sigs[0]
+	verify, err = VerifyMultipleSignatures(sigs, msgs, pubkeys)
+	require.NoError(t, err)
+	assert.Equal(t, false, verify, "Signature verified with swapped signatures")
+}
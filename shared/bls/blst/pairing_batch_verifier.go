@@ -0,0 +1,96 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/rand"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// BLST_SUCCESS in blst's error codes, which the go bindings do not export.
+const blstSuccess = 0
+
+// PairingBatchVerifier verifies a batch of signatures by accumulating the pairing of
+// each signature as it is added, rather than collecting the whole batch before verifying
+// it like VerifyMultipleSignatures. This suits batches which are built incrementally,
+// such as the signatures of a block whose entries mix repeated and distinct messages.
+//
+// Every signature and public key is multiplied by a random scalar before it is
+// accumulated, so invalid signatures cannot cancel each other out. A verifier verifies
+// a single batch and is not safe for concurrent use.
+type PairingBatchVerifier struct {
+	pairing  blst.Pairing
+	randFunc func(*blst.Scalar)
+	count    int
+	err      error
+	done     bool
+	verified bool
+}
+
+// NewPairingBatchVerifier creates an empty batch backed by a cryptographically
+// secure random generator.
+func NewPairingBatchVerifier() *PairingBatchVerifier {
+	return &PairingBatchVerifier{
+		pairing:  blst.PairingCtx(true /* hash */, signingDST()),
+		randFunc: newRandFunc(rand.NewGenerator()),
+	}
+}
+
+// AddSignature accumulates the pairing of a signature over a message by a public key.
+// The signature is group checked. Once a signature could not be added, the batch
+// fails to verify.
+func (p *PairingBatchVerifier) AddSignature(pubKey common.PublicKey, msg [32]byte, sig []byte) error {
+	if p.done {
+		return errors.New("batch has already been verified")
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if featureconfig.Get().SkipBLSVerify {
+		p.count++
+		return nil
+	}
+	pub, ok := pubKey.(*PublicKey)
+	if !ok || pub == nil || pub.p == nil {
+		p.err = errors.Errorf("public key of signature %d is not a valid blst public key", p.count)
+		return p.err
+	}
+	// Uncompress rejects points outside of G2, so the signature is in the subgroup here.
+	rawSig := new(blstSignature).Uncompress(sig)
+	if rawSig == nil {
+		p.err = errors.Errorf("could not unmarshal bytes of signature %d", p.count)
+		return p.err
+	}
+	var scalar blst.Scalar
+	p.randFunc(&scalar)
+	if blst.PairingMulNAggregatePkInG1(p.pairing, pub.p, rawSig, &scalar, randBitsEntropy, msg[:]) != blstSuccess {
+		p.err = errors.Errorf("could not accumulate signature %d", p.count)
+		return p.err
+	}
+	p.count++
+	return nil
+}
+
+// Verify reports whether every signature added to the batch is valid. An empty batch
+// does not verify. The batch cannot be added to once verified, and verifying it again
+// returns the same result.
+func (p *PairingBatchVerifier) Verify() bool {
+	if p.done {
+		return p.verified
+	}
+	p.done = true
+	switch {
+	case p.err != nil || p.count == 0:
+		p.verified = false
+	case featureconfig.Get().SkipBLSVerify:
+		p.verified = true
+	default:
+		blst.PairingCommit(p.pairing)
+		p.verified = blst.PairingFinalVerify(p.pairing, nil)
+	}
+	return p.verified
+}
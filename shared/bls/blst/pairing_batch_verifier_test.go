@@ -0,0 +1,105 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+// Generates a batch like the signatures of a block, where groups of signers
+// sign the same message alongside signers of distinct messages.
+func generateCompositeBatch(t testing.TB, groups, groupSize int) ([][]byte, [][32]byte, []common.PublicKey) {
+	var sigs [][]byte
+	var msgs [][32]byte
+	var pubkeys []common.PublicKey
+	for g := 0; g < groups; g++ {
+		for i := 0; i < groupSize; i++ {
+			// The first member of each group signs a distinct message.
+			msg := [32]byte{'g', 'r', 'o', 'u', 'p', byte(g)}
+			if i == 0 {
+				msg = [32]byte{'d', 'i', 's', 't', 'i', 'n', 'c', 't', byte(g)}
+			}
+			priv, err := RandKey()
+			require.NoError(t, err)
+			sigs = append(sigs, priv.Sign(msg[:]).Marshal())
+			msgs = append(msgs, msg)
+			pubkeys = append(pubkeys, priv.PublicKey())
+		}
+	}
+	return sigs, msgs, pubkeys
+}
+
+func verifyPairingBatch(t testing.TB, sigs [][]byte, msgs [][32]byte, pubkeys []common.PublicKey) bool {
+	verifier := NewPairingBatchVerifier()
+	for i := range sigs {
+		require.NoError(t, verifier.AddSignature(pubkeys[i], msgs[i], sigs[i]))
+	}
+	return verifier.Verify()
+}
+
+func TestPairingBatchVerifier_MatchesVerifyMultipleSignatures(t *testing.T) {
+	sigs, msgs, pubkeys := generateCompositeBatch(t, 4, 4)
+	expected, err := VerifyMultipleSignatures(sigs, msgs, pubkeys)
+	require.NoError(t, err)
+	require.Equal(t, true, expected)
+	assert.Equal(t, expected, verifyPairingBatch(t, sigs, msgs, pubkeys))
+
+	// Swapping the signatures of two signers of the same message invalidates both.
+	sigs[1], sigs[2] = sigs[2], sigs[1]
+	expected, err = VerifyMultipleSignatures(sigs, msgs, pubkeys)
+	require.NoError(t, err)
+	require.Equal(t, false, expected)
+	assert.Equal(t, expected, verifyPairingBatch(t, sigs, msgs, pubkeys))
+}
+
+func TestPairingBatchVerifier_Verify(t *testing.T) {
+	sigs, msgs, pubkeys := generateBatch(t, 2)
+
+	verifier := NewPairingBatchVerifier()
+	assert.Equal(t, false, verifier.Verify(), "Empty batch verified")
+	assert.ErrorContains(t, "already been verified", verifier.AddSignature(pubkeys[0], msgs[0], sigs[0]))
+
+	verifier = NewPairingBatchVerifier()
+	require.NoError(t, verifier.AddSignature(pubkeys[0], msgs[0], sigs[0]))
+	assert.ErrorContains(t, "could not unmarshal bytes of signature 1", verifier.AddSignature(pubkeys[1], msgs[1], []byte{'b', 'a', 'd'}))
+	// The batch keeps failing once a signature could not be added.
+	assert.ErrorContains(t, "signature 1", verifier.AddSignature(pubkeys[1], msgs[1], sigs[1]))
+	assert.Equal(t, false, verifier.Verify(), "Batch with an invalid signature verified")
+
+	verifier = NewPairingBatchVerifier()
+	assert.ErrorContains(t, "not a valid blst public key", verifier.AddSignature(nil, msgs[0], sigs[0]))
+
+	verifier = NewPairingBatchVerifier()
+	require.NoError(t, verifier.AddSignature(pubkeys[0], msgs[0], sigs[0]))
+	assert.Equal(t, true, verifier.Verify())
+	assert.Equal(t, true, verifier.Verify(), "Result changed when verifying again")
+}
+
+func BenchmarkPairingBatchVerifier_CompositeSet(b *testing.B) {
+	sigs, msgs, pubkeys := generateCompositeBatch(b, 16, 8)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !verifyPairingBatch(b, sigs, msgs, pubkeys) {
+			b.Fatal("could not verify batch")
+		}
+	}
+}
+
+func BenchmarkVerifyMultipleSignatures_CompositeSet(b *testing.B) {
+	sigs, msgs, pubkeys := generateCompositeBatch(b, 16, 8)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verified, err := VerifyMultipleSignatures(sigs, msgs, pubkeys)
+		require.NoError(b, err)
+		if !verified {
+			b.Fatal("could not verify batch")
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+// The domain separation tag of proofs of possession in the POP ciphersuite. It differs
+// from the tag used for signing, so a signature over the bytes of a public key can never
+// be passed off as a proof of possession of its secret key.
+var popDST = []byte("BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+// PopProve produces a proof of possession of a secret key, which defends aggregate
+// verification against rogue public key attacks. It returns nil for a destroyed key.
+//
+// In IETF draft BLS specification:
+// PopProve(SK) -> proof: an algorithm that generates a proof of possession
+//      for the public key corresponding to secret key SK.
+func PopProve(secretKey common.SecretKey) common.Signature {
+	proof, err := SignWithDST(secretKey, secretKey.PublicKey().Marshal(), popDST)
+	if err != nil {
+		return nil
+	}
+	return proof
+}
+
+// PopVerify verifies a proof of possession of the secret key of a public key.
+//
+// In IETF draft BLS specification:
+// PopVerify(PK, proof) -> VALID or INVALID: an algorithm that outputs VALID
+//      if proof is valid for PK, and INVALID otherwise.
+func PopVerify(pubKey common.PublicKey, proof common.Signature) bool {
+	if featureconfig.Get().SkipBLSVerify {
+		return true
+	}
+	if pubKey == nil || proof == nil {
+		return false
+	}
+	verified, err := VerifyWithDST(pubKey, pubKey.Marshal(), proof, popDST)
+	return err == nil && verified
+}
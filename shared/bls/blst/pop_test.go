@@ -0,0 +1,43 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst_test
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls/blst"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestPopProve_PopVerify(t *testing.T) {
+	priv, err := blst.RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	proof := blst.PopProve(priv)
+	require.NotNil(t, proof)
+	assert.Equal(t, true, blst.PopVerify(pub, proof), "Proof of possession did not verify")
+
+	// A proof parsed from bytes verifies too.
+	parsed, err := blst.SignatureFromBytes(proof.Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, true, blst.PopVerify(pub, parsed), "Parsed proof of possession did not verify")
+}
+
+func TestPopVerify_Invalid(t *testing.T) {
+	priv, err := blst.RandKey()
+	require.NoError(t, err)
+	other, err := blst.RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+
+	assert.Equal(t, false, blst.PopVerify(pub, blst.PopProve(other)), "Proof from a different key verified")
+	// A plain signature over the public key is not a proof of possession.
+	assert.Equal(t, false, blst.PopVerify(pub, priv.Sign(pub.Marshal())), "Signature verified as a proof")
+	assert.Equal(t, false, blst.PopVerify(pub, nil), "Nil proof verified")
+	assert.Equal(t, false, blst.PopVerify(nil, blst.PopProve(priv)), "Proof verified without a public key")
+
+	priv.Destroy()
+	assert.Equal(t, true, blst.PopProve(priv) == nil, "Destroyed key produced a proof")
+}
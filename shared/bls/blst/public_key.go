@@ -6,26 +6,22 @@ package blst
 import (
 	"fmt"
 
-	"github.com/dgraph-io/ristretto"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
-var maxKeys = int64(1000000)
-var pubkeyCache, _ = ristretto.NewCache(&ristretto.Config{
-	NumCounters: maxKeys,
-	MaxCost:     1 << 26, // ~64mb is cache max size
-	BufferItems: 64,
-})
-
 // PublicKey used in the BLS signature scheme.
 type PublicKey struct {
 	p *blstPublicKey
 }
 
-// PublicKeyFromBytes creates a BLS public key from a  BigEndian byte slice.
+// PublicKeyFromBytes creates a BLS public key from a  BigEndian byte slice. Validator public
+// keys are parsed over and over, and decompressing them is expensive, so decompressed keys
+// are kept in an LRU cache of the size configured with the bls-pubkey-cache-size flag.
+// It is safe for keys from untrusted sources, as points off the curve, outside of the G1
+// subgroup or at infinity are rejected and never cached.
 func PublicKeyFromBytes(pubKey []byte) (common.PublicKey, error) {
 	if featureconfig.Get().SkipBLSVerify {
 		return &PublicKey{}, nil
@@ -33,8 +29,8 @@ func PublicKeyFromBytes(pubKey []byte) (common.PublicKey, error) {
 	if len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
 		return nil, fmt.Errorf("public key must be %d bytes", params.BeaconConfig().BLSPubkeyLength)
 	}
-	if cv, ok := pubkeyCache.Get(string(pubKey)); ok {
-		return cv.(*PublicKey).Copy(), nil
+	if cached, ok := cachedPublicKey(pubKey); ok {
+		return cached, nil
 	}
 	// Subgroup check done when decompressing pubkey.
 	p := new(blstPublicKey).Uncompress(pubKey)
@@ -45,11 +41,67 @@ func PublicKeyFromBytes(pubKey []byte) (common.PublicKey, error) {
 	if pubKeyObj.IsInfinite() {
 		return nil, common.ErrInfinitePubKey
 	}
-	copiedKey := pubKeyObj.Copy()
-	pubkeyCache.Set(string(pubKey), copiedKey, 48)
+	cachePublicKey(pubKey, p)
 	return pubKeyObj, nil
 }
 
+// PublicKeysFromBytes creates BLS public keys from BigEndian byte slices. Keys missing from
+// the cache are uncompressed in a single batch across several threads, which is much faster
+// than parsing them one at a time when loading many keys. An error names the index of the
+// first malformed key.
+func PublicKeysFromBytes(pubKeys [][]byte) ([]common.PublicKey, error) {
+	keys := make([]common.PublicKey, len(pubKeys))
+	if featureconfig.Get().SkipBLSVerify {
+		for i := range keys {
+			keys[i] = &PublicKey{}
+		}
+		return keys, nil
+	}
+	uncached := make([][]byte, 0, len(pubKeys))
+	uncachedIndices := make([]int, 0, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		if len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+			return nil, firstPublicKeyError(pubKeys)
+		}
+		if cached, ok := cachedPublicKey(pubKey); ok {
+			keys[i] = cached
+			continue
+		}
+		uncached = append(uncached, pubKey)
+		uncachedIndices = append(uncachedIndices, i)
+	}
+	if len(uncached) == 0 {
+		return keys, nil
+	}
+	// Subgroup check done when decompressing pubkeys.
+	points := new(blstPublicKey).BatchUncompress(uncached)
+	if points == nil {
+		return nil, firstPublicKeyError(pubKeys)
+	}
+	for i, p := range points {
+		pubKeyObj := &PublicKey{p: p}
+		if pubKeyObj.IsInfinite() {
+			return nil, firstPublicKeyError(pubKeys)
+		}
+		keys[uncachedIndices[i]] = pubKeyObj
+	}
+	for i, pubKey := range uncached {
+		cachePublicKey(pubKey, points[i])
+	}
+	return keys, nil
+}
+
+// Returns an error naming the first public key which cannot be parsed. Batch uncompression
+// does not report which key failed, so the keys are parsed one at a time to find it.
+func firstPublicKeyError(pubKeys [][]byte) error {
+	for i, pubKey := range pubKeys {
+		if _, err := PublicKeyFromBytes(pubKey); err != nil {
+			return errors.Wrapf(err, "invalid public key at index %d", i)
+		}
+	}
+	return errors.New("could not unmarshal bytes into public keys")
+}
+
 // AggregatePublicKeys aggregates the provided raw public keys into a single key.
 func AggregatePublicKeys(pubs [][]byte) (common.PublicKey, error) {
 	if featureconfig.Get().SkipBLSVerify {
@@ -68,11 +120,51 @@ func AggregatePublicKeys(pubs [][]byte) (common.PublicKey, error) {
 	return &PublicKey{p: agg.ToAffine()}, nil
 }
 
+// AggregateMultiplePubkeys aggregates the provided public keys into a single key in one
+// pass over their points, rather than chaining Aggregate one key at a time. When BLS
+// verification is skipped, the first key is returned unchanged.
+func AggregateMultiplePubkeys(pubs []common.PublicKey) (common.PublicKey, error) {
+	if len(pubs) == 0 {
+		return nil, errors.New("no public keys to aggregate")
+	}
+	if featureconfig.Get().SkipBLSVerify {
+		return pubs[0], nil
+	}
+	mulP1 := make([]*blstPublicKey, len(pubs))
+	for i, pub := range pubs {
+		pubKeyObj, ok := pub.(*PublicKey)
+		if !ok || pubKeyObj == nil || pubKeyObj.p == nil {
+			return nil, fmt.Errorf("public key at index %d is not a valid blst public key", i)
+		}
+		mulP1[i] = pubKeyObj.p
+	}
+	if len(mulP1) == 1 {
+		return pubs[0].Copy(), nil
+	}
+	agg := new(blstAggregatePublicKey)
+	agg.Aggregate(mulP1)
+	return &PublicKey{p: agg.ToAffine()}, nil
+}
+
 // Marshal a public key into a LittleEndian byte slice.
 func (p *PublicKey) Marshal() []byte {
 	return p.p.Compress()
 }
 
+// MarshalHex returns the 0x-prefixed hex encoding of the compressed public key.
+func (p *PublicKey) MarshalHex() string {
+	return fmt.Sprintf("%#x", p.Marshal())
+}
+
+// String returns the 0x-prefixed hex encoding of the compressed public key, for logging.
+// Placeholder keys, as read with SkipBLSVerify set, are written as "0xmock".
+func (p *PublicKey) String() string {
+	if featureconfig.Get().SkipBLSVerify || p.p == nil {
+		return placeholderHex
+	}
+	return p.MarshalHex()
+}
+
 // Copy the public key to a new pointer reference.
 func (p *PublicKey) Copy() common.PublicKey {
 	np := *p.p
@@ -98,3 +190,14 @@ func (p *PublicKey) Aggregate(p2 common.PublicKey) common.PublicKey {
 
 	return p
 }
+
+// Marshals public keys for a verification trace, leaving nil or foreign keys empty.
+func tracePublicKeys(pubKeys []common.PublicKey) [][]byte {
+	enc := make([][]byte, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		if pub, ok := pubKey.(*PublicKey); ok && pub != nil && pub.p != nil {
+			enc[i] = pub.Marshal()
+		}
+	}
+	return enc
+}
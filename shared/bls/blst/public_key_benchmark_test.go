@@ -0,0 +1,69 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func BenchmarkPublicKeysFromBytes(b *testing.B) {
+	numKeys := 10000
+	pubKeys := make([][]byte, numKeys)
+	for i := 0; i < numKeys; i++ {
+		priv, err := RandKey()
+		require.NoError(b, err)
+		pubKeys[i] = priv.PublicKey().Marshal()
+	}
+
+	// The cache is cleared before each iteration, so every key is uncompressed.
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			ClearPublicKeyCache()
+			b.StartTimer()
+			_, err := PublicKeysFromBytes(pubKeys)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("Loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			ClearPublicKeyCache()
+			b.StartTimer()
+			for _, pubKey := range pubKeys {
+				_, err := PublicKeyFromBytes(pubKey)
+				require.NoError(b, err)
+			}
+		}
+	})
+}
+
+func BenchmarkPublicKeyFromBytes(b *testing.B) {
+	priv, err := RandKey()
+	require.NoError(b, err)
+	pubKey := priv.PublicKey().Marshal()
+
+	// The cache is cleared before each call, so the key is uncompressed.
+	b.Run("Uncompress", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			ClearPublicKeyCache()
+			b.StartTimer()
+			_, err := PublicKeyFromBytes(pubKey)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("CacheHit", func(b *testing.B) {
+		ClearPublicKeyCache()
+		_, err := PublicKeyFromBytes(pubKey)
+		require.NoError(b, err)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := PublicKeyFromBytes(pubKey)
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+// defaultPublicKeyCacheSize is the number of decompressed public keys kept when the
+// cache size is not configured.
+const defaultPublicKeyCacheSize = 100000
+
+var (
+	decompressedPubKeyCache     *lru.Cache
+	decompressedPubKeyCacheOnce sync.Once
+
+	// pubKeyCacheHit tracks the number of public keys which did not have to be decompressed.
+	pubKeyCacheHit = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bls_public_key_cache_hit",
+		Help: "The number of public key requests that are present in the decompressed public key cache.",
+	})
+	// pubKeyCacheMiss tracks the number of public keys which had to be decompressed.
+	pubKeyCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bls_public_key_cache_miss",
+		Help: "The number of public key requests that aren't present in the decompressed public key cache.",
+	})
+)
+
+// The cache is created on first use, so its size is read from the feature config
+// once the node has been configured.
+func publicKeyCache() *lru.Cache {
+	decompressedPubKeyCacheOnce.Do(func() {
+		size := featureconfig.Get().BLSPublicKeyCacheSize
+		if size <= 0 {
+			size = defaultPublicKeyCacheSize
+		}
+		cache, err := lru.New(size)
+		if err != nil {
+			panic(err) // The size is always positive.
+		}
+		decompressedPubKeyCache = cache
+	})
+	return decompressedPubKeyCache
+}
+
+// Returns the cached decompressed public key of the given compressed key, if any. The key
+// is copied, so the cached key cannot be modified by the caller.
+func cachedPublicKey(pubKey []byte) (*PublicKey, bool) {
+	cached, ok := publicKeyCache().Get(bytesutil.ToBytes48(pubKey))
+	if !ok {
+		pubKeyCacheMiss.Inc()
+		return nil, false
+	}
+	pubKeyCacheHit.Inc()
+	p := *cached.(*blstPublicKey)
+	return &PublicKey{p: &p}, true
+}
+
+// Caches a copy of a decompressed public key under its compressed form. Only keys which
+// passed the checks of PublicKeyFromBytes may be cached.
+func cachePublicKey(pubKey []byte, p *blstPublicKey) {
+	cached := *p
+	publicKeyCache().Add(bytesutil.ToBytes48(pubKey), &cached)
+}
+
+// ClearPublicKeyCache removes every decompressed public key from the cache used by
+// PublicKeyFromBytes and PublicKeysFromBytes.
+func ClearPublicKeyCache() {
+	publicKeyCache().Purge()
+}
@@ -0,0 +1,57 @@
+// +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
+// +build blst_enabled
+
+package blst
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestPublicKeyFromBytes_Cache(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pubKeyBytes := priv.PublicKey().Marshal()
+
+	misses := testutil.ToFloat64(pubKeyCacheMiss)
+	first, err := PublicKeyFromBytes(pubKeyBytes)
+	require.NoError(t, err)
+	assert.Equal(t, misses+1, testutil.ToFloat64(pubKeyCacheMiss))
+
+	hits := testutil.ToFloat64(pubKeyCacheHit)
+	second, err := PublicKeyFromBytes(pubKeyBytes)
+	require.NoError(t, err)
+	assert.Equal(t, hits+1, testutil.ToFloat64(pubKeyCacheHit))
+	assert.Equal(t, true, first.(*PublicKey).p.Equals(second.(*PublicKey).p), "Cached public key does not equal the decompressed one")
+
+	// Modifying a returned key leaves the cached key untouched.
+	other, err := RandKey()
+	require.NoError(t, err)
+	second.Aggregate(other.PublicKey())
+	third, err := PublicKeyFromBytes(pubKeyBytes)
+	require.NoError(t, err)
+	assert.DeepEqual(t, pubKeyBytes, third.Marshal(), "Cached public key was modified")
+
+	ClearPublicKeyCache()
+	misses = testutil.ToFloat64(pubKeyCacheMiss)
+	_, err = PublicKeyFromBytes(pubKeyBytes)
+	require.NoError(t, err)
+	assert.Equal(t, misses+1, testutil.ToFloat64(pubKeyCacheMiss))
+}
+
+func TestPublicKeyFromBytes_InvalidNotCached(t *testing.T) {
+	ClearPublicKeyCache()
+	_, err := PublicKeyFromBytes([]byte{1, 2, 3})
+	assert.ErrorContains(t, "public key must be 48 bytes", err)
+	_, err = PublicKeyFromBytes(make([]byte, 48))
+	assert.ErrorContains(t, "could not unmarshal bytes into public key", err)
+	infinite := make([]byte, 48)
+	infinite[0] = 0xc0
+	_, err = PublicKeyFromBytes(infinite)
+	assert.Equal(t, common.ErrInfinitePubKey, err)
+	assert.Equal(t, 0, publicKeyCache().Len(), "Invalid public key was cached")
+}
@@ -5,10 +5,15 @@ package blst_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/bls/blst"
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 )
@@ -63,6 +68,107 @@ func TestPublicKeyFromBytes(t *testing.T) {
 	}
 }
 
+func TestPublicKeysFromBytes(t *testing.T) {
+	numKeys := 5
+	pubKeys := make([][]byte, numKeys)
+	for i := 0; i < numKeys; i++ {
+		priv, err := blst.RandKey()
+		require.NoError(t, err)
+		pubKeys[i] = priv.PublicKey().Marshal()
+	}
+	// Parse some of the keys first, so both cached and uncached keys are parsed.
+	_, err := blst.PublicKeyFromBytes(pubKeys[1])
+	require.NoError(t, err)
+
+	keys, err := blst.PublicKeysFromBytes(pubKeys)
+	require.NoError(t, err)
+	require.Equal(t, numKeys, len(keys))
+	for i, key := range keys {
+		assert.DeepEqual(t, pubKeys[i], key.Marshal())
+	}
+	keys, err = blst.PublicKeysFromBytes(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(keys))
+
+	withKey := func(i int, key []byte) [][]byte {
+		withKey := make([][]byte, numKeys)
+		copy(withKey, pubKeys)
+		withKey[i] = key
+		return withKey
+	}
+	malformed := make([]byte, 48)
+	infinite := append([]byte{0xC0}, make([]byte, 47)...)
+	_, err = blst.PublicKeysFromBytes(withKey(3, malformed))
+	assert.ErrorContains(t, "invalid public key at index 3: could not unmarshal bytes into public key", err)
+	_, err = blst.PublicKeysFromBytes(withKey(4, infinite))
+	assert.ErrorContains(t, "invalid public key at index 4: "+common.ErrInfinitePubKey.Error(), err)
+	// The first malformed key is named, even if a later key has the wrong length.
+	badKeys := withKey(2, malformed)
+	badKeys[4] = pubKeys[4][:47]
+	_, err = blst.PublicKeysFromBytes(badKeys)
+	assert.ErrorContains(t, "invalid public key at index 2", err)
+}
+
+func TestPublicKeysFromBytes_SkipBLSVerify(t *testing.T) {
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{SkipBLSVerify: true})
+	defer reset()
+	keys, err := blst.PublicKeysFromBytes([][]byte{make([]byte, 48), make([]byte, 48)})
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(keys))
+}
+
+func TestPublicKeyFromBytes_Untrusted(t *testing.T) {
+	priv, err := blst.RandKey()
+	require.NoError(t, err)
+	infinite := make([]byte, 48)
+	infinite[0] = 0xc0
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "Good",
+			input: hex.EncodeToString(priv.PublicKey().Marshal()),
+		},
+		{
+			name:  "Short",
+			input: "a99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e4",
+			err:   "public key must be 48 bytes",
+		},
+		{
+			// A valid point on the curve which is not in the G1 subgroup.
+			name:  "Not in subgroup",
+			input: "8123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			err:   "public key",
+		},
+		{
+			name:  "Not on curve",
+			input: "800000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001",
+			err:   "could not unmarshal bytes into public key",
+		},
+		{
+			name:  "Infinity",
+			input: hex.EncodeToString(infinite),
+			err:   common.ErrInfinitePubKey.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := hex.DecodeString(tt.input)
+			require.NoError(t, err)
+			res, err := blst.PublicKeyFromBytes(input)
+			if tt.err != "" {
+				assert.ErrorContains(t, tt.err, err)
+				assert.Equal(t, nil, res)
+			} else {
+				require.NoError(t, err)
+				assert.DeepEqual(t, input, res.Marshal())
+			}
+		})
+	}
+}
+
 func TestPublicKey_Copy(t *testing.T) {
 	priv, err := blst.RandKey()
 	require.NoError(t, err)
@@ -76,3 +182,71 @@ func TestPublicKey_Copy(t *testing.T) {
 
 	require.DeepEqual(t, pubkeyA.Marshal(), pubkeyBytes, "Pubkey was mutated after copy")
 }
+
+func TestAggregateMultiplePubkeys(t *testing.T) {
+	_, err := blst.AggregateMultiplePubkeys(nil)
+	assert.ErrorContains(t, "no public keys to aggregate", err)
+
+	var pubkeys []common.PublicKey
+	for i := 0; i < 3; i++ {
+		priv, err := blst.RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+	}
+
+	// A single key is copied rather than returned as is.
+	single, err := blst.AggregateMultiplePubkeys(pubkeys[:1])
+	require.NoError(t, err)
+	assert.DeepEqual(t, pubkeys[0].Marshal(), single.Marshal())
+	single.Aggregate(pubkeys[1])
+	assert.Equal(t, false, bytes.Equal(pubkeys[0].Marshal(), single.Marshal()), "Aggregating into the result mutated the input")
+
+	aggregated, err := blst.AggregateMultiplePubkeys(pubkeys)
+	require.NoError(t, err)
+	chained := pubkeys[0].Copy()
+	for _, pubkey := range pubkeys[1:] {
+		chained = chained.Aggregate(pubkey)
+	}
+	assert.DeepEqual(t, chained.Marshal(), aggregated.Marshal())
+
+	_, err = blst.AggregateMultiplePubkeys([]common.PublicKey{pubkeys[0], nil})
+	assert.ErrorContains(t, "public key at index 1", err)
+}
+
+func TestAggregateMultiplePubkeys_SkipBLSVerify(t *testing.T) {
+	var pubkeys []common.PublicKey
+	for i := 0; i < 3; i++ {
+		priv, err := blst.RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+	}
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{SkipBLSVerify: true})
+	defer reset()
+
+	aggregated, err := blst.AggregateMultiplePubkeys(pubkeys)
+	require.NoError(t, err)
+	assert.Equal(t, pubkeys[0], aggregated, "Expected the first key to be returned unchanged")
+	_, err = blst.AggregateMultiplePubkeys(nil)
+	assert.ErrorContains(t, "no public keys to aggregate", err)
+}
+
+func TestPublicKey_String(t *testing.T) {
+	priv, err := blst.RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	hexKey := fmt.Sprint(pub)
+	require.Equal(t, true, strings.HasPrefix(hexKey, "0x"), hexKey)
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(hexKey, "0x"))
+	require.NoError(t, err)
+	parsed, err := blst.PublicKeyFromBytes(raw)
+	require.NoError(t, err)
+	assert.DeepEqual(t, pub.Marshal(), parsed.Marshal())
+	assert.Equal(t, hexKey, fmt.Sprint(parsed))
+
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{SkipBLSVerify: true})
+	defer reset()
+	placeholder, err := blst.PublicKeyFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "0xmock", fmt.Sprint(placeholder))
+}
@@ -4,8 +4,10 @@
 package blst
 
 import (
+	"bytes"
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -13,9 +15,16 @@ import (
 	blst "github.com/supranational/blst/bindings/go"
 )
 
+// The order r of the BLS12-381 subgroups, big endian. Secret keys are scalars in [1, r-1].
+var curveOrder = [32]byte{
+	0x73, 0xed, 0xa7, 0x53, 0x29, 0x9d, 0x7d, 0x48, 0x33, 0x39, 0xd8, 0x08, 0x09, 0xa1, 0xd8, 0x05,
+	0x53, 0xbd, 0xa4, 0x02, 0xff, 0xfe, 0x5b, 0xfe, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01,
+}
+
 // bls12SecretKey used in the BLS signature scheme.
 type bls12SecretKey struct {
-	p *blst.SecretKey
+	p         *blst.SecretKey
+	destroyed bool
 }
 
 // RandKey creates a new private key using a random method provided as an io.Reader.
@@ -26,19 +35,33 @@ func RandKey() (common.SecretKey, error) {
 	if err != nil {
 		return nil, err
 	}
+	return secretKeyFromIKM(ikm)
+}
+
+// Derives a secret key from input keying material. All-zero keying material means
+// the entropy source failed, so it is rejected rather than deriving a key from it.
+func secretKeyFromIKM(ikm [32]byte) (common.SecretKey, error) {
+	if ikm == [32]byte{} {
+		return nil, errors.New("input keying material is zero")
+	}
 	// Defensive check, that we have not generated a secret key,
-	secKey := &bls12SecretKey{blst.KeyGen(ikm[:])}
+	secKey := &bls12SecretKey{p: blst.KeyGen(ikm[:])}
 	if secKey.IsZero() {
 		return nil, common.ErrZeroKey
 	}
 	return secKey, nil
 }
 
-// SecretKeyFromBytes creates a BLS private key from a BigEndian byte slice.
+// SecretKeyFromBytes creates a BLS private key from a BigEndian byte slice. Keys which
+// are not a canonical scalar in [1, r-1], r being the curve order, are rejected rather
+// than reduced into a different key.
 func SecretKeyFromBytes(privKey []byte) (common.SecretKey, error) {
 	if len(privKey) != params.BeaconConfig().BLSSecretKeyLength {
 		return nil, fmt.Errorf("secret key must be %d bytes", params.BeaconConfig().BLSSecretKeyLength)
 	}
+	if bytes.Compare(privKey, curveOrder[:]) >= 0 {
+		return nil, errors.Wrap(common.ErrSecretUnmarshal, "secret key must be less than the curve order")
+	}
 	secKey := new(blst.SecretKey).Deserialize(privKey)
 	if secKey == nil {
 		return nil, common.ErrSecretUnmarshal
@@ -61,6 +84,24 @@ func (s *bls12SecretKey) IsZero() bool {
 	return s.p.Equals(zeroKey)
 }
 
+// Zeroize overwrites the scalar of the secret key with zeros, clearing the key material
+// from memory once the key is no longer needed. The key must not be used afterwards.
+func (s *bls12SecretKey) Zeroize() {
+	if s.p == nil {
+		return
+	}
+	*s.p = blst.SecretKey{}
+}
+
+// Destroy zeroizes the secret key and marks it unusable, after which Sign and SignBatch return
+// invalid signatures, Marshal returns nil and SignMessageSet returns ErrDestroyedKey. As the garbage collector may
+// have copied the key material elsewhere in memory, wiping it is best-effort, but it
+// reduces the window in which the key can be read from the process memory.
+func (s *bls12SecretKey) Destroy() {
+	s.destroyed = true
+	s.Zeroize()
+}
+
 // Sign a message using a secret key - in a beacon/validator client.
 //
 // In IETF draft BLS specification:
@@ -70,15 +111,63 @@ func (s *bls12SecretKey) IsZero() bool {
 // In ETH2.0 specification:
 // def Sign(SK: int, message: Bytes) -> BLSSignature
 func (s *bls12SecretKey) Sign(msg []byte) common.Signature {
-	if featureconfig.Get().SkipBLSVerify {
+	if s.destroyed {
 		return &Signature{}
 	}
-	signature := new(blstSignature).Sign(s.p, msg, dst)
-	return &Signature{s: signature}
+	if featureconfig.Get().SkipBLSVerify {
+		return &Signature{contributors: 1}
+	}
+	signature := new(blstSignature).Sign(s.p, msg, signingDST())
+	return &Signature{s: signature, contributors: 1}
+}
+
+// SignMessageSet signs each of the provided messages, such as the same registration under
+// several signing domains, returning the signatures in the order of the messages.
+func (s *bls12SecretKey) SignMessageSet(msgs [][]byte) ([]common.Signature, error) {
+	if s.destroyed {
+		return nil, common.ErrDestroyedKey
+	}
+	if len(msgs) == 0 {
+		return nil, errors.New("no messages to sign")
+	}
+	return s.SignBatch(msgs), nil
+}
+
+// SignBatch signs each of the provided messages, such as several attestations in a slot,
+// returning one signature per message in the order of the messages. It is equivalent to
+// calling Sign in a loop, but resolves the domain separation tag once and writes the
+// signatures into a single preallocated set of points, rather than allocating them per
+// message. A destroyed key returns invalid signatures.
+func (s *bls12SecretKey) SignBatch(msgs [][]byte) []common.Signature {
+	sigs := make([]common.Signature, len(msgs))
+	if s.destroyed {
+		for i := range sigs {
+			sigs[i] = &Signature{}
+		}
+		return sigs
+	}
+	wrappers := make([]Signature, len(msgs))
+	if featureconfig.Get().SkipBLSVerify {
+		for i := range wrappers {
+			wrappers[i].contributors = 1
+			sigs[i] = &wrappers[i]
+		}
+		return sigs
+	}
+	dst := signingDST()
+	points := make([]blstSignature, len(msgs))
+	for i, msg := range msgs {
+		wrappers[i] = Signature{s: points[i].Sign(s.p, msg, dst), contributors: 1}
+		sigs[i] = &wrappers[i]
+	}
+	return sigs
 }
 
 // Marshal a secret key into a LittleEndian byte slice.
 func (s *bls12SecretKey) Marshal() []byte {
+	if s.destroyed {
+		return nil
+	}
 	keyBytes := s.p.Serialize()
 	if len(keyBytes) < params.BeaconConfig().BLSSecretKeyLength {
 		emptyBytes := make([]byte, params.BeaconConfig().BLSSecretKeyLength-len(keyBytes))
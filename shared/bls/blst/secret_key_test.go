@@ -1,14 +1,13 @@
 // +build linux,amd64 linux,arm64 darwin,amd64 windows,amd64
 // +build blst_enabled
 
-package blst_test
+package blst
 
 import (
 	"bytes"
 	"errors"
 	"testing"
 
-	"github.com/prysmaticlabs/prysm/shared/bls/blst"
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
@@ -16,13 +15,13 @@ import (
 )
 
 func TestMarshalUnmarshal(t *testing.T) {
-	priv, err := blst.RandKey()
+	priv, err := RandKey()
 	require.NoError(t, err)
 	b := priv.Marshal()
 	b32 := bytesutil.ToBytes32(b)
-	pk, err := blst.SecretKeyFromBytes(b32[:])
+	pk, err := SecretKeyFromBytes(b32[:])
 	require.NoError(t, err)
-	pk2, err := blst.SecretKeyFromBytes(b32[:])
+	pk2, err := SecretKeyFromBytes(b32[:])
 	require.NoError(t, err)
 	assert.DeepEqual(t, pk.Marshal(), pk2.Marshal(), "Keys not equal")
 }
@@ -57,6 +56,22 @@ func TestSecretKeyFromBytes(t *testing.T) {
 			input: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
 			err:   common.ErrSecretUnmarshal,
 		},
+		{
+			name:  "Zero",
+			input: make([]byte, 32),
+			err:   common.ErrSecretUnmarshal,
+		},
+		{
+			name: "Curve order",
+			input: []byte{0x73, 0xed, 0xa7, 0x53, 0x29, 0x9d, 0x7d, 0x48, 0x33, 0x39, 0xd8, 0x08, 0x09, 0xa1, 0xd8, 0x05,
+				0x53, 0xbd, 0xa4, 0x02, 0xff, 0xfe, 0x5b, 0xfe, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01},
+			err: errors.New("secret key must be less than the curve order"),
+		},
+		{
+			name: "Curve order minus one",
+			input: []byte{0x73, 0xed, 0xa7, 0x53, 0x29, 0x9d, 0x7d, 0x48, 0x33, 0x39, 0xd8, 0x08, 0x09, 0xa1, 0xd8, 0x05,
+				0x53, 0xbd, 0xa4, 0x02, 0xff, 0xfe, 0x5b, 0xfe, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00},
+		},
 		{
 			name:  "Good",
 			input: []byte{0x25, 0x29, 0x5f, 0x0d, 0x1d, 0x59, 0x2a, 0x90, 0xb3, 0x33, 0xe2, 0x6e, 0x85, 0x14, 0x97, 0x08, 0x20, 0x8e, 0x9f, 0x8e, 0x8b, 0xc1, 0x8f, 0x6c, 0x77, 0xbd, 0x62, 0xf8, 0xad, 0x7a, 0x68, 0x66},
@@ -65,7 +80,7 @@ func TestSecretKeyFromBytes(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			res, err := blst.SecretKeyFromBytes(test.input)
+			res, err := SecretKeyFromBytes(test.input)
 			if test.err != nil {
 				assert.NotEqual(t, nil, err, "No error returned")
 				assert.ErrorContains(t, test.err.Error(), err, "Unexpected error returned")
@@ -77,11 +92,98 @@ func TestSecretKeyFromBytes(t *testing.T) {
 	}
 }
 
+func TestSecretKeyFromIKM(t *testing.T) {
+	_, err := secretKeyFromIKM([32]byte{})
+	assert.ErrorContains(t, "input keying material is zero", err)
+
+	priv, err := secretKeyFromIKM([32]byte{'i', 'k', 'm'})
+	require.NoError(t, err)
+	// Key generation is deterministic in the keying material.
+	priv2, err := secretKeyFromIKM([32]byte{'i', 'k', 'm'})
+	require.NoError(t, err)
+	assert.DeepEqual(t, priv.Marshal(), priv2.Marshal())
+}
+
 func TestSerialize(t *testing.T) {
-	rk, err := blst.RandKey()
+	rk, err := RandKey()
 	require.NoError(t, err)
 	b := rk.Marshal()
 
-	_, err = blst.SecretKeyFromBytes(b)
+	_, err = SecretKeyFromBytes(b)
 	assert.NoError(t, err)
 }
+
+func TestZeroize(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	require.DeepNotEqual(t, make([]byte, 32), priv.Marshal())
+
+	priv.Zeroize()
+	assert.DeepEqual(t, make([]byte, 32), priv.Marshal())
+	assert.Equal(t, true, priv.IsZero())
+}
+
+func TestDestroy(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	require.NotNil(t, priv.Sign([]byte("hello")))
+
+	priv.Destroy()
+	assert.Equal(t, true, priv.IsZero())
+	assert.Equal(t, true, priv.Marshal() == nil, "Destroyed key was marshaled")
+	sig := priv.Sign([]byte("hello"))
+	require.NotNil(t, sig)
+	assert.DeepEqual(t, make([]byte, 96), sig.Marshal())
+	assert.Equal(t, false, sig.Verify(pub, []byte("hello")), "Destroyed key signed a message")
+	_, err = priv.SignMessageSet([][]byte{[]byte("hello")})
+	assert.ErrorContains(t, common.ErrDestroyedKey.Error(), err)
+	sigs := priv.SignBatch([][]byte{[]byte("hello")})
+	require.Equal(t, 1, len(sigs))
+	assert.Equal(t, false, sigs[0].Verify(pub, []byte("hello")), "Destroyed key signed a batch")
+}
+
+func TestRandKey_DistinctVerifiableKeys(t *testing.T) {
+	msg := []byte("hello")
+	seen := make(map[[48]byte]bool)
+	for i := 0; i < 100; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pub := priv.PublicKey()
+		pubKey := bytesutil.ToBytes48(pub.Marshal())
+		require.Equal(t, false, seen[pubKey], "Generated a duplicate key")
+		seen[pubKey] = true
+		assert.Equal(t, true, priv.Sign(msg).Verify(pub, msg), "Signature did not verify")
+	}
+}
+
+func TestSignMessageSet(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	_, err = priv.SignMessageSet(nil)
+	assert.ErrorContains(t, "no messages to sign", err)
+
+	msgs := [][]byte{[]byte("deposit"), []byte("builder"), []byte("other")}
+	sigs, err := priv.SignMessageSet(msgs)
+	require.NoError(t, err)
+	require.Equal(t, len(msgs), len(sigs))
+	for i, sig := range sigs {
+		assert.Equal(t, true, sig.Verify(priv.PublicKey(), msgs[i]), "Signature %d did not verify", i)
+		assert.Equal(t, false, sig.Verify(priv.PublicKey(), msgs[(i+1)%len(msgs)]))
+	}
+}
+
+func TestSignBatch(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(priv.SignBatch(nil)))
+
+	msgs := [][]byte{[]byte("attestation 1"), []byte("attestation 2"), []byte("attestation 2"), []byte("attestation 3")}
+	sigs := priv.SignBatch(msgs)
+	require.Equal(t, len(msgs), len(sigs))
+	for i, sig := range sigs {
+		assert.DeepEqual(t, priv.Sign(msgs[i]).Marshal(), sig.Marshal(), "Signature %d differs from Sign", i)
+		assert.Equal(t, true, sig.Verify(priv.PublicKey(), msgs[i]), "Signature %d did not verify", i)
+		assert.Equal(t, 1, sig.ContributorCount())
+	}
+}
@@ -4,7 +4,11 @@
 package blst
 
 import (
+	"bytes"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
@@ -14,20 +18,60 @@ import (
 	blst "github.com/supranational/blst/bindings/go"
 )
 
-var dst = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+// The domain separation tag signatures are hashed to the curve with, which is
+// configured per network so that forks and test networks can change it.
+func signingDST() []byte {
+	tag := params.BeaconConfig().BLSDomainSeparationTag
+	if c, ok := signingDSTCache.Load().(*cachedDST); ok && c.tag == tag {
+		return c.dst
+	}
+	c := &cachedDST{tag: tag, dst: []byte(tag)}
+	signingDSTCache.Store(c)
+	return c.dst
+}
+
+// cachedDST holds the byte form of a domain separation tag, so that it is only converted
+// again when the chain config changes.
+type cachedDST struct {
+	tag string
+	dst []byte
+}
+
+var signingDSTCache atomic.Value
 
 const scalarBytes = 32
 const randBitsEntropy = 64
 
+// The size of a serialized base field element; a compressed signature holds two.
+const fieldElementBytes = 48
+
+// The fewest signatures VerifyMultipleSignaturesParallel verifies in a single partition.
+const minSignaturesPerPartition = 64
+
+// How placeholder signatures and public keys, which carry no point, are written in logs.
+const placeholderHex = "0xmock"
+
 // Signature used in the BLS signature scheme.
 type Signature struct {
 	s *blstSignature
+	// The number of signatures aggregated into this one. Signatures
+	// deserialized from bytes count as a single contributor, as the
+	// number aggregated into them cannot be recovered.
+	contributors int
+	// The domain separation tag the signature is verified under, or nil for
+	// the tag of the network config. Aggregates are always verified under the latter.
+	dst []byte
 }
 
-// SignatureFromBytes creates a BLS signature from a LittleEndian byte slice.
+// SignatureFromBytes creates a BLS signature from a LittleEndian byte slice. Uncompress
+// rejects points outside of the G2 subgroup, as herumi does for every deserialized
+// signature, so both backends reject the same inputs. The signature at infinity fails
+// verification, and is rejected right away with ErrInfiniteSignature when the
+// RejectInfiniteSignatures feature is enabled.
 func SignatureFromBytes(sig []byte) (common.Signature, error) {
-	if featureconfig.Get().SkipBLSVerify {
-		return &Signature{}, nil
+	cfg := featureconfig.Get()
+	if cfg.SkipBLSVerify {
+		return &Signature{contributors: 1}, nil
 	}
 	if len(sig) != params.BeaconConfig().BLSSignatureLength {
 		return nil, fmt.Errorf("signature must be %d bytes", params.BeaconConfig().BLSSignatureLength)
@@ -36,7 +80,71 @@ func SignatureFromBytes(sig []byte) (common.Signature, error) {
 	if signature == nil {
 		return nil, errors.New("could not unmarshal bytes into signature")
 	}
-	return &Signature{s: signature}, nil
+	sigObj := &Signature{s: signature, contributors: 1}
+	if cfg.RejectInfiniteSignatures && sigObj.IsInfinite() {
+		return nil, common.ErrInfiniteSignature
+	}
+	return sigObj, nil
+}
+
+// SignaturesFromBytes creates BLS signatures from LittleEndian byte slices, validating each
+// one like SignatureFromBytes. With more than one worker, the signatures are deserialized
+// concurrently by a pool of at most that many workers. Signatures are returned in the order
+// of the input, and an error always names the lowest index of a malformed signature,
+// regardless of which worker found it.
+func SignaturesFromBytes(sigs [][]byte, workers int) ([]common.Signature, error) {
+	signatures := make([]common.Signature, len(sigs))
+	errs := make([]error, len(sigs))
+	if workers > len(sigs) {
+		workers = len(sigs)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	// Workers claim indices in increasing order, and stop claiming once an index past the
+	// lowest failure found so far comes up. Every index below a failure is still
+	// deserialized, so the lowest failing index is found by every run.
+	next := int64(-1)
+	lowestFailure := int64(len(sigs))
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := atomic.AddInt64(&next, 1)
+				if i >= int64(len(sigs)) || i > atomic.LoadInt64(&lowestFailure) {
+					return
+				}
+				signatures[i], errs[i] = SignatureFromBytes(sigs[i])
+				if errs[i] == nil {
+					continue
+				}
+				for {
+					lowest := atomic.LoadInt64(&lowestFailure)
+					if i >= lowest || atomic.CompareAndSwapInt64(&lowestFailure, lowest, i) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if lowestFailure < int64(len(sigs)) {
+		return nil, errors.Wrapf(errs[lowestFailure], "invalid signature at index %d", lowestFailure)
+	}
+	return signatures, nil
+}
+
+// SignatureFromBytesLE creates a BLS signature from the byte order used by some legacy
+// tools, in which each of the signature's field elements is serialized in reversed byte
+// order. The consensus standard serialization remains the big endian one read by
+// SignatureFromBytes; this exists only for interop with such tools.
+func SignatureFromBytesLE(sig []byte) (common.Signature, error) {
+	if len(sig) != params.BeaconConfig().BLSSignatureLength {
+		return nil, fmt.Errorf("signature must be %d bytes", params.BeaconConfig().BLSSignatureLength)
+	}
+	return SignatureFromBytes(reverseFieldElements(sig))
 }
 
 // Verify a bls signature given a public key, a message.
@@ -48,17 +156,38 @@ func SignatureFromBytes(sig []byte) (common.Signature, error) {
 //
 // In ETH2.0 specification:
 // def Verify(PK: BLSPubkey, message: Bytes, signature: BLSSignature) -> bool
-func (s *Signature) Verify(pubKey common.PublicKey, msg []byte) bool {
+func (s *Signature) Verify(pubKey common.PublicKey, msg []byte) (verified bool) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("Verify", [][]byte{s.Marshal()}, tracePublicKeys([]common.PublicKey{pubKey}), [][]byte{msg}, verified)
+		}()
+	}
 	if featureconfig.Get().SkipBLSVerify {
 		return true
 	}
-	return s.s.Verify(pubKey.(*PublicKey).p, msg, dst)
+	// Reject blank aggregates which no signature was aggregated into.
+	if s.contributors == 0 {
+		return false
+	}
+	pub, ok := validPublicKey(pubKey)
+	if !ok {
+		return false
+	}
+	// Reject the infinite signature and public key.
+	if s.IsInfinite() || pub.IsInfinite() {
+		return false
+	}
+	return s.s.Verify(pub.p, msg, s.domainSeparationTag())
 }
 
 // AggregateVerify verifies each public key against its respective message.
 // This is vulnerable to rogue public-key attack. Each user must
 // provide a proof-of-knowledge of the public key.
 //
+// The infinity signature is INVALID for every set of public keys and messages, including
+// sets whose public keys aggregate to the infinity point, for which the pairing check alone
+// would pass. An empty set is INVALID for every signature.
+//
 // In IETF draft BLS specification:
 // AggregateVerify((PK_1, message_1), ..., (PK_n, message_n),
 //      signature) -> VALID or INVALID: an aggregate verification
@@ -68,10 +197,23 @@ func (s *Signature) Verify(pubKey common.PublicKey, msg []byte) bool {
 //
 // In ETH2.0 specification:
 // def AggregateVerify(pairs: Sequence[PK: BLSPubkey, message: Bytes], signature: BLSSignature) -> boo
-func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte) bool {
+func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte) (verified bool) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("AggregateVerify", [][]byte{s.Marshal()}, tracePublicKeys(pubKeys), common.MessagesBytes(msgs), verified)
+		}()
+	}
 	if featureconfig.Get().SkipBLSVerify {
 		return true
 	}
+	// Reject blank aggregates which no signature was aggregated into.
+	if s.contributors == 0 {
+		return false
+	}
+	// Reject the infinite signature before looking at the set.
+	if s.IsInfinite() {
+		return false
+	}
 	size := len(pubKeys)
 	if size == 0 {
 		return false
@@ -82,14 +224,25 @@ func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte)
 	msgSlices := make([][]byte, len(msgs))
 	rawKeys := make([]*blstPublicKey, len(msgs))
 	for i := 0; i < size; i++ {
+		pub, ok := validPublicKey(pubKeys[i])
+		if !ok {
+			return false
+		}
+		if pub.IsInfinite() {
+			return false
+		}
 		msgSlices[i] = msgs[i][:]
-		rawKeys[i] = pubKeys[i].(*PublicKey).p
+		rawKeys[i] = pub.p
 	}
-	return s.s.AggregateVerify(rawKeys, msgSlices, dst)
+	return s.s.AggregateVerify(rawKeys, msgSlices, s.domainSeparationTag())
 }
 
 // FastAggregateVerify verifies all the provided public keys with their aggregated signature.
 //
+// The infinity signature is INVALID for every message and set of public keys, including
+// sets whose public keys aggregate to the infinity point, for which the pairing check alone
+// would pass. An empty set is INVALID for every signature.
+//
 // In IETF draft BLS specification:
 // FastAggregateVerify(PK_1, ..., PK_n, message, signature) -> VALID
 //      or INVALID: a verification algorithm for the aggregate of multiple
@@ -98,46 +251,103 @@ func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte)
 //
 // In ETH2.0 specification:
 // def FastAggregateVerify(PKs: Sequence[BLSPubkey], message: Bytes, signature: BLSSignature) -> bool
-func (s *Signature) FastAggregateVerify(pubKeys []common.PublicKey, msg [32]byte) bool {
+func (s *Signature) FastAggregateVerify(pubKeys []common.PublicKey, msg [32]byte) (verified bool) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("FastAggregateVerify", [][]byte{s.Marshal()}, tracePublicKeys(pubKeys), [][]byte{msg[:]}, verified)
+		}()
+	}
 	if featureconfig.Get().SkipBLSVerify {
 		return true
 	}
+	// Reject blank aggregates which no signature was aggregated into.
+	if s.contributors == 0 {
+		return false
+	}
+	// Reject the infinite signature before looking at the set.
+	if s.IsInfinite() {
+		return false
+	}
 	if len(pubKeys) == 0 {
 		return false
 	}
 	rawKeys := make([]*blstPublicKey, len(pubKeys))
 	for i := 0; i < len(pubKeys); i++ {
-		rawKeys[i] = pubKeys[i].(*PublicKey).p
+		pub, ok := validPublicKey(pubKeys[i])
+		if !ok {
+			return false
+		}
+		if pub.IsInfinite() {
+			return false
+		}
+		rawKeys[i] = pub.p
 	}
 
-	return s.s.FastAggregateVerify(rawKeys, msg[:], dst)
+	return s.s.FastAggregateVerify(rawKeys, msg[:], s.domainSeparationTag())
+}
+
+// IsAggregate checks that the signature is an aggregate of signatures of a message by exactly
+// expectedSigners distinct public keys. It lets tooling tell an aggregate apart from a single
+// signature, or from an aggregate by a different committee.
+func (s *Signature) IsAggregate(expectedSigners int, pubKeys []common.PublicKey, msg [32]byte) bool {
+	if expectedSigners <= 0 || len(pubKeys) != expectedSigners {
+		return false
+	}
+	seen := make(map[string]bool, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		if pubKey == nil {
+			return false
+		}
+		key := string(pubKey.Marshal())
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	return s.FastAggregateVerify(pubKeys, msg)
 }
 
-// NewAggregateSignature creates a blank aggregate signature.
+// NewAggregateSignature creates a blank aggregate signature. It has no contributors,
+// so it fails verification until real signatures are aggregated into it.
 func NewAggregateSignature() common.Signature {
-	sig := blst.HashToG2([]byte{'m', 'o', 'c', 'k'}, dst).ToAffine()
+	sig := blst.HashToG2([]byte{'m', 'o', 'c', 'k'}, signingDST()).ToAffine()
 	return &Signature{s: sig}
 }
 
 // AggregateSignatures converts a list of signatures into a single, aggregated sig.
-func AggregateSignatures(sigs []common.Signature) common.Signature {
+// There is no aggregate of an empty list, so it returns an error instead.
+func AggregateSignatures(sigs []common.Signature) (common.Signature, error) {
 	if len(sigs) == 0 {
-		return nil
+		return nil, common.ErrNoSignatures
 	}
 	if featureconfig.Get().SkipBLSVerify {
-		return sigs[0]
+		// Signatures read with SkipBLSVerify set are placeholders without a point, so
+		// the aggregate is a placeholder too.
+		contributors := 0
+		for _, sig := range sigs {
+			if sig, ok := sig.(*Signature); ok && sig != nil {
+				contributors += sig.contributors
+			}
+		}
+		return &Signature{contributors: contributors}, nil
 	}
 
 	rawSigs := make([]*blstSignature, len(sigs))
+	contributors := 0
 	for i := 0; i < len(sigs); i++ {
-		rawSigs[i] = sigs[i].(*Signature).s
+		sig, ok := sigs[i].(*Signature)
+		if !ok || sig == nil || sig.s == nil {
+			return nil, errors.Errorf("signature at index %d is not a valid blst signature", i)
+		}
+		rawSigs[i] = sig.s
+		contributors += sig.contributors
 	}
 
 	signature := new(blstAggregateSignature).Aggregate(rawSigs)
 	if signature == nil {
-		return nil
+		return nil, errors.New("could not aggregate signatures")
 	}
-	return &Signature{s: signature.ToAffine()}
+	return &Signature{s: signature.ToAffine(), contributors: contributors}, nil
 }
 
 // Aggregate is an alias for AggregateSignatures, defined to conform to BLS specification.
@@ -151,7 +361,7 @@ func AggregateSignatures(sigs []common.Signature) common.Signature {
 // def Aggregate(signatures: Sequence[BLSSignature]) -> BLSSignature
 //
 // Deprecated: Use AggregateSignatures.
-func Aggregate(sigs []common.Signature) common.Signature {
+func Aggregate(sigs []common.Signature) (common.Signature, error) {
 	return AggregateSignatures(sigs)
 }
 
@@ -162,7 +372,19 @@ func Aggregate(sigs []common.Signature) common.Signature {
 // P'_{i,j} = P_{i,j} * r_i
 // e(S*, G) = \prod_{i=1}^n \prod_{j=1}^{m_i} e(P'_{i,j}, M_{i,j})
 // Using this we can verify multiple signatures safely.
-func VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey) (bool, error) {
+func VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey) (verified bool, err error) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("VerifyMultipleSignatures", sigs, tracePublicKeys(pubKeys), common.MessagesBytes(msgs), verified)
+		}()
+	}
+	// Secure source of RNG
+	return verifyMultipleSignatures(sigs, msgs, pubKeys, newRandFunc(rand.NewGenerator()))
+}
+
+func verifyMultipleSignatures(
+	sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey, randFunc func(*blst.Scalar),
+) (bool, error) {
 	if featureconfig.Get().SkipBLSVerify {
 		return true, nil
 	}
@@ -180,38 +402,371 @@ func VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []common.P
 	rawMsgs := make([]blst.Message, length)
 
 	for i := 0; i < length; i++ {
-		mulP1Aff[i] = pubKeys[i].(*PublicKey).p
+		pub, ok := validPublicKey(pubKeys[i])
+		if !ok {
+			return false, errors.Errorf("public key at index %d is not a valid blst public key", i)
+		}
+		mulP1Aff[i] = pub.p
 		rawMsgs[i] = msgs[i][:]
 	}
-	// Secure source of RNG
-	randGen := rand.NewGenerator()
+	dummySig := new(blstSignature)
+	return dummySig.MultipleAggregateVerify(rawSigs, mulP1Aff, rawMsgs, signingDST(), randFunc, randBitsEntropy), nil
+}
 
-	randFunc := func(scalar *blst.Scalar) {
-		var rbytes [scalarBytes]byte
-		randGen.Read(rbytes[:])
-		scalar.FromBEndian(rbytes[:])
+// VerifyMultipleSignaturesParallel verifies a non-singular set of signatures like
+// VerifyMultipleSignatures, but splits very large sets into partitions which are verified
+// concurrently by at most the given number of workers, or GOMAXPROCS workers if it is not
+// positive. Identical signature, message and public key entries are only verified once,
+// across the whole set. The set verifies only if every partition does, and an error
+// verifying any partition is returned.
+func VerifyMultipleSignaturesParallel(
+	sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey, workers int,
+) (verified bool, err error) {
+	if common.TracingVerification() {
+		// Duplicates are removed from the arguments below, so the trace keeps the originals.
+		defer func(sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey) {
+			common.TraceVerification("VerifyMultipleSignaturesParallel", sigs, tracePublicKeys(pubKeys), common.MessagesBytes(msgs), verified)
+		}(sigs, msgs, pubKeys)
 	}
-	dummySig := new(blstSignature)
-	return dummySig.MultipleAggregateVerify(rawSigs, mulP1Aff, rawMsgs, dst, randFunc, randBitsEntropy), nil
+	if featureconfig.Get().SkipBLSVerify {
+		return true, nil
+	}
+	if len(sigs) == 0 || len(pubKeys) == 0 {
+		return false, nil
+	}
+	if len(sigs) != len(pubKeys) || len(sigs) != len(msgs) {
+		return false, errors.Errorf("provided signatures, pubkeys and messages have differing lengths. S: %d, P: %d,M %d",
+			len(sigs), len(pubKeys), len(msgs))
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	sigs, msgs, pubKeys, err = removeDuplicateSignatures(sigs, msgs, pubKeys)
+	if err != nil {
+		return false, err
+	}
+	// Small partitions are not worth the extra final exponentiation each one costs.
+	partitionSize := (len(sigs) + workers - 1) / workers
+	if partitionSize < minSignaturesPerPartition {
+		partitionSize = minSignaturesPerPartition
+	}
+	numPartitions := (len(sigs) + partitionSize - 1) / partitionSize
+	type result struct {
+		verified bool
+		err      error
+	}
+	results := make(chan result, numPartitions)
+	for start := 0; start < len(sigs); start += partitionSize {
+		end := start + partitionSize
+		if end > len(sigs) {
+			end = len(sigs)
+		}
+		go func(start, end int) {
+			// The generator is not safe for concurrent use, so each partition has its own.
+			verified, err := verifyMultipleSignatures(
+				sigs[start:end], msgs[start:end], pubKeys[start:end], newRandFunc(rand.NewGenerator()),
+			)
+			if err != nil {
+				err = errors.Wrapf(err, "could not verify signatures %d to %d", start, end)
+			}
+			results <- result{verified: verified, err: err}
+		}(start, end)
+	}
+	verified = true
+	for i := 0; i < numPartitions; i++ {
+		res := <-results
+		if res.err != nil && err == nil {
+			err = res.err
+		}
+		verified = res.verified && verified
+	}
+	if err != nil {
+		return false, err
+	}
+	return verified, nil
+}
+
+// Returns the public key as a blst public key. A nil or foreign key can only come from
+// a malformed input, so it is counted and rejected rather than causing a panic.
+func validPublicKey(pubKey common.PublicKey) (*PublicKey, bool) {
+	pub, ok := pubKey.(*PublicKey)
+	if !ok || pub == nil || pub.p == nil {
+		common.MalformedPublicKeys.Inc()
+		return nil, false
+	}
+	return pub, true
+}
+
+// Removes entries with the same signature, message and public key as an earlier entry,
+// keeping the entries in the order they were first seen.
+func removeDuplicateSignatures(
+	sigs [][]byte, msgs [][32]byte, pubKeys []common.PublicKey,
+) ([][]byte, [][32]byte, []common.PublicKey, error) {
+	seen := make(map[string]bool, len(sigs))
+	uniqueSigs := make([][]byte, 0, len(sigs))
+	uniqueMsgs := make([][32]byte, 0, len(sigs))
+	uniquePubKeys := make([]common.PublicKey, 0, len(sigs))
+	for i := range sigs {
+		pub, ok := pubKeys[i].(*PublicKey)
+		if !ok || pub == nil || pub.p == nil {
+			return nil, nil, nil, fmt.Errorf("public key at index %d is not a valid blst public key", i)
+		}
+		key := string(sigs[i]) + string(msgs[i][:]) + string(pub.Marshal())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		uniqueSigs = append(uniqueSigs, sigs[i])
+		uniqueMsgs = append(uniqueMsgs, msgs[i])
+		uniquePubKeys = append(uniquePubKeys, pubKeys[i])
+	}
+	return uniqueSigs, uniqueMsgs, uniquePubKeys, nil
 }
 
 // Marshal a signature into a LittleEndian byte slice.
 func (s *Signature) Marshal() []byte {
-	if featureconfig.Get().SkipBLSVerify {
+	if featureconfig.Get().SkipBLSVerify || s.s == nil {
 		return make([]byte, params.BeaconConfig().BLSSignatureLength)
 	}
 
 	return s.s.Compress()
 }
 
-// Copy returns a full deep copy of a signature.
+// MarshalLE marshals a signature with each of its field elements in reversed byte order,
+// for interop with legacy tools. Use Marshal for the consensus standard serialization.
+func (s *Signature) MarshalLE() []byte {
+	return reverseFieldElements(s.Marshal())
+}
+
+// Reverses the byte order of each field element of a compressed signature,
+// leaving the order of the elements themselves unchanged.
+func reverseFieldElements(sig []byte) []byte {
+	reversed := make([]byte, len(sig))
+	for start := 0; start < len(sig); start += fieldElementBytes {
+		end := start + fieldElementBytes
+		if end > len(sig) {
+			end = len(sig)
+		}
+		for i := start; i < end; i++ {
+			reversed[i] = sig[end-1-(i-start)]
+		}
+	}
+	return reversed
+}
+
+// MarshalHex returns the 0x-prefixed hex encoding of the compressed signature.
+func (s *Signature) MarshalHex() string {
+	return fmt.Sprintf("%#x", s.Marshal())
+}
+
+// String returns the 0x-prefixed hex encoding of the compressed signature, for logging.
+// Placeholder signatures, as read with SkipBLSVerify set, are written as "0xmock".
+func (s *Signature) String() string {
+	if featureconfig.Get().SkipBLSVerify || s.s == nil {
+		return placeholderHex
+	}
+	return s.MarshalHex()
+}
+
+// Equals checks whether two signatures are the same point, without
+// compressing them as comparing their marshaled bytes would.
+func (s *Signature) Equals(other common.Signature) bool {
+	if featureconfig.Get().SkipBLSVerify {
+		return true
+	}
+	otherSig, ok := other.(*Signature)
+	if !ok || otherSig == nil || s.s == nil || otherSig.s == nil {
+		return false
+	}
+	return s.s.Equals(otherSig.s)
+}
+
+// ContributorCount returns the number of signatures aggregated into the signature.
+func (s *Signature) ContributorCount() int {
+	return s.contributors
+}
+
+// IsInfinite checks if the signature is the point at infinity, which is what
+// signatures aggregate to when they cancel each other out.
+func (s *Signature) IsInfinite() bool {
+	if s.s == nil {
+		return false
+	}
+	zeroSig := new(blstSignature)
+	return s.s.Equals(zeroSig)
+}
+
+// Copy returns a full deep copy of a signature. The copy of a placeholder
+// signature, as read with SkipBLSVerify set, is a placeholder.
 func (s *Signature) Copy() common.Signature {
+	if s.s == nil {
+		return &Signature{contributors: s.contributors, dst: s.dst}
+	}
 	sign := *s.s
-	return &Signature{s: &sign}
+	return &Signature{s: &sign, contributors: s.contributors, dst: s.dst}
+}
+
+// The domain separation tag the signature is verified under.
+func (s *Signature) domainSeparationTag() []byte {
+	if s.dst == nil {
+		return signingDST()
+	}
+	return s.dst
 }
 
 // VerifyCompressed verifies that the compressed signature and pubkey
 // are valid from the message provided.
-func VerifyCompressed(signature []byte, pub []byte, msg []byte) bool {
-	return new(blstSignature).VerifyCompressed(signature, pub, msg, dst)
+func VerifyCompressed(signature []byte, pub []byte, msg []byte) (verified bool) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("VerifyCompressed", [][]byte{signature}, [][]byte{pub}, [][]byte{msg}, verified)
+		}()
+	}
+	return new(blstSignature).VerifyCompressed(signature, pub, msg, signingDST())
+}
+
+// VerifyCompressedBatch verifies independent triples of compressed signatures, public keys
+// and messages, like VerifyCompressed does for one triple, with a single pairing batch. As
+// in VerifyMultipleSignatures, each signature is multiplied by a random scalar, so invalid
+// signatures can not cancel each other out. The batch verifies only if every triple does,
+// and a malformed signature or public key fails it like VerifyCompressed would.
+func VerifyCompressedBatch(sigs, pubs, msgs [][]byte) (verified bool, err error) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("VerifyCompressedBatch", sigs, pubs, msgs, verified)
+		}()
+	}
+	return verifyCompressedBatch(sigs, pubs, msgs, newRandFunc(rand.NewGenerator()))
+}
+
+func verifyCompressedBatch(sigs, pubs, msgs [][]byte, randFunc func(*blst.Scalar)) (bool, error) {
+	if featureconfig.Get().SkipBLSVerify {
+		return true, nil
+	}
+	length := len(sigs)
+	if length != len(pubs) || length != len(msgs) {
+		return false, errors.Errorf("provided signatures, pubkeys and messages have differing lengths. S: %d, P: %d,M %d",
+			length, len(pubs), len(msgs))
+	}
+	if length == 0 {
+		return false, nil
+	}
+	rawSigs := make([]*blstSignature, length)
+	rawPubs := make([]*blstPublicKey, length)
+	rawMsgs := make([]blst.Message, length)
+	for i := 0; i < length; i++ {
+		sig, err := SignatureFromBytes(sigs[i])
+		if err != nil {
+			return false, nil
+		}
+		pub, err := PublicKeyFromBytes(pubs[i])
+		if err != nil {
+			return false, nil
+		}
+		rawSigs[i] = sig.(*Signature).s
+		rawPubs[i] = pub.(*PublicKey).p
+		rawMsgs[i] = msgs[i]
+	}
+	dummySig := new(blstSignature)
+	return dummySig.MultipleAggregateVerify(rawSigs, rawPubs, rawMsgs, signingDST(), randFunc, randBitsEntropy), nil
+}
+
+// VerifyExpectDST verifies a bls signature given a public key and a message, after
+// checking that the domain separation tag used for verification is the expected one.
+// A mismatch is returned as an error rather than verifying under the wrong ciphersuite.
+// Signatures created with SignatureFromBytesWithDST are checked against their own tag.
+func VerifyExpectDST(pubKey common.PublicKey, msg []byte, sig common.Signature, expectedDST []byte) (bool, error) {
+	if pubKey == nil || sig == nil {
+		return false, errors.New("nil public key or signature")
+	}
+	dst := signingDST()
+	if s, ok := sig.(*Signature); ok {
+		if s == nil {
+			return false, errors.New("nil public key or signature")
+		}
+		dst = s.domainSeparationTag()
+	}
+	if !bytes.Equal(dst, expectedDST) {
+		return false, fmt.Errorf("domain separation tag %q does not match expected %q", dst, expectedDST)
+	}
+	return sig.Verify(pubKey, msg), nil
+}
+
+// SignatureFromBytesWithDST creates a BLS signature from a LittleEndian byte slice, like
+// SignatureFromBytes, which is verified under the given domain separation tag rather than
+// the one of the network config.
+func SignatureFromBytesWithDST(sig []byte, domainSeparationTag []byte) (common.Signature, error) {
+	if len(domainSeparationTag) == 0 {
+		return nil, errEmptyDST
+	}
+	signature, err := SignatureFromBytes(sig)
+	if err != nil {
+		return nil, err
+	}
+	signature.(*Signature).dst = copyDST(domainSeparationTag)
+	return signature, nil
+}
+
+// SignWithDST signs a message with a secret key under the given domain separation tag,
+// such as the one of the basic ciphersuite, rather than the one of the network config.
+// The returned signature is verified under the same tag.
+func SignWithDST(secretKey common.SecretKey, msg []byte, domainSeparationTag []byte) (common.Signature, error) {
+	if len(domainSeparationTag) == 0 {
+		return nil, errEmptyDST
+	}
+	key, ok := secretKey.(*bls12SecretKey)
+	if !ok || key == nil {
+		return nil, errors.New("secret key is not a blst secret key")
+	}
+	if key.destroyed {
+		return nil, common.ErrDestroyedKey
+	}
+	if featureconfig.Get().SkipBLSVerify {
+		return &Signature{contributors: 1}, nil
+	}
+	signature := new(blstSignature).Sign(key.p, msg, domainSeparationTag)
+	return &Signature{s: signature, contributors: 1, dst: copyDST(domainSeparationTag)}, nil
+}
+
+// VerifyWithDST verifies a bls signature given a public key and a message under the given
+// domain separation tag, regardless of the tag the signature was created with.
+func VerifyWithDST(pubKey common.PublicKey, msg []byte, sig common.Signature, domainSeparationTag []byte) (bool, error) {
+	if len(domainSeparationTag) == 0 {
+		return false, errEmptyDST
+	}
+	signature, ok := sig.(*Signature)
+	if !ok || signature == nil {
+		return false, errors.New("signature is not a blst signature")
+	}
+	publicKey, ok := pubKey.(*PublicKey)
+	if !ok || publicKey == nil {
+		return false, errors.New("public key is not a blst public key")
+	}
+	if featureconfig.Get().SkipBLSVerify {
+		return true, nil
+	}
+	if signature.contributors == 0 || signature.IsInfinite() || publicKey.IsInfinite() {
+		return false, nil
+	}
+	return signature.s.Verify(publicKey.p, msg, domainSeparationTag), nil
+}
+
+// VerifyMultiDST verifies a bls signature given a public key and a message under each of
+// the given domain separation tags in turn, and returns true if it verifies under any of them.
+// During a ciphersuite migration, this accepts signatures under both the old and the new tag.
+func VerifyMultiDST(pubKey common.PublicKey, msg []byte, sig common.Signature, dsts [][]byte) bool {
+	for _, dst := range dsts {
+		if verified, err := VerifyWithDST(pubKey, msg, sig, dst); err == nil && verified {
+			return true
+		}
+	}
+	return false
+}
+
+var errEmptyDST = errors.New("domain separation tag must not be empty")
+
+func copyDST(domainSeparationTag []byte) []byte {
+	copied := make([]byte, len(domainSeparationTag))
+	copy(copied, domainSeparationTag)
+	return copied
 }
@@ -5,12 +5,20 @@ package blst
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/sirupsen/logrus"
+	logTest "github.com/sirupsen/logrus/hooks/test"
 )
 
 func TestSignVerify(t *testing.T) {
@@ -36,10 +44,118 @@ func TestAggregateVerify(t *testing.T) {
 		sigs = append(sigs, sig)
 		msgs = append(msgs, msg)
 	}
-	aggSig := Aggregate(sigs)
+	aggSig, err := Aggregate(sigs)
+	require.NoError(t, err)
 	assert.Equal(t, true, aggSig.AggregateVerify(pubkeys, msgs), "Signature did not verify")
 }
 
+func TestAggregateVerify_NilPublicKey(t *testing.T) {
+	var pubkeys []common.PublicKey
+	var sigs []common.Signature
+	var msgs [][32]byte
+	for i := 0; i < 3; i++ {
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]))
+		msgs = append(msgs, msg)
+	}
+	aggSig, err := Aggregate(sigs)
+	require.NoError(t, err)
+	require.Equal(t, true, aggSig.AggregateVerify(pubkeys, msgs), "Signature did not verify")
+	rejected := testutil.ToFloat64(common.MalformedPublicKeys)
+
+	for name, malformed := range malformedPublicKeys() {
+		keys := append([]common.PublicKey{}, pubkeys...)
+		keys[1] = malformed
+		assert.Equal(t, false, aggSig.AggregateVerify(keys, msgs), "Signature verified with a %s public key", name)
+	}
+	assert.Equal(t, rejected+3, testutil.ToFloat64(common.MalformedPublicKeys))
+}
+
+// The public keys which can only come from a malformed input.
+func malformedPublicKeys() map[string]common.PublicKey {
+	var nilKey *PublicKey
+	return map[string]common.PublicKey{
+		"nil interface": nil,
+		"nil pointer":   nilKey,
+		"nil point":     &PublicKey{},
+	}
+}
+
+func TestVerify_NilPublicKey(t *testing.T) {
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	priv, err := RandKey()
+	require.NoError(t, err)
+	sig := priv.Sign(msg[:])
+	rejected := testutil.ToFloat64(common.MalformedPublicKeys)
+	for name, malformed := range malformedPublicKeys() {
+		assert.Equal(t, false, sig.Verify(malformed, msg[:]), "Signature verified with a %s public key", name)
+	}
+	assert.Equal(t, rejected+3, testutil.ToFloat64(common.MalformedPublicKeys))
+}
+
+func TestFastAggregateVerify_NilPublicKey(t *testing.T) {
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	var pubkeys []common.PublicKey
+	var sigs []common.Signature
+	for i := 0; i < 3; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]))
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	require.Equal(t, true, aggSig.FastAggregateVerify(pubkeys, msg), "Signature did not verify")
+	rejected := testutil.ToFloat64(common.MalformedPublicKeys)
+	for name, malformed := range malformedPublicKeys() {
+		keys := append([]common.PublicKey{}, pubkeys...)
+		keys[1] = malformed
+		assert.Equal(t, false, aggSig.FastAggregateVerify(keys, msg), "Signature verified with a %s public key", name)
+	}
+	assert.Equal(t, rejected+3, testutil.ToFloat64(common.MalformedPublicKeys))
+}
+
+func TestAggregateSignatures_NilSignature(t *testing.T) {
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	priv, err := RandKey()
+	require.NoError(t, err)
+	var nilSig *Signature
+	for name, malformed := range map[string]common.Signature{
+		"nil interface": nil,
+		"nil pointer":   nilSig,
+		"nil point":     &Signature{contributors: 1},
+	} {
+		_, err := AggregateSignatures([]common.Signature{priv.Sign(msg[:]), malformed})
+		assert.ErrorContains(t, "signature at index 1 is not a valid", err, "Aggregated a %s signature", name)
+	}
+}
+
+func TestVerifyMultipleSignatures_NilPublicKey(t *testing.T) {
+	var pubkeys []common.PublicKey
+	var sigs [][]byte
+	var msgs [][32]byte
+	for i := 0; i < 3; i++ {
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]).Marshal())
+		msgs = append(msgs, msg)
+	}
+	rejected := testutil.ToFloat64(common.MalformedPublicKeys)
+	for name, malformed := range malformedPublicKeys() {
+		keys := append([]common.PublicKey{}, pubkeys...)
+		keys[1] = malformed
+		verified, err := VerifyMultipleSignatures(sigs, msgs, keys)
+		assert.ErrorContains(t, "public key at index 1 is not a valid blst public key", err, "Verified with a %s public key", name)
+		assert.Equal(t, false, verified)
+	}
+	assert.Equal(t, rejected+3, testutil.ToFloat64(common.MalformedPublicKeys))
+}
+
 func TestFastAggregateVerify(t *testing.T) {
 	pubkeys := make([]common.PublicKey, 0, 100)
 	sigs := make([]common.Signature, 0, 100)
@@ -52,11 +168,44 @@ func TestFastAggregateVerify(t *testing.T) {
 		pubkeys = append(pubkeys, pub)
 		sigs = append(sigs, sig)
 	}
-	aggSig := AggregateSignatures(sigs)
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
 	assert.Equal(t, true, aggSig.FastAggregateVerify(pubkeys, msg), "Signature did not verify")
 
 }
 
+func TestSignature_IsAggregate(t *testing.T) {
+	pubkeys := make([]common.PublicKey, 0, 4)
+	sigs := make([]common.Signature, 0, 4)
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	for i := 0; i < 4; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]))
+	}
+	aggSig, err := AggregateSignatures(sigs[:3])
+	require.NoError(t, err)
+
+	assert.Equal(t, true, aggSig.IsAggregate(3, pubkeys[:3], msg), "Aggregate of 3 signers not recognized")
+	assert.Equal(t, false, aggSig.IsAggregate(2, pubkeys[:3], msg), "Mismatched signer count accepted")
+	assert.Equal(t, false, aggSig.IsAggregate(2, pubkeys[:2], msg), "Aggregate accepted for a subset of its signers")
+	assert.Equal(t, false, aggSig.IsAggregate(4, pubkeys, msg), "Aggregate accepted for a superset of its signers")
+	assert.Equal(t, false, aggSig.IsAggregate(3, pubkeys[1:], msg), "Aggregate accepted for a different committee")
+	assert.Equal(t, false, aggSig.IsAggregate(3, pubkeys[:3], [32]byte{'o', 't', 'h', 'e', 'r'}), "Aggregate accepted for another message")
+	assert.Equal(t, false, aggSig.IsAggregate(0, nil, msg), "Aggregate of no signers accepted")
+
+	// A single signature is an aggregate of one signer, but not of a committee.
+	assert.Equal(t, true, sigs[0].IsAggregate(1, pubkeys[:1], msg))
+
+	// Signing twice with one key does not make an aggregate of two signers.
+	doubleSig, err := AggregateSignatures([]common.Signature{sigs[0], sigs[0]})
+	require.NoError(t, err)
+	doubleKeys := []common.PublicKey{pubkeys[0], pubkeys[0]}
+	require.Equal(t, true, doubleSig.FastAggregateVerify(doubleKeys, msg))
+	assert.Equal(t, false, doubleSig.IsAggregate(2, doubleKeys, msg), "Duplicate signers accepted")
+}
+
 func TestVerifyCompressed(t *testing.T) {
 	priv, err := RandKey()
 	require.NoError(t, err)
@@ -67,6 +216,262 @@ func TestVerifyCompressed(t *testing.T) {
 	assert.Equal(t, true, VerifyCompressed(sig.Marshal(), pub.Marshal(), msg), "Compressed signatures and pubkeys did not verify")
 }
 
+func TestVerifyExpectDST(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	msg := []byte("hello")
+	sig := priv.Sign(msg)
+
+	verified, err := VerifyExpectDST(pub, msg, sig, []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"))
+	require.NoError(t, err)
+	assert.Equal(t, true, verified, "Signature did not verify")
+
+	verified, err = VerifyExpectDST(pub, []byte("other"), sig, []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"))
+	require.NoError(t, err)
+	assert.Equal(t, false, verified, "Signature verified for the wrong message")
+
+	// The basic scheme ciphersuite differs from the proof of possession one used for signing.
+	_, err = VerifyExpectDST(pub, msg, sig, []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"))
+	assert.ErrorContains(t, "does not match expected", err)
+
+	_, err = VerifyExpectDST(nil, msg, sig, []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"))
+	assert.ErrorContains(t, "nil public key or signature", err)
+}
+
+func TestSignVerify_ConfiguredDST(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	privs := make([]common.SecretKey, 3)
+	pubKeys := make([]common.PublicKey, len(privs))
+	mainnetSigs := make([]common.Signature, len(privs))
+	for i := range privs {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		privs[i] = priv
+		pubKeys[i] = priv.PublicKey()
+		mainnetSigs[i] = priv.Sign(msg[:])
+	}
+
+	cfg := params.BeaconConfig().Copy()
+	cfg.BLSDomainSeparationTag = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_PRYSM_TESTNET_"
+	params.OverrideBeaconConfig(cfg)
+	sigs := make([]common.Signature, len(privs))
+	for i, priv := range privs {
+		sigs[i] = priv.Sign(msg[:])
+	}
+
+	msgs := [][32]byte{msg, msg, msg}
+	for _, tt := range []struct {
+		name     string
+		sigs     []common.Signature
+		verifies bool
+	}{
+		{name: "configured DST", sigs: sigs, verifies: true},
+		{name: "mainnet DST", sigs: mainnetSigs, verifies: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.verifies, tt.sigs[0].Verify(pubKeys[0], msg[:]))
+			aggSig, err := AggregateSignatures(tt.sigs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.verifies, aggSig.AggregateVerify(pubKeys, msgs))
+			assert.Equal(t, tt.verifies, aggSig.FastAggregateVerify(pubKeys, msg))
+			rawSigs := make([][]byte, len(tt.sigs))
+			for i, sig := range tt.sigs {
+				rawSigs[i] = sig.Marshal()
+			}
+			verified, err := VerifyMultipleSignatures(rawSigs, msgs, pubKeys)
+			require.NoError(t, err)
+			assert.Equal(t, tt.verifies, verified)
+		})
+	}
+}
+
+func TestSignWithDST_BasicCiphersuite(t *testing.T) {
+	basicDST := []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+	// Reference vector for the basic ciphersuite computed with kilic/bls12-381,
+	// an independent implementation of the IETF BLS signature draft.
+	privBytes, err := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	require.NoError(t, err)
+	wantPub, err := hex.DecodeString("a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a")
+	require.NoError(t, err)
+	wantSig, err := hex.DecodeString("b857b06bd36aaa9e92c3fb2c8bbfddc62fe79012eb095841924f2c013cbde3512b572fa127d81f8c1281d391079c60a509b902898460cb4470f867b814c86648d11e1224b17845bcfa86ccf0139cc9fb796c36be6152c0b23f935a17caffe6e7")
+	require.NoError(t, err)
+	msg := []byte("prysm basic ciphersuite interop")
+
+	priv, err := SecretKeyFromBytes(privBytes)
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	assert.DeepEqual(t, wantPub, pub.Marshal())
+	sig, err := SignWithDST(priv, msg, basicDST)
+	require.NoError(t, err)
+	assert.DeepEqual(t, wantSig, sig.Marshal())
+	assert.Equal(t, true, sig.Verify(pub, msg), "Signature did not verify under its own ciphersuite")
+
+	verified, err := VerifyWithDST(pub, msg, sig, basicDST)
+	require.NoError(t, err)
+	assert.Equal(t, true, verified, "Signature did not verify under the basic ciphersuite")
+	verified, err = VerifyWithDST(pub, msg, sig, signingDST())
+	require.NoError(t, err)
+	assert.Equal(t, false, verified, "Basic ciphersuite signature verified under the POP ciphersuite")
+
+	// The reference signature only verifies once it is parsed with the basic ciphersuite tag.
+	parsed, err := SignatureFromBytes(wantSig)
+	require.NoError(t, err)
+	assert.Equal(t, false, parsed.Verify(pub, msg), "Basic ciphersuite signature verified under the POP ciphersuite")
+	parsed, err = SignatureFromBytesWithDST(wantSig, basicDST)
+	require.NoError(t, err)
+	assert.Equal(t, true, parsed.Verify(pub, msg), "Signature did not verify under the basic ciphersuite")
+	assert.Equal(t, true, parsed.Copy().Verify(pub, msg), "Copied signature did not keep its ciphersuite")
+	verified, err = VerifyExpectDST(pub, msg, parsed, basicDST)
+	require.NoError(t, err)
+	assert.Equal(t, true, verified, "Signature did not verify under its expected ciphersuite")
+	_, err = VerifyExpectDST(pub, msg, parsed, signingDST())
+	assert.ErrorContains(t, "does not match expected", err)
+
+	// Signing with the POP tag explicitly matches the default.
+	popSig, err := SignWithDST(priv, msg, signingDST())
+	require.NoError(t, err)
+	assert.DeepEqual(t, priv.Sign(msg).Marshal(), popSig.Marshal())
+}
+
+func TestWithDST_EmptyDST(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	msg := []byte("hello")
+	sig := priv.Sign(msg)
+
+	_, err = SignWithDST(priv, msg, nil)
+	assert.ErrorContains(t, "domain separation tag must not be empty", err)
+	_, err = VerifyWithDST(priv.PublicKey(), msg, sig, []byte{})
+	assert.ErrorContains(t, "domain separation tag must not be empty", err)
+	_, err = SignatureFromBytesWithDST(sig.Marshal(), nil)
+	assert.ErrorContains(t, "domain separation tag must not be empty", err)
+
+	priv.Destroy()
+	_, err = SignWithDST(priv, msg, signingDST())
+	assert.ErrorContains(t, common.ErrDestroyedKey.Error(), err)
+}
+
+func TestVerifyMultiDST(t *testing.T) {
+	oldDST := []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+	newDST := []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_")
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	msg := []byte("hello")
+	sig, err := SignWithDST(priv, msg, newDST)
+	require.NoError(t, err)
+
+	assert.Equal(t, true, VerifyMultiDST(pub, msg, sig, [][]byte{oldDST, newDST}), "Signature did not verify under its tag")
+	assert.Equal(t, false, VerifyMultiDST(pub, msg, sig, [][]byte{oldDST, signingDST()}), "Signature verified under another tag")
+	assert.Equal(t, false, VerifyMultiDST(pub, []byte("other"), sig, [][]byte{oldDST, newDST}), "Signature verified for another message")
+	assert.Equal(t, false, VerifyMultiDST(pub, msg, sig, [][]byte{nil, {}}), "Signature verified under empty tags")
+	assert.Equal(t, false, VerifyMultiDST(pub, msg, sig, nil), "Signature verified without any tag")
+}
+
+func TestSignature_IsInfinite(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	sig := priv.Sign(msg[:])
+	assert.Equal(t, false, sig.IsInfinite())
+
+	infiniteSigBytes := make([]byte, 96)
+	infiniteSigBytes[0] = 0xc0
+	infiniteSig, err := SignatureFromBytes(infiniteSigBytes)
+	require.NoError(t, err)
+	assert.Equal(t, true, infiniteSig.IsInfinite())
+	assert.Equal(t, false, infiniteSig.Verify(pub, msg[:]))
+	assert.Equal(t, false, infiniteSig.AggregateVerify([]common.PublicKey{pub}, [][32]byte{msg}))
+	assert.Equal(t, false, infiniteSig.FastAggregateVerify([]common.PublicKey{pub}, msg))
+
+	// Verification with the infinite public key fails rather than being attempted.
+	infinitePub := &PublicKey{p: new(blstPublicKey)}
+	require.Equal(t, true, infinitePub.IsInfinite())
+	assert.Equal(t, false, sig.Verify(infinitePub, msg[:]))
+	assert.Equal(t, false, sig.AggregateVerify([]common.PublicKey{infinitePub}, [][32]byte{msg}))
+	assert.Equal(t, false, sig.FastAggregateVerify([]common.PublicKey{pub, infinitePub}, msg))
+}
+
+func TestSignature_InfinityAggregateVerify(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	sig := priv.Sign(msg[:])
+
+	// Flipping the sign bit of a compressed point negates it, so the negated signature
+	// and public key cancel out the original ones when aggregated.
+	negSigBytes := sig.Marshal()
+	negSigBytes[0] ^= 0x20
+	negSig, err := SignatureFromBytes(negSigBytes)
+	require.NoError(t, err)
+	negPubBytes := pub.Marshal()
+	negPubBytes[0] ^= 0x20
+	negPub, err := PublicKeyFromBytes(negPubBytes)
+	require.NoError(t, err)
+	require.Equal(t, true, negSig.Verify(negPub, msg[:]), "Negated signature did not verify")
+	infiniteSig, err := AggregateSignatures([]common.Signature{sig, negSig})
+	require.NoError(t, err)
+	require.Equal(t, true, infiniteSig.IsInfinite())
+	require.Equal(t, 2, infiniteSig.ContributorCount())
+
+	// The public keys aggregate to the infinity point too, so the pairing check alone would pass.
+	pubKeys := []common.PublicKey{pub, negPub}
+	assert.Equal(t, false, infiniteSig.FastAggregateVerify(pubKeys, msg))
+	assert.Equal(t, false, infiniteSig.AggregateVerify(pubKeys, [][32]byte{msg, msg}))
+	assert.Equal(t, false, infiniteSig.FastAggregateVerify(nil, msg))
+	assert.Equal(t, false, infiniteSig.AggregateVerify(nil, nil))
+	assert.Equal(t, false, infiniteSig.AggregateVerify(pubKeys, nil))
+
+	// The empty set is invalid for any signature.
+	assert.Equal(t, false, sig.FastAggregateVerify(nil, msg))
+	assert.Equal(t, false, sig.AggregateVerify(nil, nil))
+}
+
+func TestSignaturesFromBytes(t *testing.T) {
+	numSigs := 20
+	sigs := make([][]byte, numSigs)
+	for i := 0; i < numSigs; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		sigs[i] = priv.Sign([]byte{byte(i)}).Marshal()
+	}
+	for _, workers := range []int{0, 1, 4, 2 * numSigs} {
+		signatures, err := SignaturesFromBytes(sigs, workers)
+		require.NoError(t, err)
+		require.Equal(t, numSigs, len(signatures))
+		for i, sig := range signatures {
+			assert.DeepEqual(t, sigs[i], sig.Marshal(), "Signature %d out of order with %d workers", i, workers)
+		}
+	}
+	signatures, err := SignaturesFromBytes(nil, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(signatures))
+}
+
+func TestSignaturesFromBytes_LowestFailingIndex(t *testing.T) {
+	numSigs := 64
+	sigs := make([][]byte, numSigs)
+	for i := 0; i < numSigs; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		sigs[i] = priv.Sign([]byte{byte(i)}).Marshal()
+	}
+	// Several malformed entries mid-batch, the lowest of which must always be reported.
+	sigs[23] = make([]byte, 96)
+	sigs[24] = sigs[24][:95]
+	sigs[50] = make([]byte, 96)
+	for run := 0; run < 50; run++ {
+		_, err := SignaturesFromBytes(sigs, 8)
+		assert.ErrorContains(t, "invalid signature at index 23: could not unmarshal bytes into signature", err)
+	}
+	_, err := SignaturesFromBytes(sigs, 1)
+	assert.ErrorContains(t, "invalid signature at index 23", err)
+}
+
 func TestMultipleSignatureVerification(t *testing.T) {
 	pubkeys := make([]common.PublicKey, 0, 100)
 	sigs := make([][]byte, 0, 100)
@@ -86,6 +491,103 @@ func TestMultipleSignatureVerification(t *testing.T) {
 	assert.Equal(t, true, verify, "Signature did not verify")
 }
 
+func TestVerifyMultipleSignaturesParallel(t *testing.T) {
+	numSigs := 300
+	pubkeys := make([]common.PublicKey, numSigs)
+	sigs := make([][]byte, numSigs)
+	msgs := make([][32]byte, numSigs)
+	for i := 0; i < numSigs; i++ {
+		msgs[i] = [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys[i] = priv.PublicKey()
+		sigs[i] = priv.Sign(msgs[i][:]).Marshal()
+	}
+	// Without a positive number of workers, GOMAXPROCS workers are used.
+	for _, workers := range []int{1, 4, 16, 0, -1} {
+		verified, err := VerifyMultipleSignaturesParallel(sigs, msgs, pubkeys, workers)
+		require.NoError(t, err)
+		assert.Equal(t, true, verified, "Signatures did not verify with %d workers", workers)
+	}
+
+	// An invalid signature in any partition fails the whole set.
+	for _, i := range []int{0, numSigs - 1} {
+		badMsgs := make([][32]byte, numSigs)
+		copy(badMsgs, msgs)
+		badMsgs[i] = [32]byte{'b', 'a', 'd'}
+		verified, err := VerifyMultipleSignaturesParallel(sigs, badMsgs, pubkeys, 4)
+		require.NoError(t, err)
+		assert.Equal(t, false, verified, "Signatures verified with an invalid entry at index %d", i)
+	}
+	// A signature by another key over the same message is a valid point, but still fails the set.
+	badSigs := make([][]byte, numSigs)
+	copy(badSigs, sigs)
+	other, err := RandKey()
+	require.NoError(t, err)
+	badSigs[numSigs/2] = other.Sign(msgs[numSigs/2][:]).Marshal()
+	verified, err := VerifyMultipleSignaturesParallel(badSigs, msgs, pubkeys, 4)
+	require.NoError(t, err)
+	assert.Equal(t, false, verified, "Signatures verified with a corrupt signature")
+
+	_, err = VerifyMultipleSignaturesParallel(sigs, msgs[1:], pubkeys, 4)
+	assert.ErrorContains(t, "differing lengths", err)
+	badPubkeys := make([]common.PublicKey, numSigs)
+	copy(badPubkeys, pubkeys)
+	badPubkeys[7] = nil
+	_, err = VerifyMultipleSignaturesParallel(sigs, msgs, badPubkeys, 4)
+	assert.ErrorContains(t, "public key at index 7", err)
+}
+
+func TestRemoveDuplicateSignatures(t *testing.T) {
+	pubkeys := make([]common.PublicKey, 3)
+	sigs := make([][]byte, 3)
+	msgs := make([][32]byte, 3)
+	for i := 0; i < 3; i++ {
+		msgs[i] = [32]byte{byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys[i] = priv.PublicKey()
+		sigs[i] = priv.Sign(msgs[i][:]).Marshal()
+	}
+	// Entry 3 duplicates entry 1, entry 4 duplicates entry 0 but for another message.
+	order := []int{2, 0, 1, 0, 0}
+	dupSigs := make([][]byte, len(order))
+	dupMsgs := make([][32]byte, len(order))
+	dupPubkeys := make([]common.PublicKey, len(order))
+	for i, j := range order {
+		dupSigs[i], dupMsgs[i], dupPubkeys[i] = sigs[j], msgs[j], pubkeys[j]
+	}
+	dupMsgs[4] = [32]byte{'o', 't', 'h', 'e', 'r'}
+
+	uniqueSigs, uniqueMsgs, uniquePubkeys, err := removeDuplicateSignatures(dupSigs, dupMsgs, dupPubkeys)
+	require.NoError(t, err)
+	assert.DeepEqual(t, [][]byte{sigs[2], sigs[0], sigs[1], sigs[0]}, uniqueSigs)
+	assert.DeepEqual(t, [][32]byte{msgs[2], msgs[0], msgs[1], dupMsgs[4]}, uniqueMsgs)
+	assert.DeepEqual(t, []common.PublicKey{pubkeys[2], pubkeys[0], pubkeys[1], pubkeys[0]}, uniquePubkeys)
+}
+
+func TestMultipleSignatureVerification_NoErrorLogs(t *testing.T) {
+	hook := logTest.NewGlobal()
+	pubkeys := make([]common.PublicKey, 0, 10)
+	sigs := make([][]byte, 0, 10)
+	var msgs [][32]byte
+	for i := 0; i < 10; i++ {
+		// Repeat messages across the batch, as happens when verifying the attestations of a block.
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i % 3)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]).Marshal())
+		msgs = append(msgs, msg)
+	}
+	verify, err := VerifyMultipleSignatures(sigs, msgs, pubkeys)
+	require.NoError(t, err)
+	require.Equal(t, true, verify, "Signature did not verify")
+	for _, e := range hook.AllEntries() {
+		assert.Equal(t, true, e.Level > logrus.ErrorLevel, "Unexpected log at level %s: %s", e.Level, e.Message)
+	}
+}
+
 func TestFastAggregateVerify_ReturnsFalseOnEmptyPubKeyList(t *testing.T) {
 	var pubkeys []common.PublicKey
 	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
@@ -144,13 +646,21 @@ func TestSignatureFromBytes(t *testing.T) {
 	}
 }
 
+func TestSignatureFromBytes_NotInSubgroup(t *testing.T) {
+	// A point on the curve which is not in the G2 subgroup, from the deserialization_G2 spec tests.
+	notInG2, err := hex.DecodeString("8123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	_, err = SignatureFromBytes(notInG2)
+	assert.NotNil(t, err, "Signature outside of the subgroup was accepted")
+}
+
 func TestCopy(t *testing.T) {
 	priv, err := RandKey()
 	require.NoError(t, err)
 	key, ok := priv.(*bls12SecretKey)
 	require.Equal(t, true, ok)
 
-	signatureA := &Signature{s: new(blstSignature).Sign(key.p, []byte("foo"), dst)}
+	signatureA := &Signature{s: new(blstSignature).Sign(key.p, []byte("foo"), signingDST())}
 	signatureB, ok := signatureA.Copy().(*Signature)
 	require.Equal(t, true, ok)
 
@@ -158,6 +668,111 @@ func TestCopy(t *testing.T) {
 	assert.NotEqual(t, signatureA.s, signatureB.s)
 	assert.DeepEqual(t, signatureA, signatureB)
 
-	signatureA.s.Sign(key.p, []byte("bar"), dst)
+	signatureA.s.Sign(key.p, []byte("bar"), signingDST())
 	assert.DeepNotEqual(t, signatureA, signatureB)
 }
+
+func TestContributorCount(t *testing.T) {
+	blank := NewAggregateSignature()
+	assert.Equal(t, 0, blank.ContributorCount())
+	assert.Equal(t, 0, blank.Copy().ContributorCount())
+
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	pubKeys := make([]common.PublicKey, 0, 3)
+	sigs := make([]common.Signature, 0, 3)
+	for i := 0; i < 3; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		sig := priv.Sign(msg[:])
+		assert.Equal(t, 1, sig.ContributorCount())
+		pubKeys = append(pubKeys, priv.PublicKey())
+		sigs = append(sigs, sig)
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	assert.Equal(t, 3, aggSig.ContributorCount())
+	assert.Equal(t, true, aggSig.FastAggregateVerify(pubKeys, msg))
+
+	// Signatures from bytes count as a single contributor.
+	fromBytes, err := SignatureFromBytes(aggSig.Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, 1, fromBytes.ContributorCount())
+
+	// The mock seeded blank aggregate is rejected by verification.
+	assert.Equal(t, false, blank.Verify(pubKeys[0], msg[:]))
+	assert.Equal(t, false, blank.AggregateVerify(pubKeys[:1], [][32]byte{msg}))
+	assert.Equal(t, false, blank.FastAggregateVerify(pubKeys, msg))
+
+	// A valid signature point is rejected too when it has no contributors.
+	noContributors := &Signature{s: aggSig.(*Signature).s}
+	assert.Equal(t, false, noContributors.FastAggregateVerify(pubKeys, msg))
+}
+
+func TestSignatureEquals(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	msg := []byte("hello")
+	sig := priv.Sign(msg)
+	assert.Equal(t, true, sig.Equals(priv.Sign(msg)))
+	assert.Equal(t, true, sig.Equals(sig.Copy()))
+
+	// Tamper with the signature by signing a different message.
+	assert.Equal(t, false, sig.Equals(priv.Sign([]byte("tampered"))))
+	otherPriv, err := RandKey()
+	require.NoError(t, err)
+	assert.Equal(t, false, sig.Equals(otherPriv.Sign(msg)))
+	assert.Equal(t, false, sig.Equals(nil))
+}
+
+func TestSignatureLittleEndian_RoundTrip(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	msg := []byte("hello")
+	sig := priv.Sign(msg).(*Signature)
+
+	le := sig.MarshalLE()
+	be := sig.Marshal()
+	require.Equal(t, len(be), len(le))
+	assert.Equal(t, false, bytes.Equal(be, le))
+	// Each 48 byte field element is reversed in place.
+	for i := 0; i < len(be); i++ {
+		element := i / fieldElementBytes
+		offset := i % fieldElementBytes
+		assert.Equal(t, be[element*fieldElementBytes+fieldElementBytes-1-offset], le[i])
+	}
+
+	decoded, err := SignatureFromBytesLE(le)
+	require.NoError(t, err)
+	assert.DeepEqual(t, be, decoded.Marshal())
+	assert.Equal(t, true, decoded.Verify(priv.PublicKey(), msg))
+
+	// Little endian bytes are not a valid standard serialization, and vice versa.
+	_, err = SignatureFromBytes(le)
+	assert.NotNil(t, err)
+	_, err = SignatureFromBytesLE(be)
+	assert.NotNil(t, err)
+	_, err = SignatureFromBytesLE(le[1:])
+	assert.ErrorContains(t, "signature must be 96 bytes", err)
+}
+
+func TestSignature_String(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	sig := priv.Sign([]byte("hello"))
+	hexSig := fmt.Sprint(sig)
+	require.Equal(t, true, strings.HasPrefix(hexSig, "0x"), hexSig)
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(hexSig, "0x"))
+	require.NoError(t, err)
+	parsed, err := SignatureFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, true, sig.Equals(parsed), "Signature parsed from its hex string differs")
+	assert.Equal(t, hexSig, fmt.Sprint(parsed))
+
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{SkipBLSVerify: true})
+	defer reset()
+	placeholder, err := SignatureFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "0xmock", fmt.Sprint(placeholder))
+	assert.Equal(t, "0xmock", fmt.Sprint(sig))
+}
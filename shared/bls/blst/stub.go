@@ -3,6 +3,8 @@
 package blst
 
 import (
+	"io"
+
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
 )
 
@@ -22,6 +24,16 @@ func (s SecretKey) Sign(_ []byte) common.Signature {
 	panic(err)
 }
 
+// SignMessageSet -- stub
+func (s SecretKey) SignMessageSet(_ [][]byte) ([]common.Signature, error) {
+	panic(err)
+}
+
+// SignBatch -- stub
+func (s SecretKey) SignBatch(_ [][]byte) []common.Signature {
+	panic(err)
+}
+
 // Marshal -- stub
 func (s SecretKey) Marshal() []byte {
 	panic(err)
@@ -32,6 +44,16 @@ func (s SecretKey) IsZero() bool {
 	panic(err)
 }
 
+// Zeroize -- stub
+func (s SecretKey) Zeroize() {
+	panic(err)
+}
+
+// Destroy -- stub
+func (s SecretKey) Destroy() {
+	panic(err)
+}
+
 // PublicKey -- stub
 type PublicKey struct{}
 
@@ -40,6 +62,11 @@ func (p PublicKey) Marshal() []byte {
 	panic(err)
 }
 
+// MarshalHex -- stub
+func (p PublicKey) MarshalHex() string {
+	panic(err)
+}
+
 // Copy -- stub
 func (p PublicKey) Copy() common.PublicKey {
 	panic(err)
@@ -55,6 +82,11 @@ func (s PublicKey) IsInfinite() bool {
 	panic(err)
 }
 
+// String -- stub
+func (p PublicKey) String() string {
+	panic(err)
+}
+
 // Signature -- stub
 type Signature struct{}
 
@@ -73,16 +105,51 @@ func (s Signature) FastAggregateVerify(_ []common.PublicKey, _ [32]byte) bool {
 	panic(err)
 }
 
+// IsAggregate -- stub
+func (s Signature) IsAggregate(_ int, _ []common.PublicKey, _ [32]byte) bool {
+	panic(err)
+}
+
 // Marshal -- stub
 func (s Signature) Marshal() []byte {
 	panic(err)
 }
 
+// MarshalHex -- stub
+func (s Signature) MarshalHex() string {
+	panic(err)
+}
+
+// MarshalLE -- stub
+func (s Signature) MarshalLE() []byte {
+	panic(err)
+}
+
 // Copy -- stub
 func (s Signature) Copy() common.Signature {
 	panic(err)
 }
 
+// Equals -- stub
+func (s Signature) Equals(_ common.Signature) bool {
+	panic(err)
+}
+
+// ContributorCount -- stub
+func (s Signature) ContributorCount() int {
+	panic(err)
+}
+
+// IsInfinite -- stub
+func (s Signature) IsInfinite() bool {
+	panic(err)
+}
+
+// String -- stub
+func (s Signature) String() string {
+	panic(err)
+}
+
 // SecretKeyFromBytes -- stub
 func SecretKeyFromBytes(_ []byte) (SecretKey, error) {
 	panic(err)
@@ -93,18 +160,43 @@ func PublicKeyFromBytes(_ []byte) (PublicKey, error) {
 	panic(err)
 }
 
+// ClearPublicKeyCache -- stub
+func ClearPublicKeyCache() {
+	panic(err)
+}
+
+// PublicKeysFromBytes -- stub
+func PublicKeysFromBytes(_ [][]byte) ([]common.PublicKey, error) {
+	panic(err)
+}
+
 // SignatureFromBytes -- stub
 func SignatureFromBytes(_ []byte) (Signature, error) {
 	panic(err)
 }
 
+// SignaturesFromBytes -- stub
+func SignaturesFromBytes(_ [][]byte, _ int) ([]common.Signature, error) {
+	panic(err)
+}
+
+// SignatureFromBytesLE -- stub
+func SignatureFromBytesLE(_ []byte) (Signature, error) {
+	panic(err)
+}
+
 // AggregatePublicKeys -- stub
 func AggregatePublicKeys(_ [][]byte) (PublicKey, error) {
 	panic(err)
 }
 
+// AggregateMultiplePubkeys -- stub
+func AggregateMultiplePubkeys(_ []common.PublicKey) (PublicKey, error) {
+	panic(err)
+}
+
 // AggregateSignatures -- stub
-func AggregateSignatures(_ []common.Signature) common.Signature {
+func AggregateSignatures(_ []common.Signature) (common.Signature, error) {
 	panic(err)
 }
 
@@ -113,6 +205,11 @@ func VerifyMultipleSignatures(_ [][]byte, _ [][32]byte, _ []common.PublicKey) (b
 	panic(err)
 }
 
+// VerifyMultipleSignaturesParallel -- stub
+func VerifyMultipleSignaturesParallel(_ [][]byte, _ [][32]byte, _ []common.PublicKey, _ int) (bool, error) {
+	panic(err)
+}
+
 // NewAggregateSignature -- stub
 func NewAggregateSignature() common.Signature {
 	panic(err)
@@ -127,3 +224,97 @@ func RandKey() (common.SecretKey, error) {
 func VerifyCompressed(_, _, _ []byte) bool {
 	panic(err)
 }
+
+// VerifyCompressedBatch -- stub
+func VerifyCompressedBatch(_, _, _ [][]byte) (bool, error) {
+	panic(err)
+}
+
+// VerifyExpectDST -- stub
+func VerifyExpectDST(_ common.PublicKey, _ []byte, _ common.Signature, _ []byte) (bool, error) {
+	panic(err)
+}
+
+// SignatureFromBytesWithDST -- stub
+func SignatureFromBytesWithDST(_ []byte, _ []byte) (common.Signature, error) {
+	panic(err)
+}
+
+// SignWithDST -- stub
+func SignWithDST(_ common.SecretKey, _ []byte, _ []byte) (common.Signature, error) {
+	panic(err)
+}
+
+// VerifyWithDST -- stub
+func VerifyWithDST(_ common.PublicKey, _ []byte, _ common.Signature, _ []byte) (bool, error) {
+	panic(err)
+}
+
+// VerifyMultiDST -- stub
+func VerifyMultiDST(_ common.PublicKey, _ []byte, _ common.Signature, _ [][]byte) bool {
+	panic(err)
+}
+
+// PopProve -- stub
+func PopProve(_ common.SecretKey) common.Signature {
+	panic(err)
+}
+
+// PopVerify -- stub
+func PopVerify(_ common.PublicKey, _ common.Signature) bool {
+	panic(err)
+}
+
+// SetExtraEntropySource -- stub
+func SetExtraEntropySource(_ io.Reader) error {
+	panic(err)
+}
+
+// BatchVerifier -- stub
+type BatchVerifier struct{}
+
+// NewBatchVerifier -- stub
+func NewBatchVerifier() *BatchVerifier {
+	panic(err)
+}
+
+// Verify -- stub
+func (b *BatchVerifier) Verify(_ [][]byte, _ [][32]byte, _ []common.PublicKey) (bool, error) {
+	panic(err)
+}
+
+// AggregationBuilder -- stub
+type AggregationBuilder struct{}
+
+// NewAggregationBuilder -- stub
+func NewAggregationBuilder() *AggregationBuilder {
+	panic(err)
+}
+
+// Add -- stub
+func (b *AggregationBuilder) Add(_ common.Signature) {
+	panic(err)
+}
+
+// Finalize -- stub
+func (b *AggregationBuilder) Finalize() (common.Signature, error) {
+	panic(err)
+}
+
+// PairingBatchVerifier -- stub
+type PairingBatchVerifier struct{}
+
+// NewPairingBatchVerifier -- stub
+func NewPairingBatchVerifier() *PairingBatchVerifier {
+	panic(err)
+}
+
+// AddSignature -- stub
+func (p *PairingBatchVerifier) AddSignature(_ common.PublicKey, _ [32]byte, _ []byte) error {
+	panic(err)
+}
+
+// Verify -- stub
+func (p *PairingBatchVerifier) Verify() bool {
+	panic(err)
+}
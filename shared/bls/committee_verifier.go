@@ -0,0 +1,107 @@
+package bls
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
+// CommitteeVerifier holds the deserialized public keys of a committee so aggregate
+// signatures from it can be verified across slots without deserializing the keys of
+// its members again. The keys are replaced whenever the committee rotates.
+type CommitteeVerifier struct {
+	lock    sync.RWMutex
+	root    [32]byte
+	pubKeys []PublicKey
+	hits    uint64
+	misses  uint64
+}
+
+// CommitteeVerifierStats reports how often a committee verifier could reuse its keys.
+// A hit is a committee update for the committee already held, a miss is one which had
+// to deserialize the keys of a new committee.
+type CommitteeVerifierStats struct {
+	CommitteeSize int
+	Hits          uint64
+	Misses        uint64
+}
+
+// NewCommitteeVerifier creates a verifier with no committee set.
+func NewCommitteeVerifier() *CommitteeVerifier {
+	return &CommitteeVerifier{}
+}
+
+// SetCommittee sets the committee the verifier verifies aggregates for, in committee
+// order. The keys are only deserialized when the committee differs from the one held.
+func (c *CommitteeVerifier) SetCommittee(pubKeys [][]byte) error {
+	if len(pubKeys) == 0 {
+		return errors.New("committee must not be empty")
+	}
+	root := committeeRoot(pubKeys)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.pubKeys != nil && root == c.root {
+		c.hits++
+		return nil
+	}
+	keys := make([]PublicKey, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		key, err := PublicKeyFromBytes(pubKey)
+		if err != nil {
+			return errors.Wrapf(err, "could not deserialize public key of committee member %d", i)
+		}
+		keys[i] = key
+	}
+	c.root = root
+	c.pubKeys = keys
+	c.misses++
+	return nil
+}
+
+// VerifyAggregate verifies an aggregate signature over a message by the committee
+// members whose bits are set.
+func (c *CommitteeVerifier) VerifyAggregate(bits bitfield.Bitlist, sig Signature, msg [32]byte) (bool, error) {
+	if sig == nil {
+		return false, errors.New("nil signature")
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.pubKeys == nil {
+		return false, errors.New("no committee set")
+	}
+	if bits.Len() != uint64(len(c.pubKeys)) {
+		return false, errors.Errorf("bitfield length %d does not match committee size %d", bits.Len(), len(c.pubKeys))
+	}
+	participants := make([]PublicKey, 0, bits.Count())
+	for i, pubKey := range c.pubKeys {
+		if bits.BitAt(uint64(i)) {
+			participants = append(participants, pubKey)
+		}
+	}
+	if len(participants) == 0 {
+		return false, errors.New("no committee member is set in the bitfield")
+	}
+	return sig.FastAggregateVerify(participants, msg), nil
+}
+
+// Stats returns the size of the committee held and how often its keys were reused.
+func (c *CommitteeVerifier) Stats() CommitteeVerifierStats {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return CommitteeVerifierStats{
+		CommitteeSize: len(c.pubKeys),
+		Hits:          c.hits,
+		Misses:        c.misses,
+	}
+}
+
+// Identifies a committee by the hash of its serialized keys, in committee order.
+func committeeRoot(pubKeys [][]byte) [32]byte {
+	data := make([]byte, 0, len(pubKeys)*48)
+	for _, pubKey := range pubKeys {
+		data = append(data, pubKey...)
+	}
+	return hashutil.Hash(data)
+}
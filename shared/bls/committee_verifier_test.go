@@ -0,0 +1,148 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func testCommittee(t testing.TB, n int) ([]SecretKey, [][]byte) {
+	privKeys := make([]SecretKey, n)
+	pubKeys := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		privKeys[i] = priv
+		pubKeys[i] = priv.PublicKey().Marshal()
+	}
+	return privKeys, pubKeys
+}
+
+// Aggregates the signatures over msg of the members set in bits.
+func signByCommittee(privKeys []SecretKey, bits bitfield.Bitlist, msg [32]byte) Signature {
+	sigs := make([]Signature, 0, len(privKeys))
+	for i, priv := range privKeys {
+		if bits.BitAt(uint64(i)) {
+			sigs = append(sigs, priv.Sign(msg[:]))
+		}
+	}
+	return MustAggregateSignatures(sigs)
+}
+
+func TestCommitteeVerifier_VerifyAggregate(t *testing.T) {
+	privKeys, pubKeys := testCommittee(t, 8)
+	msg := [32]byte{'m', 's', 'g'}
+	bits := bitfield.NewBitlist(8)
+	bits.SetBitAt(1, true)
+	bits.SetBitAt(4, true)
+	bits.SetBitAt(7, true)
+	sig := signByCommittee(privKeys, bits, msg)
+
+	c := NewCommitteeVerifier()
+	_, err := c.VerifyAggregate(bits, sig, msg)
+	assert.ErrorContains(t, "no committee set", err)
+	assert.ErrorContains(t, "committee must not be empty", c.SetCommittee(nil))
+	require.NoError(t, c.SetCommittee(pubKeys))
+
+	verified, err := c.VerifyAggregate(bits, sig, msg)
+	require.NoError(t, err)
+	assert.Equal(t, true, verified)
+
+	// The signature does not verify for a different set of participants or message.
+	otherBits := bitfield.NewBitlist(8)
+	otherBits.SetBitAt(1, true)
+	otherBits.SetBitAt(4, true)
+	verified, err = c.VerifyAggregate(otherBits, sig, msg)
+	require.NoError(t, err)
+	assert.Equal(t, false, verified)
+	verified, err = c.VerifyAggregate(bits, sig, [32]byte{'b', 'a', 'd'})
+	require.NoError(t, err)
+	assert.Equal(t, false, verified)
+
+	_, err = c.VerifyAggregate(bitfield.NewBitlist(7), sig, msg)
+	assert.ErrorContains(t, "does not match committee size 8", err)
+	_, err = c.VerifyAggregate(bitfield.NewBitlist(8), sig, msg)
+	assert.ErrorContains(t, "no committee member is set", err)
+	_, err = c.VerifyAggregate(bits, nil, msg)
+	assert.ErrorContains(t, "nil signature", err)
+}
+
+func TestCommitteeVerifier_CommitteeRotation(t *testing.T) {
+	privKeys, pubKeys := testCommittee(t, 4)
+	otherPrivKeys, otherPubKeys := testCommittee(t, 4)
+	msg := [32]byte{'m', 's', 'g'}
+	bits := bitfield.NewBitlist(4)
+	bits.SetBitAt(0, true)
+	bits.SetBitAt(2, true)
+
+	c := NewCommitteeVerifier()
+	require.NoError(t, c.SetCommittee(pubKeys))
+	require.NoError(t, c.SetCommittee(pubKeys))
+	assert.DeepEqual(t, CommitteeVerifierStats{CommitteeSize: 4, Hits: 1, Misses: 1}, c.Stats())
+
+	// Once the committee rotates, only aggregates from the new committee verify.
+	require.NoError(t, c.SetCommittee(otherPubKeys))
+	assert.DeepEqual(t, CommitteeVerifierStats{CommitteeSize: 4, Hits: 1, Misses: 2}, c.Stats())
+	verified, err := c.VerifyAggregate(bits, signByCommittee(privKeys, bits, msg), msg)
+	require.NoError(t, err)
+	assert.Equal(t, false, verified)
+	verified, err = c.VerifyAggregate(bits, signByCommittee(otherPrivKeys, bits, msg), msg)
+	require.NoError(t, err)
+	assert.Equal(t, true, verified)
+
+	// A committee with an invalid key is rejected and the previous one kept.
+	badPubKeys := append([][]byte{}, pubKeys...)
+	badPubKeys[3] = []byte{'b', 'a', 'd'}
+	assert.ErrorContains(t, "committee member 3", c.SetCommittee(badPubKeys))
+	verified, err = c.VerifyAggregate(bits, signByCommittee(otherPrivKeys, bits, msg), msg)
+	require.NoError(t, err)
+	assert.Equal(t, true, verified)
+}
+
+func BenchmarkCommitteeVerifier_VerifyAggregate(b *testing.B) {
+	privKeys, pubKeys := testCommittee(b, 128)
+	msg := [32]byte{'m', 's', 'g'}
+	bits := bitfield.NewBitlist(128)
+	for i := uint64(0); i < 128; i++ {
+		bits.SetBitAt(i, true)
+	}
+	sig := signByCommittee(privKeys, bits, msg)
+	c := NewCommitteeVerifier()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		require.NoError(b, c.SetCommittee(pubKeys))
+		verified, err := c.VerifyAggregate(bits, sig, msg)
+		require.NoError(b, err)
+		if !verified {
+			b.Fatal("could not verify aggregate")
+		}
+	}
+}
+
+func BenchmarkVerifyAggregate_DeserializeKeys(b *testing.B) {
+	privKeys, pubKeys := testCommittee(b, 128)
+	msg := [32]byte{'m', 's', 'g'}
+	bits := bitfield.NewBitlist(128)
+	for i := uint64(0); i < 128; i++ {
+		bits.SetBitAt(i, true)
+	}
+	sig := signByCommittee(privKeys, bits, msg)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		keys := make([]PublicKey, len(pubKeys))
+		for j, pubKey := range pubKeys {
+			key, err := PublicKeyFromBytes(pubKey)
+			require.NoError(b, err)
+			keys[j] = key
+		}
+		if !sig.FastAggregateVerify(keys, msg) {
+			b.Fatal("could not verify aggregate")
+		}
+	}
+}
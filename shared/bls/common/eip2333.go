@@ -0,0 +1,180 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// The number of bytes of keying material expanded into a secret key, which
+// is large enough for the reduction modulo the curve order to be unbiased.
+const keygenOutputBytes = 48
+
+// MinSeedLength is the minimum length of the seed a master secret key is derived from.
+const MinSeedLength = 32
+
+// The number of 32 byte chunks in each half of a Lamport secret key.
+const lamportChunks = 255
+
+// The initial salt of the key generation, which is hashed once per attempt.
+var keygenSalt = []byte("BLS-SIG-KEYGEN-SALT-")
+
+// The order r of the BLS12-381 subgroups. Secret keys are scalars in [1, r-1].
+var curveOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+// DeriveMasterSKBytes derives the big endian bytes of the master secret key of a wallet
+// from a seed of at least 32 bytes, as defined by derive_master_SK in EIP-2333.
+//
+// Spec pseudocode definition:
+//  def derive_master_SK(seed: bytes) -> int:
+//    if len(seed) < 32:
+//        raise ValueError("`len(seed)` should be greater than or equal to 32.")
+//    return HKDF_mod_r(seed)
+func DeriveMasterSKBytes(seed []byte) ([]byte, error) {
+	if len(seed) < MinSeedLength {
+		return nil, fmt.Errorf("seed must be at least %d bytes", MinSeedLength)
+	}
+	return hkdfModR(seed, nil), nil
+}
+
+// DeriveChildSKBytes derives the big endian bytes of the child secret key at the given
+// index from the big endian bytes of a parent secret key, as defined by derive_child_SK
+// in EIP-2333. All derivation is hardened, so every index in the 32 bit range is valid.
+//
+// Spec pseudocode definition:
+//  def derive_child_SK(parent_SK: int, index: int) -> int:
+//    lamport_PK = parent_SK_to_lamport_PK(parent_SK, index)
+//    return HKDF_mod_r(lamport_PK)
+func DeriveChildSKBytes(parentSK []byte, index uint32) ([]byte, error) {
+	if len(parentSK) != 32 {
+		return nil, errors.New("parent secret key must be 32 bytes")
+	}
+	return hkdfModR(parentSKToLamportPK(parentSK, index), nil), nil
+}
+
+// ParseDerivationPath parses the indices of an EIP-2334 derivation path, such as
+// m/12381/3600/0/0/0. Paths must start at the master key m and consist of 32 bit
+// decimal indices, as EIP-2334 only allows hardened derivation.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m", path)
+	}
+	indices := make([]uint32, len(segments)-1)
+	for i, segment := range segments[1:] {
+		// In base 10 ParseUint rejects signs and underscores, which leaves leading
+		// zeros as the only non-canonical form of an index to reject.
+		if len(segment) > 1 && segment[0] == '0' {
+			return nil, fmt.Errorf("derivation path %q has an index with leading zeros", path)
+		}
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("derivation path %q has an invalid index %q", path, segment)
+		}
+		indices[i] = uint32(index)
+	}
+	return indices, nil
+}
+
+// Derives a secret key from input keying material.
+//
+// Spec pseudocode definition:
+//  def HKDF_mod_r(IKM: bytes, key_info: bytes=b'') -> int:
+//    L = 48
+//    salt = b'BLS-SIG-KEYGEN-SALT-'
+//    SK = 0
+//    while SK == 0:
+//        salt = H(salt)
+//        okm = HKDF(salt=salt, IKM=IKM + b'\x00', L=L, info=key_info + L.to_bytes(2, 'big'))
+//        SK = int.from_bytes(okm, byteorder='big') % curve_order
+//    return SK
+func hkdfModR(ikm, keyInfo []byte) []byte {
+	postfixedIKM := make([]byte, len(ikm)+1)
+	copy(postfixedIKM, ikm)
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	binary.BigEndian.PutUint16(info[len(keyInfo):], keygenOutputBytes)
+
+	salt := keygenSalt
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		okm := hkdfExpand(hkdfExtract(salt, postfixedIKM), info, keygenOutputBytes)
+		sk.SetBytes(okm).Mod(sk, curveOrder)
+	}
+	skBytes := make([]byte, 32)
+	return sk.FillBytes(skBytes)
+}
+
+// Derives the compressed Lamport public key of a parent secret key and child index.
+//
+// Spec pseudocode definition:
+//  def parent_SK_to_lamport_PK(parent_SK: int, index: int) -> bytes:
+//    salt = I2OSP(index, 4)
+//    IKM = I2OSP(parent_SK, 32)
+//    lamport_0 = IKM_to_lamport_SK(IKM, salt)
+//    not_IKM = flip_bits(IKM)
+//    lamport_1 = IKM_to_lamport_SK(not_IKM, salt)
+//    lamport_PK = b''
+//    for i in range(255):
+//        lamport_PK += SHA256(lamport_0[i])
+//    for i in range(255):
+//        lamport_PK += SHA256(lamport_1[i])
+//    compressed_lamport_PK = SHA256(lamport_PK)
+//    return compressed_lamport_PK
+func parentSKToLamportPK(ikm []byte, index uint32) []byte {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+	notIKM := make([]byte, len(ikm))
+	for i, b := range ikm {
+		notIKM[i] = ^b
+	}
+	lamportPK := sha256.New()
+	for _, lamportSK := range [][]byte{ikmToLamportSK(ikm, salt), ikmToLamportSK(notIKM, salt)} {
+		for i := 0; i < lamportChunks; i++ {
+			chunk := sha256.Sum256(lamportSK[i*sha256.Size : (i+1)*sha256.Size])
+			lamportPK.Write(chunk[:])
+		}
+	}
+	return lamportPK.Sum(nil)
+}
+
+// Derives the 255 chunks of one half of a Lamport secret key, concatenated.
+//
+// Spec pseudocode definition:
+//  def IKM_to_lamport_SK(IKM: bytes, salt: bytes) -> List[bytes]:
+//    OKM = HKDF(salt=salt, IKM=IKM, info=b'', L=8160)
+//    lamport_SK = [OKM[i:i+32] for i in range(0, 8160, 32)]
+//    return lamport_SK
+func ikmToLamportSK(ikm, salt []byte) []byte {
+	return hkdfExpand(hkdfExtract(salt, ikm), nil, lamportChunks*sha256.Size)
+}
+
+// HKDF-Extract as defined in RFC 5869, instantiated with SHA-256.
+func hkdfExtract(salt, ikm []byte) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// HKDF-Expand as defined in RFC 5869, instantiated with SHA-256.
+func hkdfExpand(prk, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, prk)
+	okm := make([]byte, 0, length+sha256.Size)
+	var t []byte
+	for i := byte(1); len(okm) < length; i++ {
+		mac.Reset()
+		mac.Write(t)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		t = mac.Sum(nil)
+		okm = append(okm, t...)
+	}
+	return okm[:length]
+}
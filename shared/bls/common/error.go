@@ -14,3 +14,15 @@ var ErrInfinitePubKey = errors.New("received an infinite public key")
 
 // ErrInfiniteSignature describes an error due to an infinite signature.
 var ErrInfiniteSignature = errors.New("received an infinite signature")
+
+// ErrNoSignatures describes an error due to aggregating an empty list of signatures.
+var ErrNoSignatures = errors.New("no signatures to aggregate")
+
+// ErrDestroyedKey describes an error due to using a secret key after it was destroyed.
+var ErrDestroyedKey = errors.New("secret key has been destroyed")
+
+// ErrInvalidHex describes an error due to a string which is not hex encoded.
+var ErrInvalidHex = errors.New("invalid hex string")
+
+// ErrInvalidHexLength describes an error due to a hex string encoding the wrong number of bytes.
+var ErrInvalidHexLength = errors.New("hex string has the wrong length")
@@ -6,16 +6,25 @@
 package common
 
 // SecretKey represents a BLS secret or private key.
+//
+// Once Destroy is called, Sign and SignBatch return signatures that never verify, rather
+// than nil, so callers may still marshal them. Use SignMessageSet to get ErrDestroyedKey
+// back when signing with a destroyed key must be detected.
 type SecretKey interface {
 	PublicKey() PublicKey
 	Sign(msg []byte) Signature
+	SignMessageSet(msgs [][]byte) ([]Signature, error)
+	SignBatch(msgs [][]byte) []Signature
 	Marshal() []byte
 	IsZero() bool
+	Zeroize()
+	Destroy()
 }
 
 // PublicKey represents a BLS public key.
 type PublicKey interface {
 	Marshal() []byte
+	MarshalHex() string
 	Copy() PublicKey
 	Aggregate(p2 PublicKey) PublicKey
 	IsInfinite() bool
@@ -26,6 +35,11 @@ type Signature interface {
 	Verify(pubKey PublicKey, msg []byte) bool
 	AggregateVerify(pubKeys []PublicKey, msgs [][32]byte) bool
 	FastAggregateVerify(pubKeys []PublicKey, msg [32]byte) bool
+	IsAggregate(expectedSigners int, pubKeys []PublicKey, msg [32]byte) bool
 	Marshal() []byte
+	MarshalHex() string
 	Copy() Signature
+	Equals(other Signature) bool
+	ContributorCount() int
+	IsInfinite() bool
 }
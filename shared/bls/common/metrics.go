@@ -0,0 +1,14 @@
+package common
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// MalformedPublicKeys tracks the number of nil or foreign public keys rejected by
+// signature verification. Such keys can only come from a deserialization bug upstream,
+// so any increase is worth investigating. It is shared by every BLS implementation.
+var MalformedPublicKeys = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bls_malformed_public_keys",
+	Help: "The number of nil or malformed public keys rejected by signature verification.",
+})
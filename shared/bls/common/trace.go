@@ -0,0 +1,79 @@
+package common
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	traceWriter  io.Writer
+	traceLock    sync.Mutex
+	traceEnabled int32
+)
+
+// VerificationTrace records the inputs and result of a signature verification, so that
+// it can be diffed against a reference implementation. Byte strings are 0x prefixed hex.
+type VerificationTrace struct {
+	Function   string   `json:"function"`
+	Signatures []string `json:"signatures"`
+	PublicKeys []string `json:"pubkeys"`
+	Messages   []string `json:"messages"`
+	Result     bool     `json:"result"`
+}
+
+// SetVerificationTrace writes a VerificationTrace of every signature verification to w,
+// as one JSON object per line. Passing a nil writer disables tracing.
+func SetVerificationTrace(w io.Writer) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	traceWriter = w
+	if w != nil {
+		atomic.StoreInt32(&traceEnabled, 1)
+	} else {
+		atomic.StoreInt32(&traceEnabled, 0)
+	}
+}
+
+// TracingVerification reports whether verifications are traced. Callers check it before
+// collecting the inputs of a trace, so tracing costs a single atomic load when disabled.
+func TracingVerification() bool {
+	return atomic.LoadInt32(&traceEnabled) == 1
+}
+
+// TraceVerification records a verification of signatures of messages by public keys.
+// Write errors are ignored, as tracing must not change the outcome of a verification.
+func TraceVerification(function string, sigs, pubKeys, msgs [][]byte, verified bool) {
+	trace := &VerificationTrace{
+		Function:   function,
+		Signatures: hexStrings(sigs),
+		PublicKeys: hexStrings(pubKeys),
+		Messages:   hexStrings(msgs),
+		Result:     verified,
+	}
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	if traceWriter == nil {
+		return
+	}
+	_ = json.NewEncoder(traceWriter).Encode(trace)
+}
+
+// MessagesBytes converts 32 byte messages for a trace.
+func MessagesBytes(msgs [][32]byte) [][]byte {
+	enc := make([][]byte, len(msgs))
+	for i := range msgs {
+		enc[i] = msgs[i][:]
+	}
+	return enc
+}
+
+func hexStrings(b [][]byte) []string {
+	s := make([]string, len(b))
+	for i := range b {
+		s[i] = "0x" + hex.EncodeToString(b[i])
+	}
+	return s
+}
@@ -15,3 +15,6 @@ var ZeroSecretKey = common.ZeroSecretKey
 
 // InfinitePublicKey represents an infinite public key.
 var InfinitePublicKey = common.InfinitePublicKey
+
+// InfiniteSignature represents an infinite signature.
+var InfiniteSignature = common.InfiniteSignature
@@ -0,0 +1,51 @@
+package bls
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls/common"
+)
+
+// DeriveMasterSK derives the master secret key of a wallet from a seed of at least
+// 32 bytes, as defined by derive_master_SK in EIP-2333. The key derivation is shared
+// by both BLS backends, and the key is created by the enabled backend. This is the only
+// entry point for creating a secret key from a seed.
+func DeriveMasterSK(seed []byte) (SecretKey, error) {
+	skBytes, err := common.DeriveMasterSKBytes(seed)
+	if err != nil {
+		return nil, err
+	}
+	return SecretKeyFromBytes(skBytes)
+}
+
+// DeriveChildSK derives the child secret key at the given index from a parent secret
+// key, as defined by derive_child_SK in EIP-2333. All derivation is hardened, so every
+// index in the 32 bit range is valid.
+func DeriveChildSK(parent SecretKey, index uint32) (SecretKey, error) {
+	if parent == nil {
+		return nil, errors.New("nil parent secret key")
+	}
+	skBytes, err := common.DeriveChildSKBytes(parent.Marshal(), index)
+	if err != nil {
+		return nil, err
+	}
+	return SecretKeyFromBytes(skBytes)
+}
+
+// DeriveKeyFromPath derives the secret key at an EIP-2334 path from a master secret key,
+// such as the signing key of validator i at m/12381/3600/i/0/0 or its withdrawal key at
+// m/12381/3600/i/0. Paths must start at the master key m and consist of 32 bit decimal
+// indices, as EIP-2334 only allows hardened derivation.
+func DeriveKeyFromPath(masterSK SecretKey, path string) (SecretKey, error) {
+	indices, err := common.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk := masterSK
+	for _, index := range indices {
+		sk, err = DeriveChildSK(sk, index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not derive child key at index %d of path %q", index, path)
+		}
+	}
+	return sk, nil
+}
@@ -0,0 +1,169 @@
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+// Test vectors from https://eips.ethereum.org/EIPS/eip-2333#test-cases.
+func TestDeriveMasterSK_DeriveChildSK(t *testing.T) {
+	tests := []struct {
+		name       string
+		seed       string
+		masterSK   string
+		childIndex uint32
+		childSK    string
+	}{
+		{
+			name:       "test case 0",
+			seed:       "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			masterSK:   "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+			childIndex: 0,
+			childSK:    "20397789859736650942317412262472558107875392172444076792671091975210932703118",
+		},
+		{
+			name:       "test case 1",
+			seed:       "3141592653589793238462643383279502884197169399375105820974944592",
+			masterSK:   "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+			childIndex: 3141592653,
+			childSK:    "25457201688850691947727629385191704516744796114925897962676248250929345014287",
+		},
+		{
+			name:       "test case 2",
+			seed:       "0099ff991111002299dd7744ee3355bbdd8844115566cc55663355668888cc00",
+			masterSK:   "27580842291869792442942448775674722299803720648445448686099262467207037398656",
+			childIndex: 4294967295,
+			childSK:    "29358610794459428860402234341874281240803786294062035874021252734817515685787",
+		},
+		{
+			name:       "test case 3",
+			seed:       "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+			masterSK:   "19022158461524446591288038168518313374041767046816487870552872741050760015818",
+			childIndex: 42,
+			childSK:    "31372231650479070279774297061823572166496564838472787488249775572789064611981",
+		},
+	}
+	for _, backend := range []struct {
+		name       string
+		enableBlst bool
+	}{
+		{name: "herumi"},
+		{name: "blst", enableBlst: true},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: backend.enableBlst})
+			defer reset()
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					seed, err := hex.DecodeString(tt.seed)
+					require.NoError(t, err)
+					masterSK, err := DeriveMasterSK(seed)
+					require.NoError(t, err)
+					assert.Equal(t, tt.masterSK, new(big.Int).SetBytes(masterSK.Marshal()).String())
+					childSK, err := DeriveChildSK(masterSK, tt.childIndex)
+					require.NoError(t, err)
+					assert.Equal(t, tt.childSK, new(big.Int).SetBytes(childSK.Marshal()).String())
+				})
+			}
+		})
+	}
+}
+
+func TestDeriveMasterSK_ShortSeed(t *testing.T) {
+	_, err := DeriveMasterSK(make([]byte, 31))
+	assert.ErrorContains(t, "seed must be at least 32 bytes", err)
+}
+
+func TestDeriveChildSK_NilParent(t *testing.T) {
+	_, err := DeriveChildSK(nil, 0)
+	assert.ErrorContains(t, "nil parent secret key", err)
+}
+
+func TestDeriveChildSK_DestroyedParent(t *testing.T) {
+	for _, backend := range []struct {
+		name       string
+		enableBlst bool
+	}{
+		{name: "herumi"},
+		{name: "blst", enableBlst: true},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: backend.enableBlst})
+			defer reset()
+			parent, err := RandKey()
+			require.NoError(t, err)
+			parent.Destroy()
+			_, err = DeriveChildSK(parent, 0)
+			assert.ErrorContains(t, "parent secret key must be 32 bytes", err)
+		})
+	}
+}
+
+func TestDeriveKeyFromPath(t *testing.T) {
+	seed, err := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	require.NoError(t, err)
+	for _, backend := range []struct {
+		name       string
+		enableBlst bool
+	}{
+		{name: "herumi"},
+		{name: "blst", enableBlst: true},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: backend.enableBlst})
+			defer reset()
+			masterSK, err := DeriveMasterSK(seed)
+			require.NoError(t, err)
+
+			// The EIP-2334 signing key path of the first validator.
+			signingSK, err := DeriveKeyFromPath(masterSK, "m/12381/3600/0/0/0")
+			require.NoError(t, err)
+			assert.Equal(t, "032e6c3c7359223e127e9479afc521c4342f8903bc29ae01b671bcbcc98be0f6", hex.EncodeToString(signingSK.Marshal()))
+
+			// The signing key is a child of the withdrawal key.
+			withdrawalSK, err := DeriveKeyFromPath(masterSK, "m/12381/3600/0/0")
+			require.NoError(t, err)
+			childSK, err := DeriveChildSK(withdrawalSK, 0)
+			require.NoError(t, err)
+			assert.DeepEqual(t, signingSK.Marshal(), childSK.Marshal())
+
+			sk, err := DeriveKeyFromPath(masterSK, "m/2147483648")
+			require.NoError(t, err)
+			assert.Equal(t, "047414f482aacb776fd1a0bffcf7cdc6ff9491e03f9915f5c9d1e1ff6154d69b", hex.EncodeToString(sk.Marshal()))
+			sk, err = DeriveKeyFromPath(masterSK, "m/4294967295")
+			require.NoError(t, err)
+			assert.Equal(t, "4bb97f9a4dfb7b816be04598556ecd2912babf7bce75e1e7252cb119aba0c64a", hex.EncodeToString(sk.Marshal()))
+			sk, err = DeriveKeyFromPath(masterSK, "m")
+			require.NoError(t, err)
+			assert.DeepEqual(t, masterSK.Marshal(), sk.Marshal())
+		})
+	}
+}
+
+func TestDeriveKeyFromPath_MalformedPath(t *testing.T) {
+	masterSK, err := RandKey()
+	require.NoError(t, err)
+	for _, path := range []string{
+		"",
+		"12381/3600/0/0/0",
+		"m/",
+		"m/12381//0/0",
+		"m/12381/3600/0/0/0/",
+		"m/12381'/3600'/0'/0'/0'",
+		"m/-1",
+		"m/007",
+		"m/4294967296",
+		"m/18446744073709551616",
+		"m/12381/validator",
+	} {
+		t.Run(path, func(t *testing.T) {
+			_, err := DeriveKeyFromPath(masterSK, path)
+			assert.ErrorContains(t, "derivation path", err)
+		})
+	}
+}
@@ -9,3 +9,15 @@ var ErrZeroKey = common.ErrZeroKey
 
 // ErrInfinitePubKey describes an error due to an infinite public key.
 var ErrInfinitePubKey = common.ErrInfinitePubKey
+
+// ErrInfiniteSignature describes an error due to an infinite signature.
+var ErrInfiniteSignature = common.ErrInfiniteSignature
+
+// ErrNoSignatures describes an error due to aggregating an empty list of signatures.
+var ErrNoSignatures = common.ErrNoSignatures
+
+// ErrInvalidHex describes an error due to a string which is not hex encoded.
+var ErrInvalidHex = common.ErrInvalidHex
+
+// ErrInvalidHexLength describes an error due to a hex string encoding the wrong number of bytes.
+var ErrInvalidHexLength = common.ErrInvalidHexLength
@@ -58,7 +58,8 @@ func BenchmarkSignature_AggregateVerify(b *testing.B) {
 		sigs = append(sigs, sig)
 		msgs = append(msgs, msg)
 	}
-	aggregated := herumi.Aggregate(sigs)
+	aggregated, err := herumi.Aggregate(sigs)
+	require.NoError(b, err)
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -67,6 +68,21 @@ func BenchmarkSignature_AggregateVerify(b *testing.B) {
 	}
 }
 
+// Herumi checks the subgroup on every deserialization, so this is comparable to the
+// validated case of the blst benchmark.
+func BenchmarkSignatureFromBytes(b *testing.B) {
+	sk, err := herumi.RandKey()
+	require.NoError(b, err)
+	sig := sk.Sign([]byte("Some msg")).Marshal()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := herumi.SignatureFromBytes(sig); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func BenchmarkSecretKey_Marshal(b *testing.B) {
 	key, err := herumi.RandKey()
 	require.NoError(b, err)
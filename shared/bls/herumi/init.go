@@ -2,6 +2,10 @@ package herumi
 
 import "github.com/herumi/bls-eth-go-binary/bls"
 
+// DomainSeparationTag is the tag messages are hashed to the curve with in the Ethereum mode
+// herumi is set up in. Unlike blst, herumi can not sign or verify under any other tag.
+const DomainSeparationTag = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
 func init() {
 	if err := bls.Init(bls.BLS12_381); err != nil {
 		panic(err)
@@ -47,6 +47,26 @@ func PublicKeyFromBytes(pubKey []byte) (common.PublicKey, error) {
 	return pubKeyObj, nil
 }
 
+// ClearPublicKeyCache removes every deserialized public key from the cache used by
+// PublicKeyFromBytes and PublicKeysFromBytes.
+func ClearPublicKeyCache() {
+	pubkeyCache.Clear()
+}
+
+// PublicKeysFromBytes creates BLS public keys from BigEndian byte slices. An error names
+// the index of the first malformed key.
+func PublicKeysFromBytes(pubKeys [][]byte) ([]common.PublicKey, error) {
+	keys := make([]common.PublicKey, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		key, err := PublicKeyFromBytes(pubKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid public key at index %d", i)
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
 // AggregatePublicKeys aggregates the provided raw public keys into a single key.
 func AggregatePublicKeys(pubs [][]byte) (common.PublicKey, error) {
 	if len(pubs) == 0 {
@@ -71,6 +91,20 @@ func (p *PublicKey) Marshal() []byte {
 	return p.p.Serialize()
 }
 
+// MarshalHex returns the 0x-prefixed hex encoding of the compressed public key.
+func (p *PublicKey) MarshalHex() string {
+	return fmt.Sprintf("%#x", p.Marshal())
+}
+
+// String returns the 0x-prefixed hex encoding of the compressed public key, for logging.
+// Placeholder keys, as read with SkipBLSVerify set, are written as "0xmock".
+func (p *PublicKey) String() string {
+	if featureconfig.Get().SkipBLSVerify || p.p == nil {
+		return placeholderHex
+	}
+	return p.MarshalHex()
+}
+
 // Copy the public key to a new pointer reference.
 func (p *PublicKey) Copy() common.PublicKey {
 	np := *p.p
@@ -90,3 +124,14 @@ func (p *PublicKey) Aggregate(p2 common.PublicKey) common.PublicKey {
 	p.p.Add(p2.(*PublicKey).p)
 	return p
 }
+
+// Marshals public keys for a verification trace, leaving nil or foreign keys empty.
+func tracePublicKeys(pubKeys []common.PublicKey) [][]byte {
+	enc := make([][]byte, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		if pub, ok := pubKey.(*PublicKey); ok && pub != nil && pub.p != nil {
+			enc[i] = pub.Marshal()
+		}
+	}
+	return enc
+}
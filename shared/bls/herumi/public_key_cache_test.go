@@ -0,0 +1,30 @@
+package herumi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestClearPublicKeyCache(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pubKey := priv.PublicKey().Marshal()
+	_, err = PublicKeyFromBytes(pubKey)
+	require.NoError(t, err)
+
+	// Ristretto buffers writes, so wait for the key to land before clearing.
+	for i := 0; i < 100; i++ {
+		if _, ok := pubkeyCache.Get(string(pubKey)); ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, ok := pubkeyCache.Get(string(pubKey))
+	require.Equal(t, true, ok, "Expected the public key to be cached")
+
+	ClearPublicKeyCache()
+	_, ok = pubkeyCache.Get(string(pubKey))
+	require.Equal(t, false, ok, "Expected the public key cache to be empty")
+}
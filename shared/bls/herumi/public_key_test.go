@@ -2,10 +2,14 @@ package herumi_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/bls/herumi"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 )
@@ -74,3 +78,24 @@ func TestPublicKey_Copy(t *testing.T) {
 		t.Fatal("Pubkey was mutated after copy")
 	}
 }
+
+func TestPublicKey_String(t *testing.T) {
+	priv, err := herumi.RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	hexKey := fmt.Sprint(pub)
+	require.Equal(t, true, strings.HasPrefix(hexKey, "0x"), hexKey)
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(hexKey, "0x"))
+	require.NoError(t, err)
+	parsed, err := herumi.PublicKeyFromBytes(raw)
+	require.NoError(t, err)
+	assert.DeepEqual(t, pub.Marshal(), parsed.Marshal())
+	assert.Equal(t, hexKey, fmt.Sprint(parsed))
+
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{SkipBLSVerify: true})
+	defer reset()
+	placeholder, err := herumi.PublicKeyFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "0xmock", fmt.Sprint(placeholder))
+}
@@ -12,7 +12,8 @@ import (
 
 // bls12SecretKey used in the BLS signature scheme.
 type bls12SecretKey struct {
-	p *bls12.SecretKey
+	p         *bls12.SecretKey
+	destroyed bool
 }
 
 // RandKey creates a new private key using a random method provided as an io.Reader.
@@ -22,7 +23,7 @@ func RandKey() (common.SecretKey, error) {
 	if secKey.IsZero() {
 		return nil, errors.New("generated a zero secret key")
 	}
-	return &bls12SecretKey{secKey}, nil
+	return &bls12SecretKey{p: secKey}, nil
 }
 
 // SecretKeyFromBytes creates a BLS private key from a BigEndian byte slice.
@@ -56,15 +57,50 @@ func (s *bls12SecretKey) PublicKey() common.PublicKey {
 // In ETH2.0 specification:
 // def Sign(SK: int, message: Bytes) -> BLSSignature
 func (s *bls12SecretKey) Sign(msg []byte) common.Signature {
-	if featureconfig.Get().SkipBLSVerify {
+	if s.destroyed {
 		return &Signature{}
 	}
+	if featureconfig.Get().SkipBLSVerify {
+		return &Signature{contributors: 1}
+	}
 	signature := s.p.SignByte(msg)
-	return &Signature{s: signature}
+	return &Signature{s: signature, contributors: 1}
+}
+
+// SignMessageSet signs each of the provided messages, such as the same registration under
+// several signing domains, returning the signatures in the order of the messages.
+func (s *bls12SecretKey) SignMessageSet(msgs [][]byte) ([]common.Signature, error) {
+	if s.destroyed {
+		return nil, common.ErrDestroyedKey
+	}
+	if len(msgs) == 0 {
+		return nil, errors.New("no messages to sign")
+	}
+	return s.SignBatch(msgs), nil
+}
+
+// SignBatch signs each of the provided messages, such as several attestations in a slot,
+// returning one signature per message in the order of the messages. A destroyed key returns
+// invalid signatures.
+func (s *bls12SecretKey) SignBatch(msgs [][]byte) []common.Signature {
+	sigs := make([]common.Signature, len(msgs))
+	if s.destroyed {
+		for i := range sigs {
+			sigs[i] = &Signature{}
+		}
+		return sigs
+	}
+	for i, msg := range msgs {
+		sigs[i] = s.Sign(msg)
+	}
+	return sigs
 }
 
 // Marshal a secret key into a LittleEndian byte slice.
 func (s *bls12SecretKey) Marshal() []byte {
+	if s.destroyed {
+		return nil
+	}
 	keyBytes := s.p.Serialize()
 	if len(keyBytes) < params.BeaconConfig().BLSSecretKeyLength {
 		emptyBytes := make([]byte, params.BeaconConfig().BLSSecretKeyLength-len(keyBytes))
@@ -77,3 +113,21 @@ func (s *bls12SecretKey) Marshal() []byte {
 func (s *bls12SecretKey) IsZero() bool {
 	return s.p.IsZero()
 }
+
+// Zeroize overwrites the scalar of the secret key with zeros, clearing the key material
+// from memory once the key is no longer needed. The key must not be used afterwards.
+func (s *bls12SecretKey) Zeroize() {
+	if s.p == nil {
+		return
+	}
+	*s.p = bls12.SecretKey{}
+}
+
+// Destroy zeroizes the secret key and marks it unusable, after which Sign and SignBatch return
+// invalid signatures, Marshal returns nil and SignMessageSet returns ErrDestroyedKey. As the garbage collector may
+// have copied the key material elsewhere in memory, wiping it is best-effort, but it
+// reduces the window in which the key can be read from the process memory.
+func (s *bls12SecretKey) Destroy() {
+	s.destroyed = true
+	s.Zeroize()
+}
@@ -81,3 +81,64 @@ func TestSerialize(t *testing.T) {
 	_, err = herumi.SecretKeyFromBytes(b)
 	assert.NoError(t, err)
 }
+
+func TestZeroize(t *testing.T) {
+	priv, err := herumi.RandKey()
+	require.NoError(t, err)
+	require.DeepNotEqual(t, make([]byte, 32), priv.Marshal())
+
+	priv.Zeroize()
+	assert.DeepEqual(t, make([]byte, 32), priv.Marshal())
+	assert.Equal(t, true, priv.IsZero())
+}
+
+func TestDestroy(t *testing.T) {
+	priv, err := herumi.RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	require.NotNil(t, priv.Sign([]byte("hello")))
+
+	priv.Destroy()
+	assert.Equal(t, true, priv.IsZero())
+	assert.Equal(t, true, priv.Marshal() == nil, "Destroyed key was marshaled")
+	sig := priv.Sign([]byte("hello"))
+	require.NotNil(t, sig)
+	assert.DeepEqual(t, make([]byte, 96), sig.Marshal())
+	assert.Equal(t, false, sig.Verify(pub, []byte("hello")), "Destroyed key signed a message")
+	_, err = priv.SignMessageSet([][]byte{[]byte("hello")})
+	assert.ErrorContains(t, common.ErrDestroyedKey.Error(), err)
+	sigs := priv.SignBatch([][]byte{[]byte("hello")})
+	require.Equal(t, 1, len(sigs))
+	assert.Equal(t, false, sigs[0].Verify(pub, []byte("hello")), "Destroyed key signed a batch")
+}
+
+func TestSignMessageSet(t *testing.T) {
+	priv, err := herumi.RandKey()
+	require.NoError(t, err)
+	_, err = priv.SignMessageSet(nil)
+	assert.ErrorContains(t, "no messages to sign", err)
+
+	msgs := [][]byte{[]byte("deposit"), []byte("builder"), []byte("other")}
+	sigs, err := priv.SignMessageSet(msgs)
+	require.NoError(t, err)
+	require.Equal(t, len(msgs), len(sigs))
+	for i, sig := range sigs {
+		assert.Equal(t, true, sig.Verify(priv.PublicKey(), msgs[i]), "Signature %d did not verify", i)
+		assert.Equal(t, false, sig.Verify(priv.PublicKey(), msgs[(i+1)%len(msgs)]))
+	}
+}
+
+func TestSignBatch(t *testing.T) {
+	priv, err := herumi.RandKey()
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(priv.SignBatch(nil)))
+
+	msgs := [][]byte{[]byte("attestation 1"), []byte("attestation 2"), []byte("attestation 2"), []byte("attestation 3")}
+	sigs := priv.SignBatch(msgs)
+	require.Equal(t, len(msgs), len(sigs))
+	for i, sig := range sigs {
+		assert.DeepEqual(t, priv.Sign(msgs[i]).Marshal(), sig.Marshal(), "Signature %d differs from Sign", i)
+		assert.Equal(t, true, sig.Verify(priv.PublicKey(), msgs[i]), "Signature %d did not verify", i)
+		assert.Equal(t, 1, sig.ContributorCount())
+	}
+}
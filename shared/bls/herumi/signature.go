@@ -12,15 +12,25 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/rand"
 )
 
+// How placeholder signatures and public keys, which carry no point, are written in logs.
+const placeholderHex = "0xmock"
+
 // Signature used in the BLS signature scheme.
 type Signature struct {
 	s *bls12.Sign
+	// The number of signatures aggregated into this one. Signatures
+	// deserialized from bytes count as a single contributor, as the
+	// number aggregated into them cannot be recovered.
+	contributors int
 }
 
-// SignatureFromBytes creates a BLS signature from a LittleEndian byte slice.
+// SignatureFromBytes creates a BLS signature from a LittleEndian byte slice. The signature
+// at infinity fails verification, and is rejected right away with ErrInfiniteSignature
+// when the RejectInfiniteSignatures feature is enabled.
 func SignatureFromBytes(sig []byte) (common.Signature, error) {
-	if featureconfig.Get().SkipBLSVerify {
-		return &Signature{}, nil
+	cfg := featureconfig.Get()
+	if cfg.SkipBLSVerify {
+		return &Signature{contributors: 1}, nil
 	}
 	if len(sig) != params.BeaconConfig().BLSSignatureLength {
 		return nil, fmt.Errorf("signature must be %d bytes", params.BeaconConfig().BLSSignatureLength)
@@ -30,7 +40,26 @@ func SignatureFromBytes(sig []byte) (common.Signature, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not unmarshal bytes into signature")
 	}
-	return &Signature{s: signature}, nil
+	sigObj := &Signature{s: signature, contributors: 1}
+	if cfg.RejectInfiniteSignatures && sigObj.IsInfinite() {
+		return nil, common.ErrInfiniteSignature
+	}
+	return sigObj, nil
+}
+
+// SignaturesFromBytes creates BLS signatures from LittleEndian byte slices, validating each
+// one like SignatureFromBytes. Herumi deserializes the signatures one at a time, so the
+// number of workers is ignored. An error names the index of the first malformed signature.
+func SignaturesFromBytes(sigs [][]byte, _ int) ([]common.Signature, error) {
+	signatures := make([]common.Signature, len(sigs))
+	for i, sig := range sigs {
+		signature, err := SignatureFromBytes(sig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid signature at index %d", i)
+		}
+		signatures[i] = signature
+	}
+	return signatures, nil
 }
 
 // Verify a bls signature given a public key, a message.
@@ -42,21 +71,38 @@ func SignatureFromBytes(sig []byte) (common.Signature, error) {
 //
 // In ETH2.0 specification:
 // def Verify(PK: BLSPubkey, message: Bytes, signature: BLSSignature) -> bool
-func (s *Signature) Verify(pubKey common.PublicKey, msg []byte) bool {
+func (s *Signature) Verify(pubKey common.PublicKey, msg []byte) (verified bool) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("Verify", [][]byte{s.Marshal()}, tracePublicKeys([]common.PublicKey{pubKey}), [][]byte{msg}, verified)
+		}()
+	}
 	if featureconfig.Get().SkipBLSVerify {
 		return true
 	}
-	// Reject infinite public keys.
-	if pubKey.(*PublicKey).p.IsZero() {
+	// Reject blank aggregates which no signature was aggregated into.
+	if s.contributors == 0 {
 		return false
 	}
-	return s.s.VerifyByte(pubKey.(*PublicKey).p, msg)
+	pub, ok := validPublicKey(pubKey)
+	if !ok {
+		return false
+	}
+	// Reject the infinite signature and public key.
+	if s.IsInfinite() || pub.IsInfinite() {
+		return false
+	}
+	return s.s.VerifyByte(pub.p, msg)
 }
 
 // AggregateVerify verifies each public key against its respective message.
 // This is vulnerable to rogue public-key attack. Each user must
 // provide a proof-of-knowledge of the public key.
 //
+// The infinity signature is INVALID for every set of public keys and messages, including
+// sets whose public keys aggregate to the infinity point, for which the pairing check alone
+// would pass. An empty set is INVALID for every signature.
+//
 // In IETF draft BLS specification:
 // AggregateVerify((PK_1, message_1), ..., (PK_n, message_n),
 //      signature) -> VALID or INVALID: an aggregate verification
@@ -66,10 +112,23 @@ func (s *Signature) Verify(pubKey common.PublicKey, msg []byte) bool {
 //
 // In ETH2.0 specification:
 // def AggregateVerify(pairs: Sequence[PK: BLSPubkey, message: Bytes], signature: BLSSignature) -> boo
-func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte) bool {
+func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte) (verified bool) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("AggregateVerify", [][]byte{s.Marshal()}, tracePublicKeys(pubKeys), common.MessagesBytes(msgs), verified)
+		}()
+	}
 	if featureconfig.Get().SkipBLSVerify {
 		return true
 	}
+	// Reject blank aggregates which no signature was aggregated into.
+	if s.contributors == 0 {
+		return false
+	}
+	// Reject the infinite signature before looking at the set.
+	if s.IsInfinite() {
+		return false
+	}
 	size := len(pubKeys)
 	if size == 0 {
 		return false
@@ -80,8 +139,15 @@ func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte)
 	msgSlices := make([]byte, 0, 32*len(msgs))
 	rawKeys := make([]bls12.PublicKey, 0, len(pubKeys))
 	for i := 0; i < size; i++ {
+		pub, ok := validPublicKey(pubKeys[i])
+		if !ok {
+			return false
+		}
+		if pub.IsInfinite() {
+			return false
+		}
 		msgSlices = append(msgSlices, msgs[i][:]...)
-		rawKeys = append(rawKeys, *(pubKeys[i].(*PublicKey).p))
+		rawKeys = append(rawKeys, *pub.p)
 	}
 	// Use "NoCheck" because we do not care if the messages are unique or not.
 	return s.s.AggregateVerifyNoCheck(rawKeys, msgSlices)
@@ -89,6 +155,10 @@ func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte)
 
 // FastAggregateVerify verifies all the provided public keys with their aggregated signature.
 //
+// The infinity signature is INVALID for every message and set of public keys, including
+// sets whose public keys aggregate to the infinity point, for which the pairing check alone
+// would pass. An empty set is INVALID for every signature.
+//
 // In IETF draft BLS specification:
 // FastAggregateVerify(PK_1, ..., PK_n, message, signature) -> VALID
 //      or INVALID: a verification algorithm for the aggregate of multiple
@@ -97,40 +167,101 @@ func (s *Signature) AggregateVerify(pubKeys []common.PublicKey, msgs [][32]byte)
 //
 // In ETH2.0 specification:
 // def FastAggregateVerify(PKs: Sequence[BLSPubkey], message: Bytes, signature: BLSSignature) -> bool
-func (s *Signature) FastAggregateVerify(pubKeys []common.PublicKey, msg [32]byte) bool {
+func (s *Signature) FastAggregateVerify(pubKeys []common.PublicKey, msg [32]byte) (verified bool) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("FastAggregateVerify", [][]byte{s.Marshal()}, tracePublicKeys(pubKeys), [][]byte{msg[:]}, verified)
+		}()
+	}
 	if featureconfig.Get().SkipBLSVerify {
 		return true
 	}
+	// Reject blank aggregates which no signature was aggregated into.
+	if s.contributors == 0 {
+		return false
+	}
+	// Reject the infinite signature before looking at the set.
+	if s.IsInfinite() {
+		return false
+	}
 	if len(pubKeys) == 0 {
 		return false
 	}
 	rawKeys := make([]bls12.PublicKey, len(pubKeys))
 	for i := 0; i < len(pubKeys); i++ {
-		rawKeys[i] = *(pubKeys[i].(*PublicKey).p)
+		pub, ok := validPublicKey(pubKeys[i])
+		if !ok {
+			return false
+		}
+		if pub.IsInfinite() {
+			return false
+		}
+		rawKeys[i] = *pub.p
 	}
 
 	return s.s.FastAggregateVerify(rawKeys, msg[:])
 }
 
-// NewAggregateSignature creates a blank aggregate signature.
+// IsAggregate checks that the signature is an aggregate of signatures of a message by exactly
+// expectedSigners distinct public keys. It lets tooling tell an aggregate apart from a single
+// signature, or from an aggregate by a different committee.
+func (s *Signature) IsAggregate(expectedSigners int, pubKeys []common.PublicKey, msg [32]byte) bool {
+	if expectedSigners <= 0 || len(pubKeys) != expectedSigners {
+		return false
+	}
+	seen := make(map[string]bool, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		if pubKey == nil {
+			return false
+		}
+		key := string(pubKey.Marshal())
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	return s.FastAggregateVerify(pubKeys, msg)
+}
+
+// NewAggregateSignature creates a blank aggregate signature. It has no contributors,
+// so it fails verification until real signatures are aggregated into it.
 func NewAggregateSignature() common.Signature {
 	return &Signature{s: bls12.HashAndMapToSignature([]byte{'m', 'o', 'c', 'k'})}
 }
 
 // AggregateSignatures converts a list of signatures into a single, aggregated sig.
-func AggregateSignatures(sigs []common.Signature) common.Signature {
+// There is no aggregate of an empty list, so it returns an error instead.
+func AggregateSignatures(sigs []common.Signature) (common.Signature, error) {
 	if len(sigs) == 0 {
-		return nil
+		return nil, common.ErrNoSignatures
 	}
 	if featureconfig.Get().SkipBLSVerify {
-		return sigs[0]
+		// Signatures read with SkipBLSVerify set are placeholders without a point, so
+		// the aggregate is a placeholder too.
+		contributors := 0
+		for _, sig := range sigs {
+			if sig, ok := sig.(*Signature); ok && sig != nil {
+				contributors += sig.contributors
+			}
+		}
+		return &Signature{contributors: contributors}, nil
 	}
 
-	signature := *sigs[0].Copy().(*Signature).s
-	for i := 1; i < len(sigs); i++ {
-		signature.Add(sigs[i].(*Signature).s)
+	rawSigs := make([]*bls12.Sign, len(sigs))
+	contributors := 0
+	for i := 0; i < len(sigs); i++ {
+		sig, ok := sigs[i].(*Signature)
+		if !ok || sig == nil || sig.s == nil {
+			return nil, errors.Errorf("signature at index %d is not a valid herumi signature", i)
+		}
+		rawSigs[i] = sig.s
+		contributors += sig.contributors
+	}
+	signature := *rawSigs[0]
+	for i := 1; i < len(rawSigs); i++ {
+		signature.Add(rawSigs[i])
 	}
-	return &Signature{s: &signature}
+	return &Signature{s: &signature, contributors: contributors}, nil
 }
 
 // Aggregate is an alias for AggregateSignatures, defined to conform to BLS specification.
@@ -144,7 +275,7 @@ func AggregateSignatures(sigs []common.Signature) common.Signature {
 // def Aggregate(signatures: Sequence[BLSSignature]) -> BLSSignature
 //
 // Deprecated: Use AggregateSignatures.
-func Aggregate(sigs []common.Signature) common.Signature {
+func Aggregate(sigs []common.Signature) (common.Signature, error) {
 	return AggregateSignatures(sigs)
 }
 
@@ -155,7 +286,12 @@ func Aggregate(sigs []common.Signature) common.Signature {
 // P'_{i,j} = P_{i,j} * r_i
 // e(S*, G) = \prod_{i=1}^n \prod_{j=1}^{m_i} e(P'_{i,j}, M_{i,j})
 // Using this we can verify multiple signatures safely.
-func VerifyMultipleSignatures(sigs []common.Signature, msgs [][32]byte, pubKeys []common.PublicKey) (bool, error) {
+func VerifyMultipleSignatures(sigs []common.Signature, msgs [][32]byte, pubKeys []common.PublicKey) (verified bool, err error) {
+	if common.TracingVerification() {
+		defer func() {
+			common.TraceVerification("VerifyMultipleSignatures", traceSignatures(sigs), tracePublicKeys(pubKeys), common.MessagesBytes(msgs), verified)
+		}()
+	}
 	if featureconfig.Get().SkipBLSVerify {
 		return true, nil
 	}
@@ -173,12 +309,16 @@ func VerifyMultipleSignatures(sigs []common.Signature, msgs [][32]byte, pubKeys
 	signatures := make([]bls12.G2, length)
 	msgSlices := make([]byte, 0, 32*len(msgs))
 	for i := 0; i < len(sigs); i++ {
+		sig, ok := sigs[i].(*Signature)
+		if !ok || sig == nil || sig.s == nil {
+			return false, errors.Errorf("signature at index %d is not a valid herumi signature", i)
+		}
 		rNum := newGen.Uint64()
 		if err := randNums[i].SetLittleEndian(bytesutil.Bytes8(rNum)); err != nil {
 			return false, err
 		}
 		// Cast signature to a G2 value
-		signatures[i] = *bls12.CastFromSign(sigs[i].(*Signature).s)
+		signatures[i] = *bls12.CastFromSign(sig.s)
 
 		// Flatten message to single byte slice to make it compatible with herumi.
 		msgSlices = append(msgSlices, msgs[i][:]...)
@@ -190,12 +330,13 @@ func VerifyMultipleSignatures(sigs []common.Signature, msgs [][32]byte, pubKeys
 
 	multiKeys := make([]bls12.PublicKey, length)
 	for i := 0; i < len(pubKeys); i++ {
-		if pubKeys[i] == nil {
-			return false, errors.New("nil public key")
+		pub, ok := validPublicKey(pubKeys[i])
+		if !ok {
+			return false, errors.Errorf("public key at index %d is not a valid herumi public key", i)
 		}
 		// Perform scalar multiplication for the corresponding g1 points.
 		g1 := new(bls12.G1)
-		bls12.G1Mul(g1, bls12.CastFromPublicKey(pubKeys[i].(*PublicKey).p), &randNums[i])
+		bls12.G1Mul(g1, bls12.CastFromPublicKey(pub.p), &randNums[i])
 		multiKeys[i] = *bls12.CastToPublicKey(g1)
 	}
 	aggSig := bls12.CastToSign(finalSig)
@@ -205,15 +346,82 @@ func VerifyMultipleSignatures(sigs []common.Signature, msgs [][32]byte, pubKeys
 
 // Marshal a signature into a LittleEndian byte slice.
 func (s *Signature) Marshal() []byte {
-	if featureconfig.Get().SkipBLSVerify {
+	if featureconfig.Get().SkipBLSVerify || s.s == nil {
 		return make([]byte, params.BeaconConfig().BLSSignatureLength)
 	}
 
 	return s.s.Serialize()
 }
 
-// Copy returns a full deep copy of a signature.
+// MarshalHex returns the 0x-prefixed hex encoding of the compressed signature.
+func (s *Signature) MarshalHex() string {
+	return fmt.Sprintf("%#x", s.Marshal())
+}
+
+// String returns the 0x-prefixed hex encoding of the compressed signature, for logging.
+// Placeholder signatures, as read with SkipBLSVerify set, are written as "0xmock".
+func (s *Signature) String() string {
+	if featureconfig.Get().SkipBLSVerify || s.s == nil {
+		return placeholderHex
+	}
+	return s.MarshalHex()
+}
+
+// Equals checks whether two signatures are the same point, without
+// compressing them as comparing their marshaled bytes would.
+func (s *Signature) Equals(other common.Signature) bool {
+	if featureconfig.Get().SkipBLSVerify {
+		return true
+	}
+	otherSig, ok := other.(*Signature)
+	if !ok || otherSig == nil || s.s == nil || otherSig.s == nil {
+		return false
+	}
+	return s.s.IsEqual(otherSig.s)
+}
+
+// ContributorCount returns the number of signatures aggregated into the signature.
+func (s *Signature) ContributorCount() int {
+	return s.contributors
+}
+
+// IsInfinite checks if the signature is the point at infinity, which is what
+// signatures aggregate to when they cancel each other out.
+func (s *Signature) IsInfinite() bool {
+	if s.s == nil {
+		return false
+	}
+	return s.s.IsZero()
+}
+
+// Copy returns a full deep copy of a signature. The copy of a placeholder
+// signature, as read with SkipBLSVerify set, is a placeholder.
 func (s *Signature) Copy() common.Signature {
+	if s.s == nil {
+		return &Signature{contributors: s.contributors}
+	}
 	sign := *s.s
-	return &Signature{s: &sign}
+	return &Signature{s: &sign, contributors: s.contributors}
+}
+
+// Marshals signatures for a verification trace, leaving nil signatures empty.
+func traceSignatures(sigs []common.Signature) [][]byte {
+	enc := make([][]byte, len(sigs))
+	for i, sig := range sigs {
+		if sig, ok := sig.(*Signature); ok && sig != nil {
+			enc[i] = sig.Marshal()
+		}
+	}
+	return enc
+}
+
+// Returns the public key as a herumi public key. A nil or foreign key can only come from
+// a malformed input, so it is counted and rejected rather than causing a panic.
+func validPublicKey(pubKey common.PublicKey) (*PublicKey, bool) {
+	pub, ok := pubKey.(*PublicKey)
+	if !ok || pub == nil || pub.p == nil {
+		common.MalformedPublicKeys.Inc()
+		return nil, false
+	}
+	return pub, true
 }
@@ -1,11 +1,16 @@
 package herumi
 
 import (
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	bls12 "github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prysmaticlabs/prysm/shared/bls/common"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 )
@@ -33,10 +38,116 @@ func TestAggregateVerify(t *testing.T) {
 		sigs = append(sigs, sig)
 		msgs = append(msgs, msg)
 	}
-	aggSig := Aggregate(sigs)
+	aggSig, err := Aggregate(sigs)
+	require.NoError(t, err)
 	assert.DeepEqual(t, true, aggSig.AggregateVerify(pubkeys, msgs))
 }
 
+func TestAggregateVerify_NilPublicKey(t *testing.T) {
+	var pubkeys []common.PublicKey
+	var sigs []common.Signature
+	var msgs [][32]byte
+	for i := 0; i < 3; i++ {
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]))
+		msgs = append(msgs, msg)
+	}
+	aggSig, err := Aggregate(sigs)
+	require.NoError(t, err)
+	require.Equal(t, true, aggSig.AggregateVerify(pubkeys, msgs), "Signature did not verify")
+
+	for name, malformed := range malformedPublicKeys() {
+		keys := append([]common.PublicKey{}, pubkeys...)
+		keys[1] = malformed
+		assert.Equal(t, false, aggSig.AggregateVerify(keys, msgs), "Signature verified with a %s public key", name)
+	}
+}
+
+// The public keys which can only come from a malformed input.
+func malformedPublicKeys() map[string]common.PublicKey {
+	var nilKey *PublicKey
+	return map[string]common.PublicKey{
+		"nil interface": nil,
+		"nil pointer":   nilKey,
+		"nil point":     &PublicKey{},
+	}
+}
+
+func TestVerify_NilPublicKey(t *testing.T) {
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	priv, err := RandKey()
+	require.NoError(t, err)
+	sig := priv.Sign(msg[:])
+	rejected := testutil.ToFloat64(common.MalformedPublicKeys)
+	for name, malformed := range malformedPublicKeys() {
+		assert.Equal(t, false, sig.Verify(malformed, msg[:]), "Signature verified with a %s public key", name)
+	}
+	assert.Equal(t, rejected+3, testutil.ToFloat64(common.MalformedPublicKeys))
+}
+
+func TestFastAggregateVerify_NilPublicKey(t *testing.T) {
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	var pubkeys []common.PublicKey
+	var sigs []common.Signature
+	for i := 0; i < 3; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]))
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	require.Equal(t, true, aggSig.FastAggregateVerify(pubkeys, msg), "Signature did not verify")
+	rejected := testutil.ToFloat64(common.MalformedPublicKeys)
+	for name, malformed := range malformedPublicKeys() {
+		keys := append([]common.PublicKey{}, pubkeys...)
+		keys[1] = malformed
+		assert.Equal(t, false, aggSig.FastAggregateVerify(keys, msg), "Signature verified with a %s public key", name)
+	}
+	assert.Equal(t, rejected+3, testutil.ToFloat64(common.MalformedPublicKeys))
+}
+
+func TestAggregateSignatures_NilSignature(t *testing.T) {
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	priv, err := RandKey()
+	require.NoError(t, err)
+	var nilSig *Signature
+	for name, malformed := range map[string]common.Signature{
+		"nil interface": nil,
+		"nil pointer":   nilSig,
+		"nil point":     &Signature{contributors: 1},
+	} {
+		_, err := AggregateSignatures([]common.Signature{priv.Sign(msg[:]), malformed})
+		assert.ErrorContains(t, "signature at index 1 is not a valid", err, "Aggregated a %s signature", name)
+	}
+}
+
+func TestVerifyMultipleSignatures_NilPublicKey(t *testing.T) {
+	var pubkeys []common.PublicKey
+	var sigs []common.Signature
+	var msgs [][32]byte
+	for i := 0; i < 3; i++ {
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]))
+		msgs = append(msgs, msg)
+	}
+	rejected := testutil.ToFloat64(common.MalformedPublicKeys)
+	for name, malformed := range malformedPublicKeys() {
+		keys := append([]common.PublicKey{}, pubkeys...)
+		keys[1] = malformed
+		verified, err := VerifyMultipleSignatures(sigs, msgs, keys)
+		assert.ErrorContains(t, "public key at index 1 is not a valid herumi public key", err, "Verified with a %s public key", name)
+		assert.Equal(t, false, verified)
+	}
+	assert.Equal(t, rejected+3, testutil.ToFloat64(common.MalformedPublicKeys))
+}
+
 func TestFastAggregateVerify(t *testing.T) {
 	pubkeys := make([]common.PublicKey, 0, 100)
 	sigs := make([]common.Signature, 0, 100)
@@ -49,10 +160,43 @@ func TestFastAggregateVerify(t *testing.T) {
 		pubkeys = append(pubkeys, pub)
 		sigs = append(sigs, sig)
 	}
-	aggSig := AggregateSignatures(sigs)
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
 	assert.DeepEqual(t, true, aggSig.FastAggregateVerify(pubkeys, msg))
 }
 
+func TestSignature_IsAggregate(t *testing.T) {
+	pubkeys := make([]common.PublicKey, 0, 4)
+	sigs := make([]common.Signature, 0, 4)
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	for i := 0; i < 4; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg[:]))
+	}
+	aggSig, err := AggregateSignatures(sigs[:3])
+	require.NoError(t, err)
+
+	assert.Equal(t, true, aggSig.IsAggregate(3, pubkeys[:3], msg), "Aggregate of 3 signers not recognized")
+	assert.Equal(t, false, aggSig.IsAggregate(2, pubkeys[:3], msg), "Mismatched signer count accepted")
+	assert.Equal(t, false, aggSig.IsAggregate(2, pubkeys[:2], msg), "Aggregate accepted for a subset of its signers")
+	assert.Equal(t, false, aggSig.IsAggregate(4, pubkeys, msg), "Aggregate accepted for a superset of its signers")
+	assert.Equal(t, false, aggSig.IsAggregate(3, pubkeys[1:], msg), "Aggregate accepted for a different committee")
+	assert.Equal(t, false, aggSig.IsAggregate(3, pubkeys[:3], [32]byte{'o', 't', 'h', 'e', 'r'}), "Aggregate accepted for another message")
+	assert.Equal(t, false, aggSig.IsAggregate(0, nil, msg), "Aggregate of no signers accepted")
+
+	// A single signature is an aggregate of one signer, but not of a committee.
+	assert.Equal(t, true, sigs[0].IsAggregate(1, pubkeys[:1], msg))
+
+	// Signing twice with one key does not make an aggregate of two signers.
+	doubleSig, err := AggregateSignatures([]common.Signature{sigs[0], sigs[0]})
+	require.NoError(t, err)
+	doubleKeys := []common.PublicKey{pubkeys[0], pubkeys[0]}
+	require.Equal(t, true, doubleSig.FastAggregateVerify(doubleKeys, msg))
+	assert.Equal(t, false, doubleSig.IsAggregate(2, doubleKeys, msg), "Duplicate signers accepted")
+}
+
 func TestMultipleSignatureVerification(t *testing.T) {
 	pubkeys := make([]common.PublicKey, 0, 100)
 	sigs := make([]common.Signature, 0, 100)
@@ -126,7 +270,8 @@ func TestMultipleSignatureVerification_FailsCorrectly(t *testing.T) {
 
 	// This method is expected to pass, as it would not
 	// be able to detect bad signatures
-	aggSig := AggregateSignatures(sigs)
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
 	if !aggSig.AggregateVerify(pubkeys, msgs) {
 		t.Error("Signature did not verify")
 	}
@@ -196,6 +341,14 @@ func TestSignatureFromBytes(t *testing.T) {
 	}
 }
 
+func TestSignatureFromBytes_NotInSubgroup(t *testing.T) {
+	// A point on the curve which is not in the G2 subgroup, from the deserialization_G2 spec tests.
+	notInG2, err := hex.DecodeString("8123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	_, err = SignatureFromBytes(notInG2)
+	assert.NotNil(t, err, "Signature outside of the subgroup was accepted")
+}
+
 func TestCopy(t *testing.T) {
 	signatureA := &Signature{s: bls12.HashAndMapToSignature([]byte("foo"))}
 	signatureB, ok := signatureA.Copy().(*Signature)
@@ -208,3 +361,135 @@ func TestCopy(t *testing.T) {
 	signatureA.s.Add(bls12.HashAndMapToSignature([]byte("bar")))
 	assert.DeepNotEqual(t, signatureA, signatureB)
 }
+
+func TestContributorCount(t *testing.T) {
+	blank := NewAggregateSignature()
+	assert.Equal(t, 0, blank.ContributorCount())
+	assert.Equal(t, 0, blank.Copy().ContributorCount())
+
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	pubKeys := make([]common.PublicKey, 0, 3)
+	sigs := make([]common.Signature, 0, 3)
+	for i := 0; i < 3; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		sig := priv.Sign(msg[:])
+		assert.Equal(t, 1, sig.ContributorCount())
+		pubKeys = append(pubKeys, priv.PublicKey())
+		sigs = append(sigs, sig)
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	assert.Equal(t, 3, aggSig.ContributorCount())
+	assert.Equal(t, true, aggSig.FastAggregateVerify(pubKeys, msg))
+
+	// Signatures from bytes count as a single contributor.
+	fromBytes, err := SignatureFromBytes(aggSig.Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, 1, fromBytes.ContributorCount())
+
+	// The mock seeded blank aggregate is rejected by verification.
+	assert.Equal(t, false, blank.Verify(pubKeys[0], msg[:]))
+	assert.Equal(t, false, blank.AggregateVerify(pubKeys[:1], [][32]byte{msg}))
+	assert.Equal(t, false, blank.FastAggregateVerify(pubKeys, msg))
+
+	// A valid signature point is rejected too when it has no contributors.
+	noContributors := &Signature{s: aggSig.(*Signature).s}
+	assert.Equal(t, false, noContributors.FastAggregateVerify(pubKeys, msg))
+}
+
+func TestSignature_IsInfinite(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	sig := priv.Sign(msg[:])
+	assert.Equal(t, false, sig.IsInfinite())
+
+	infiniteSig := &Signature{s: &bls12.Sign{}, contributors: 1}
+	assert.Equal(t, true, infiniteSig.IsInfinite())
+	assert.Equal(t, false, infiniteSig.Verify(pub, msg[:]))
+	assert.Equal(t, false, infiniteSig.AggregateVerify([]common.PublicKey{pub}, [][32]byte{msg}))
+	assert.Equal(t, false, infiniteSig.FastAggregateVerify([]common.PublicKey{pub}, msg))
+
+	// Verification with the infinite public key fails rather than being attempted.
+	infinitePub := &PublicKey{p: &bls12.PublicKey{}}
+	require.Equal(t, true, infinitePub.IsInfinite())
+	assert.Equal(t, false, sig.Verify(infinitePub, msg[:]))
+	assert.Equal(t, false, sig.AggregateVerify([]common.PublicKey{infinitePub}, [][32]byte{msg}))
+	assert.Equal(t, false, sig.FastAggregateVerify([]common.PublicKey{pub, infinitePub}, msg))
+}
+
+func TestSignature_InfinityAggregateVerify(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	sig := priv.Sign(msg[:])
+
+	// Flipping the sign bit of a compressed point negates it, so the negated signature
+	// and public key cancel out the original ones when aggregated.
+	negSigBytes := sig.Marshal()
+	negSigBytes[0] ^= 0x20
+	negSig, err := SignatureFromBytes(negSigBytes)
+	require.NoError(t, err)
+	negPubBytes := pub.Marshal()
+	negPubBytes[0] ^= 0x20
+	negPub, err := PublicKeyFromBytes(negPubBytes)
+	require.NoError(t, err)
+	require.Equal(t, true, negSig.Verify(negPub, msg[:]), "Negated signature did not verify")
+	infiniteSig, err := AggregateSignatures([]common.Signature{sig, negSig})
+	require.NoError(t, err)
+	require.Equal(t, true, infiniteSig.IsInfinite())
+	require.Equal(t, 2, infiniteSig.ContributorCount())
+
+	// The public keys aggregate to the infinity point too, so the pairing check alone would pass.
+	pubKeys := []common.PublicKey{pub, negPub}
+	assert.Equal(t, false, infiniteSig.FastAggregateVerify(pubKeys, msg))
+	assert.Equal(t, false, infiniteSig.AggregateVerify(pubKeys, [][32]byte{msg, msg}))
+	assert.Equal(t, false, infiniteSig.FastAggregateVerify(nil, msg))
+	assert.Equal(t, false, infiniteSig.AggregateVerify(nil, nil))
+	assert.Equal(t, false, infiniteSig.AggregateVerify(pubKeys, nil))
+
+	// The empty set is invalid for any signature.
+	assert.Equal(t, false, sig.FastAggregateVerify(nil, msg))
+	assert.Equal(t, false, sig.AggregateVerify(nil, nil))
+}
+
+func TestSignatureEquals(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	msg := []byte("hello")
+	sig := priv.Sign(msg)
+	assert.Equal(t, true, sig.Equals(priv.Sign(msg)))
+	assert.Equal(t, true, sig.Equals(sig.Copy()))
+
+	// Tamper with the signature by signing a different message.
+	assert.Equal(t, false, sig.Equals(priv.Sign([]byte("tampered"))))
+	otherPriv, err := RandKey()
+	require.NoError(t, err)
+	assert.Equal(t, false, sig.Equals(otherPriv.Sign(msg)))
+	assert.Equal(t, false, sig.Equals(nil))
+}
+
+func TestSignature_String(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	sig := priv.Sign([]byte("hello"))
+	hexSig := fmt.Sprint(sig)
+	require.Equal(t, true, strings.HasPrefix(hexSig, "0x"), hexSig)
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(hexSig, "0x"))
+	require.NoError(t, err)
+	parsed, err := SignatureFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, true, sig.Equals(parsed), "Signature parsed from its hex string differs")
+	assert.Equal(t, hexSig, fmt.Sprint(parsed))
+
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{SkipBLSVerify: true})
+	defer reset()
+	placeholder, err := SignatureFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "0xmock", fmt.Sprint(placeholder))
+	assert.Equal(t, "0xmock", fmt.Sprint(sig))
+}
@@ -0,0 +1,44 @@
+package bls
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// PublicKeyFromHex creates a BLS public key from its hex encoding, with or without a 0x
+// prefix. A malformed string fails with ErrInvalidHex or ErrInvalidHexLength.
+func PublicKeyFromHex(pubKey string) (PublicKey, error) {
+	b, err := decodeHex(pubKey, params.BeaconConfig().BLSPubkeyLength)
+	if err != nil {
+		return nil, err
+	}
+	return PublicKeyFromBytes(b)
+}
+
+// SignatureFromHex creates a BLS signature from its hex encoding, with or without a 0x
+// prefix. A malformed string fails with ErrInvalidHex or ErrInvalidHexLength.
+func SignatureFromHex(sig string) (Signature, error) {
+	b, err := decodeHex(sig, params.BeaconConfig().BLSSignatureLength)
+	if err != nil {
+		return nil, err
+	}
+	return SignatureFromBytes(b)
+}
+
+// Decodes a hex string, which may have a 0x prefix in either case, of the given number of bytes.
+func decodeHex(s string, length int) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidHex, "%v", err)
+	}
+	if len(b) != length {
+		return nil, errors.Wrapf(ErrInvalidHexLength, "expected %d bytes, got %d", length, len(b))
+	}
+	return b, nil
+}
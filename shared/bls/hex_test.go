@@ -0,0 +1,61 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestHex_RoundTrip(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pub := priv.PublicKey()
+		sig := priv.Sign([]byte("hello"))
+		assert.Equal(t, fmt.Sprintf("%#x", pub.Marshal()), pub.MarshalHex())
+		assert.Equal(t, fmt.Sprintf("%#x", sig.Marshal()), sig.MarshalHex())
+
+		for _, encode := range []func(string) string{
+			func(s string) string { return s },
+			strings.ToUpper,
+			func(s string) string { return strings.TrimPrefix(s, "0x") },
+			func(s string) string { return "0x" + strings.ToUpper(strings.TrimPrefix(s, "0x")) },
+		} {
+			parsedPub, err := PublicKeyFromHex(encode(pub.MarshalHex()))
+			require.NoError(t, err)
+			assert.DeepEqual(t, pub.Marshal(), parsedPub.Marshal())
+			parsedSig, err := SignatureFromHex(encode(sig.MarshalHex()))
+			require.NoError(t, err)
+			assert.DeepEqual(t, sig.Marshal(), parsedSig.Marshal())
+		}
+		reset()
+	}
+}
+
+func TestHex_Malformed(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	pubHex := priv.PublicKey().MarshalHex()
+	sigHex := priv.Sign([]byte("hello")).MarshalHex()
+
+	_, err = PublicKeyFromHex(pubHex[:len(pubHex)-2])
+	assert.Equal(t, true, errors.Is(err, ErrInvalidHexLength), err)
+	_, err = SignatureFromHex(pubHex)
+	assert.Equal(t, true, errors.Is(err, ErrInvalidHexLength), err)
+	_, err = PublicKeyFromHex(pubHex[:len(pubHex)-1])
+	assert.Equal(t, true, errors.Is(err, ErrInvalidHex), err)
+	_, err = SignatureFromHex("0x" + strings.Repeat("zz", 96))
+	assert.Equal(t, true, errors.Is(err, ErrInvalidHex), err)
+	_, err = SignatureFromHex("")
+	assert.Equal(t, true, errors.Is(err, ErrInvalidHexLength), err)
+
+	// Well formed hex of an invalid point still fails to parse.
+	_, err = SignatureFromHex(strings.Replace(sigHex, sigHex[2:6], "ffff", 1))
+	assert.NotNil(t, err)
+}
@@ -12,3 +12,6 @@ type SecretKey = common.SecretKey
 
 // Signature represents a BLS signature.
 type Signature = common.Signature
+
+// VerificationTrace records the inputs and result of a signature verification.
+type VerificationTrace = common.VerificationTrace
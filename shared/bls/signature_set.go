@@ -1,5 +1,10 @@
 package bls
 
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
 // SignatureSet refers to the defined set of
 // signatures and its respective public keys and
 // messages required to verify it.
@@ -18,6 +23,32 @@ func NewSet() *SignatureSet {
 	}
 }
 
+// SignatureSetFromRoots constructs a signature set from object roots and their signing
+// domains, computing each signing root once while the set is assembled. This matches
+// the spec's compute_signing_root, hash_tree_root(SigningData(object_root, domain)).
+func SignatureSetFromRoots(objectRoots [][32]byte, domains [][]byte, pubKeys []PublicKey, sigs [][]byte) (*SignatureSet, error) {
+	if len(objectRoots) != len(domains) || len(objectRoots) != len(pubKeys) || len(objectRoots) != len(sigs) {
+		return nil, errors.Errorf(
+			"mismatched lengths: %d object roots, %d domains, %d public keys, %d signatures",
+			len(objectRoots), len(domains), len(pubKeys), len(sigs),
+		)
+	}
+	msgs := make([][32]byte, len(objectRoots))
+	for i, root := range objectRoots {
+		if len(domains[i]) != 32 {
+			return nil, errors.Errorf("domain at index %d has length %d, wanted 32", i, len(domains[i]))
+		}
+		// SigningData is a container of two 32 byte fields, so its hash tree
+		// root is the hash of the concatenated fields.
+		msgs[i] = hashutil.Hash(append(root[:], domains[i]...))
+	}
+	return &SignatureSet{
+		Signatures: sigs,
+		PublicKeys: pubKeys,
+		Messages:   msgs,
+	}, nil
+}
+
 // Join merges the provided signature set to out current one.
 func (s *SignatureSet) Join(set *SignatureSet) *SignatureSet {
 	s.Signatures = append(s.Signatures, set.Signatures...)
@@ -26,6 +57,59 @@ func (s *SignatureSet) Join(set *SignatureSet) *SignatureSet {
 	return s
 }
 
+// MergeSets concatenates the provided signature sets into a new set and removes the
+// entries repeated across them, so the merged set can be verified in a single batch
+// with fewer pairings than verifying each set on its own.
+func MergeSets(sets ...*SignatureSet) *SignatureSet {
+	merged := NewSet()
+	for _, set := range sets {
+		if set == nil {
+			continue
+		}
+		merged.Join(set)
+	}
+	return merged.RemoveDuplicates()
+}
+
+// RemoveDuplicates removes entries with the same signature, public key and message as
+// an earlier entry in the set, keeping the order of the remaining entries. Verifying a
+// repeated entry again cannot change the outcome of a batch verification.
+func (s *SignatureSet) RemoveDuplicates() *SignatureSet {
+	// Sets with mismatched lengths are left untouched for Verify to reject.
+	if len(s.PublicKeys) != len(s.Signatures) || len(s.Messages) != len(s.Signatures) {
+		return s
+	}
+	seen := make(map[[32]byte]bool, len(s.Signatures))
+	sigs := make([][]byte, 0, len(s.Signatures))
+	pubKeys := make([]PublicKey, 0, len(s.PublicKeys))
+	msgs := make([][32]byte, 0, len(s.Messages))
+	for i := range s.Signatures {
+		key := tripleKey(s.Signatures[i], s.PublicKeys[i], s.Messages[i])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		sigs = append(sigs, s.Signatures[i])
+		pubKeys = append(pubKeys, s.PublicKeys[i])
+		msgs = append(msgs, s.Messages[i])
+	}
+	s.Signatures = sigs
+	s.PublicKeys = pubKeys
+	s.Messages = msgs
+	return s
+}
+
+// Hashes a triple into the key identifying it among the entries of a set.
+func tripleKey(sig []byte, pubKey PublicKey, msg [32]byte) [32]byte {
+	data := make([]byte, 0, len(sig)+48+len(msg))
+	data = append(data, sig...)
+	if pubKey != nil {
+		data = append(data, pubKey.Marshal()...)
+	}
+	data = append(data, msg[:]...)
+	return hashutil.Hash(data)
+}
+
 // Verify the current signature set using the batch verify algorithm.
 func (s *SignatureSet) Verify() (bool, error) {
 	return VerifyMultipleSignatures(s.Signatures, s.Messages, s.PublicKeys)
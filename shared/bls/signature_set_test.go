@@ -0,0 +1,136 @@
+package bls
+
+import (
+	"testing"
+
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestSignatureSetFromRoots(t *testing.T) {
+	numSigs := 5
+	objectRoots := make([][32]byte, numSigs)
+	domains := make([][]byte, numSigs)
+	pubKeys := make([]PublicKey, numSigs)
+	sigs := make([][]byte, numSigs)
+	signingRoots := make([][32]byte, numSigs)
+	for i := 0; i < numSigs; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		objectRoots[i] = bytesutil.ToBytes32([]byte{'r', 'o', 'o', 't', byte(i)})
+		domains[i] = bytesutil.PadTo([]byte{'d', 'o', 'm', byte(i)}, 32)
+		signingData := &pb.SigningData{
+			ObjectRoot: objectRoots[i][:],
+			Domain:     domains[i],
+		}
+		signingRoots[i], err = signingData.HashTreeRoot()
+		require.NoError(t, err)
+		pubKeys[i] = priv.PublicKey()
+		sigs[i] = priv.Sign(signingRoots[i][:]).Marshal()
+	}
+
+	set, err := SignatureSetFromRoots(objectRoots, domains, pubKeys, sigs)
+	require.NoError(t, err)
+	assert.DeepEqual(t, signingRoots, set.Messages)
+	verified, err := set.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, true, verified, "Signature set did not verify")
+
+	// Signatures which do not match their signing roots should fail batch verification.
+	sigs[0], sigs[1] = sigs[1], sigs[0]
+	set, err = SignatureSetFromRoots(objectRoots, domains, pubKeys, sigs)
+	require.NoError(t, err)
+	verified, err = set.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, false, verified, "Signature set with swapped signatures verified")
+}
+
+func TestSignatureSetFromRoots_MismatchedLengths(t *testing.T) {
+	priv, err := RandKey()
+	require.NoError(t, err)
+	_, err = SignatureSetFromRoots(
+		[][32]byte{{}, {}},
+		[][]byte{make([]byte, 32)},
+		[]PublicKey{priv.PublicKey()},
+		[][]byte{priv.Sign(make([]byte, 32)).Marshal()},
+	)
+	assert.ErrorContains(t, "mismatched lengths", err)
+
+	_, err = SignatureSetFromRoots(
+		[][32]byte{{}},
+		[][]byte{make([]byte, 4)},
+		[]PublicKey{priv.PublicKey()},
+		[][]byte{priv.Sign(make([]byte, 32)).Marshal()},
+	)
+	assert.ErrorContains(t, "wanted 32", err)
+}
+
+func TestMergeSets(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 4)
+	// Two batches sharing the entries at indices 1 and 2, such as an attestation
+	// included both on its own and as part of another block component.
+	first := &SignatureSet{
+		Signatures: sigs[:3],
+		PublicKeys: pubKeys[:3],
+		Messages:   msgs[:3],
+	}
+	second := &SignatureSet{
+		Signatures: sigs[1:],
+		PublicKeys: pubKeys[1:],
+		Messages:   msgs[1:],
+	}
+	merged := MergeSets(first, nil, second)
+	// Batch verification needs one pairing per entry plus one for the aggregate
+	// signature, so the merged set needs 5 pairings instead of 4 + 4.
+	assert.Equal(t, 4, len(merged.Signatures))
+	assert.Equal(t, len(first.Signatures)+len(second.Signatures)-2, len(merged.Signatures))
+	assert.DeepEqual(t, sigs, merged.Signatures)
+	assert.DeepEqual(t, msgs, merged.Messages)
+	// The input sets are left untouched.
+	assert.Equal(t, 3, len(first.Signatures))
+	assert.Equal(t, 3, len(second.Signatures))
+
+	verified, err := merged.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, true, verified)
+
+	// An invalid entry in either set still fails the merged verification.
+	badSet := &SignatureSet{
+		Signatures: [][]byte{sigs[0]},
+		PublicKeys: []PublicKey{pubKeys[0]},
+		Messages:   [][32]byte{{'b', 'a', 'd'}},
+	}
+	verified, err = MergeSets(first, second, badSet).Verify()
+	require.NoError(t, err)
+	assert.Equal(t, false, verified)
+}
+
+func TestSignatureSet_RemoveDuplicates(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 2)
+	set := &SignatureSet{
+		Signatures: [][]byte{sigs[0], sigs[1], sigs[0]},
+		PublicKeys: []PublicKey{pubKeys[0], pubKeys[1], pubKeys[0]},
+		Messages:   [][32]byte{msgs[0], msgs[1], msgs[0]},
+	}
+	set.RemoveDuplicates()
+	assert.DeepEqual(t, sigs, set.Signatures)
+	assert.DeepEqual(t, msgs, set.Messages)
+
+	// Entries sharing a signature but not a message are kept.
+	set = &SignatureSet{
+		Signatures: [][]byte{sigs[0], sigs[0]},
+		PublicKeys: []PublicKey{pubKeys[0], pubKeys[0]},
+		Messages:   [][32]byte{msgs[0], msgs[1]},
+	}
+	assert.Equal(t, 2, len(set.RemoveDuplicates().Signatures))
+
+	// Mismatched sets are left for Verify to reject.
+	set = &SignatureSet{
+		Signatures: [][]byte{sigs[0], sigs[0]},
+		PublicKeys: []PublicKey{pubKeys[0]},
+		Messages:   [][32]byte{msgs[0], msgs[0]},
+	}
+	assert.Equal(t, 2, len(set.RemoveDuplicates().Signatures))
+}
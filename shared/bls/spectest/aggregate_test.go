@@ -49,13 +49,14 @@ func testAggregateYaml(t *testing.T) {
 				}
 				return
 			}
-			sig := bls.AggregateSignatures(sigs)
+			sig, err := bls.AggregateSignatures(sigs)
 			if strings.Contains(folder.Name(), "aggregate_na_pubkeys") {
-				if sig != nil {
-					t.Errorf("Expected nil signature, received: %v", sig)
+				if err == nil {
+					t.Errorf("Expected an error, received signature: %v", sig)
 				}
 				return
 			}
+			require.NoError(t, err)
 			outputBytes, err := hex.DecodeString(test.Output[2:])
 			require.NoError(t, err)
 			require.DeepEqual(t, outputBytes, sig.Marshal())
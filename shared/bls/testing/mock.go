@@ -0,0 +1,45 @@
+// Package testing provides a mock BLS verifier for unit tests which should not
+// depend on real signatures.
+package testing
+
+import (
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+var _ bls.Verifier = (*MockVerifier)(nil)
+
+// MockVerifier is a no-op verifier which reports every signature as valid, or as
+// invalid when Fail is set, without checking any of them.
+type MockVerifier struct {
+	Fail bool
+}
+
+// Verify --
+func (m *MockVerifier) Verify(_ bls.Signature, _ bls.PublicKey, _ []byte) bool {
+	return !m.Fail
+}
+
+// AggregateVerify --
+func (m *MockVerifier) AggregateVerify(_ bls.Signature, _ []bls.PublicKey, _ [][32]byte) bool {
+	return !m.Fail
+}
+
+// FastAggregateVerify --
+func (m *MockVerifier) FastAggregateVerify(_ bls.Signature, _ []bls.PublicKey, _ [32]byte) bool {
+	return !m.Fail
+}
+
+// VerifyCompressed --
+func (m *MockVerifier) VerifyCompressed(_, _, _ []byte) bool {
+	return !m.Fail
+}
+
+// VerifyCompressedBatch --
+func (m *MockVerifier) VerifyCompressedBatch(_, _, _ [][]byte) (bool, error) {
+	return !m.Fail, nil
+}
+
+// VerifyMultipleSignatures --
+func (m *MockVerifier) VerifyMultipleSignatures(_ [][]byte, _ [][32]byte, _ []bls.PublicKey) (bool, error) {
+	return !m.Fail, nil
+}
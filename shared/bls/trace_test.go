@@ -0,0 +1,54 @@
+package bls
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestSetVerificationTrace(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		priv, err := RandKey()
+		require.NoError(t, err)
+		pub := priv.PublicKey()
+		msg := [32]byte{'m', 's', 'g'}
+		sig := priv.Sign(msg[:])
+
+		var buf bytes.Buffer
+		SetVerificationTrace(&buf)
+		require.Equal(t, true, sig.Verify(pub, msg[:]))
+		require.Equal(t, false, sig.FastAggregateVerify([]PublicKey{pub}, [32]byte{'b', 'a', 'd'}))
+		require.Equal(t, true, VerifyCompressed(sig.Marshal(), pub.Marshal(), msg[:]))
+		// Verifications are not traced once tracing is disabled.
+		SetVerificationTrace(nil)
+		require.Equal(t, true, sig.Verify(pub, msg[:]))
+
+		var traces []*VerificationTrace
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			trace := &VerificationTrace{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), trace))
+			traces = append(traces, trace)
+		}
+		require.NoError(t, scanner.Err())
+		require.Equal(t, 3, len(traces))
+		assert.DeepEqual(t, &VerificationTrace{
+			Function:   "Verify",
+			Signatures: []string{sig.MarshalHex()},
+			PublicKeys: []string{pub.MarshalHex()},
+			Messages:   []string{fmt.Sprintf("%#x", msg)},
+			Result:     true,
+		}, traces[0])
+		assert.Equal(t, "FastAggregateVerify", traces[1].Function)
+		assert.Equal(t, false, traces[1].Result)
+		assert.Equal(t, true, traces[2].Result)
+		reset()
+	}
+}
@@ -0,0 +1,232 @@
+package bls
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// VerifiedFilter remembers recently verified signature, public key and message triples
+// in a bloom filter so batches containing the same triples again, such as attestations
+// recurring across slots, can skip re-verifying them. Triples are remembered for a
+// sliding window by rotating between two filter generations: a triple is remembered
+// for at least half a window and at most a full window after it was verified. A
+// generation holding the expected number of triples is rotated early, so the false
+// positive rate is never exceeded however many triples are verified in a window.
+//
+// A bloom filter may report a triple it has never seen, with the configured false
+// positive rate. Triples are keyed with a random salt, so which triples collide cannot
+// be predicted across processes. In strict mode a filter hit is never trusted: the hit
+// triples are verified in full on their own, so the filter can never cause an invalid
+// signature to be accepted.
+type VerifiedFilter struct {
+	lock          sync.Mutex
+	strict        bool
+	window        time.Duration
+	expectedItems int
+	numBits       uint64
+	numHashes     uint64
+	salt          []byte
+	current       []uint64
+	currentItems  int
+	previous      []uint64
+	rotatedAt     time.Time
+	now           func() time.Time
+}
+
+// NewVerifiedFilter creates a filter sized to remember expectedItems triples per
+// window with the given false positive rate.
+func NewVerifiedFilter(expectedItems int, falsePositiveRate float64, window time.Duration, strict bool) (*VerifiedFilter, error) {
+	if expectedItems <= 0 {
+		return nil, errors.New("expected number of items must be greater than 0")
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, errors.New("false positive rate must be between 0 and 1")
+	}
+	if window <= 0 {
+		return nil, errors.New("window must be greater than 0")
+	}
+	// Optimal bloom filter parameters for n items with false positive rate p:
+	// m = -n*ln(p)/ln(2)^2 bits and k = (m/n)*ln(2) hash functions.
+	numBits := uint64(math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	numHashes := uint64(math.Round(float64(numBits) / float64(expectedItems) * math.Ln2))
+	if numHashes < 1 {
+		numHashes = 1
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "could not generate filter salt")
+	}
+	f := &VerifiedFilter{
+		strict:        strict,
+		window:        window,
+		expectedItems: expectedItems,
+		numBits:       numBits,
+		numHashes:     numHashes,
+		salt:          salt,
+		now:           time.Now,
+	}
+	f.current = f.newGeneration()
+	f.previous = f.newGeneration()
+	f.rotatedAt = f.now()
+	return f, nil
+}
+
+// VerifyMultipleSignatures verifies a batch of signatures like the package level
+// VerifyMultipleSignatures, skipping triples verified within the window. In strict mode
+// the triples found in the filter are verified separately instead. Only triples missing
+// from the filter are remembered, once their batch verifies.
+func (f *VerifiedFilter) VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []PublicKey) (bool, error) {
+	if len(msgs) != len(sigs) || len(pubKeys) != len(sigs) {
+		return false, errors.Errorf(
+			"provided signatures, pubkeys and messages have differing lengths. S: %d, P: %d, M: %d",
+			len(sigs), len(pubKeys), len(msgs),
+		)
+	}
+	unseenKeys := make([][32]byte, 0, len(sigs))
+	unseen := newSignatureBatch(len(sigs))
+	hits := newSignatureBatch(0)
+	f.lock.Lock()
+	f.rotate()
+	for i := range sigs {
+		key := f.tripleKey(sigs[i], pubKeys[i], msgs[i])
+		if f.contains(key) {
+			if f.strict {
+				hits.append(sigs[i], msgs[i], pubKeys[i])
+			}
+			continue
+		}
+		unseenKeys = append(unseenKeys, key)
+		unseen.append(sigs[i], msgs[i], pubKeys[i])
+	}
+	f.lock.Unlock()
+
+	// Filter hits may be false positives, so they are not remembered again as new triples.
+	if len(hits.sigs) > 0 {
+		verified, err := VerifyMultipleSignatures(hits.sigs, hits.msgs, hits.pubKeys)
+		if err != nil || !verified {
+			return verified, err
+		}
+	}
+	if len(unseen.sigs) == 0 {
+		return true, nil
+	}
+	verified, err := VerifyMultipleSignatures(unseen.sigs, unseen.msgs, unseen.pubKeys)
+	if err != nil || !verified {
+		return verified, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, key := range unseenKeys {
+		f.add(key)
+	}
+	return true, nil
+}
+
+// Signatures collected for a single call to VerifyMultipleSignatures.
+type signatureBatch struct {
+	sigs    [][]byte
+	msgs    [][32]byte
+	pubKeys []PublicKey
+}
+
+func newSignatureBatch(capacity int) *signatureBatch {
+	return &signatureBatch{
+		sigs:    make([][]byte, 0, capacity),
+		msgs:    make([][32]byte, 0, capacity),
+		pubKeys: make([]PublicKey, 0, capacity),
+	}
+}
+
+func (b *signatureBatch) append(sig []byte, msg [32]byte, pubKey PublicKey) {
+	b.sigs = append(b.sigs, sig)
+	b.msgs = append(b.msgs, msg)
+	b.pubKeys = append(b.pubKeys, pubKey)
+}
+
+// Hashes a triple into the key stored in the filter, keyed with the salt of the filter.
+func (f *VerifiedFilter) tripleKey(sig []byte, pubKey PublicKey, msg [32]byte) [32]byte {
+	mac := hmac.New(sha256.New, f.salt)
+	// Writing to a hash never returns an error.
+	_, _ = mac.Write(sig)
+	if pubKey != nil {
+		_, _ = mac.Write(pubKey.Marshal())
+	}
+	_, _ = mac.Write(msg[:])
+	var key [32]byte
+	copy(key[:], mac.Sum(nil))
+	return key
+}
+
+func (f *VerifiedFilter) newGeneration() []uint64 {
+	return make([]uint64, (f.numBits+63)/64)
+}
+
+// Starts a new filter generation every half window, forgetting the oldest one.
+func (f *VerifiedFilter) rotate() {
+	now := f.now()
+	if now.Sub(f.rotatedAt) < f.window/2 {
+		return
+	}
+	if now.Sub(f.rotatedAt) >= f.window {
+		// Both generations have expired.
+		f.previous = f.newGeneration()
+	} else {
+		f.previous = f.current
+	}
+	f.current = f.newGeneration()
+	f.currentItems = 0
+	f.rotatedAt = now
+}
+
+// Starts a new filter generation ahead of time once the current one is full, as
+// adding more triples than it was sized for raises its false positive rate.
+func (f *VerifiedFilter) rotateFull() {
+	if f.currentItems < f.expectedItems {
+		return
+	}
+	f.previous = f.current
+	f.current = f.newGeneration()
+	f.currentItems = 0
+	f.rotatedAt = f.now()
+}
+
+// Returns the bit positions of a key, using double hashing over the
+// two halves of the key to derive each of the hash functions.
+func (f *VerifiedFilter) positions(key [32]byte) []uint64 {
+	h1 := binary.LittleEndian.Uint64(key[:8])
+	h2 := binary.LittleEndian.Uint64(key[8:16])
+	positions := make([]uint64, f.numHashes)
+	for i := uint64(0); i < f.numHashes; i++ {
+		positions[i] = (h1 + i*h2) % f.numBits
+	}
+	return positions
+}
+
+func (f *VerifiedFilter) add(key [32]byte) {
+	f.rotateFull()
+	for _, pos := range f.positions(key) {
+		f.current[pos/64] |= 1 << (pos % 64)
+	}
+	f.currentItems++
+}
+
+func (f *VerifiedFilter) contains(key [32]byte) bool {
+	positions := f.positions(key)
+	return isSet(f.current, positions) || isSet(f.previous, positions)
+}
+
+func isSet(bits []uint64, positions []uint64) bool {
+	for _, pos := range positions {
+		if bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package bls
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func testTriples(t *testing.T, n int) ([][]byte, [][32]byte, []PublicKey) {
+	sigs := make([][]byte, n)
+	msgs := make([][32]byte, n)
+	pubKeys := make([]PublicKey, n)
+	for i := 0; i < n; i++ {
+		priv, err := RandKey()
+		require.NoError(t, err)
+		msgs[i] = [32]byte{'m', 's', 'g', byte(i)}
+		sigs[i] = priv.Sign(msgs[i][:]).Marshal()
+		pubKeys[i] = priv.PublicKey()
+	}
+	return sigs, msgs, pubKeys
+}
+
+func TestNewVerifiedFilter(t *testing.T) {
+	_, err := NewVerifiedFilter(0, 0.01, time.Minute, false)
+	assert.ErrorContains(t, "expected number of items", err)
+	_, err = NewVerifiedFilter(100, 0, time.Minute, false)
+	assert.ErrorContains(t, "false positive rate", err)
+	_, err = NewVerifiedFilter(100, 1, time.Minute, false)
+	assert.ErrorContains(t, "false positive rate", err)
+	_, err = NewVerifiedFilter(100, 0.01, 0, false)
+	assert.ErrorContains(t, "window", err)
+
+	f, err := NewVerifiedFilter(1000, 0.01, time.Minute, false)
+	require.NoError(t, err)
+	// 1000 items at a 1% false positive rate need ~9586 bits and 7 hash functions.
+	assert.Equal(t, uint64(9586), f.numBits)
+	assert.Equal(t, uint64(7), f.numHashes)
+}
+
+func TestVerifiedFilter_VerifyMultipleSignatures(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 4)
+	f, err := NewVerifiedFilter(100, 0.01, time.Minute, false)
+	require.NoError(t, err)
+
+	_, err = f.VerifyMultipleSignatures(sigs, msgs[:3], pubKeys)
+	assert.ErrorContains(t, "differing lengths", err)
+
+	// An invalid batch is rejected and none of its triples are remembered.
+	badMsgs := append([][32]byte{{'b', 'a', 'd'}}, msgs[1:]...)
+	verified, err := f.VerifyMultipleSignatures(sigs, badMsgs, pubKeys)
+	require.NoError(t, err)
+	assert.Equal(t, false, verified)
+	for i := range sigs {
+		assert.Equal(t, false, f.contains(f.tripleKey(sigs[i], pubKeys[i], badMsgs[i])))
+	}
+
+	verified, err = f.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+	require.NoError(t, err)
+	assert.Equal(t, true, verified)
+	for i := range sigs {
+		assert.Equal(t, true, f.contains(f.tripleKey(sigs[i], pubKeys[i], msgs[i])))
+	}
+	verified, err = f.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+	require.NoError(t, err)
+	assert.Equal(t, true, verified)
+}
+
+func TestVerifiedFilter_FilterHitThenVerify(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 2)
+	badMsg := [32]byte{'b', 'a', 'd'}
+	for _, strict := range []bool{false, true} {
+		f, err := NewVerifiedFilter(100, 0.01, time.Minute, strict)
+		require.NoError(t, err)
+		// Simulate a false positive for an invalid triple.
+		f.add(f.tripleKey(sigs[0], pubKeys[0], badMsg))
+
+		verified, err := f.VerifyMultipleSignatures(sigs[:1], [][32]byte{badMsg}, pubKeys[:1])
+		require.NoError(t, err)
+		// Only strict mode falls back to full verification on a filter hit.
+		assert.Equal(t, !strict, verified)
+
+		verified, err = f.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+		require.NoError(t, err)
+		assert.Equal(t, true, verified)
+	}
+}
+
+func TestVerifiedFilter_StrictHitsAreNotRemembered(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 2)
+	f, err := NewVerifiedFilter(100, 0.01, time.Minute, true)
+	require.NoError(t, err)
+	verified, err := f.VerifyMultipleSignatures(sigs[:1], msgs[:1], pubKeys[:1])
+	require.NoError(t, err)
+	require.Equal(t, true, verified)
+	require.Equal(t, 1, f.currentItems)
+
+	// The hit is verified again, but only the unseen triple is added to the filter.
+	verified, err = f.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+	require.NoError(t, err)
+	require.Equal(t, true, verified)
+	assert.Equal(t, 2, f.currentItems)
+}
+
+func TestVerifiedFilter_RotatesFullGeneration(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 3)
+	f, err := NewVerifiedFilter(2, 0.01, time.Minute, false)
+	require.NoError(t, err)
+	verified, err := f.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+	require.NoError(t, err)
+	require.Equal(t, true, verified)
+
+	// The third triple did not fit in the generation holding the first two.
+	assert.Equal(t, 1, f.currentItems)
+	for i := range sigs {
+		assert.Equal(t, true, f.contains(f.tripleKey(sigs[i], pubKeys[i], msgs[i])))
+	}
+	assert.Equal(t, true, isSet(f.current, f.positions(f.tripleKey(sigs[2], pubKeys[2], msgs[2]))))
+}
+
+func TestVerifiedFilter_SaltedKeys(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 1)
+	f1, err := NewVerifiedFilter(100, 0.01, time.Minute, false)
+	require.NoError(t, err)
+	f2, err := NewVerifiedFilter(100, 0.01, time.Minute, false)
+	require.NoError(t, err)
+	assert.Equal(t, f1.tripleKey(sigs[0], pubKeys[0], msgs[0]), f1.tripleKey(sigs[0], pubKeys[0], msgs[0]))
+	assert.NotEqual(t, f1.tripleKey(sigs[0], pubKeys[0], msgs[0]), f2.tripleKey(sigs[0], pubKeys[0], msgs[0]))
+}
+
+func TestVerifiedFilter_WindowExpiry(t *testing.T) {
+	sigs, msgs, pubKeys := testTriples(t, 1)
+	f, err := NewVerifiedFilter(100, 0.01, time.Minute, false)
+	require.NoError(t, err)
+	key := f.tripleKey(sigs[0], pubKeys[0], msgs[0])
+	now := time.Now()
+	f.now = func() time.Time {
+		return now
+	}
+	f.rotatedAt = now
+
+	verified, err := f.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+	require.NoError(t, err)
+	require.Equal(t, true, verified)
+
+	// After half a window the triple moves to the previous generation.
+	now = now.Add(30 * time.Second)
+	f.rotate()
+	assert.Equal(t, true, f.contains(key))
+
+	// After another half window it has expired.
+	now = now.Add(30 * time.Second)
+	f.rotate()
+	assert.Equal(t, false, f.contains(key))
+
+	// Re-verifying remembers it again, and a gap longer than
+	// a full window expires both generations at once.
+	verified, err = f.VerifyMultipleSignatures(sigs, msgs, pubKeys)
+	require.NoError(t, err)
+	require.Equal(t, true, verified)
+	assert.Equal(t, true, f.contains(key))
+	now = now.Add(2 * time.Minute)
+	f.rotate()
+	assert.Equal(t, false, f.contains(key))
+}
@@ -0,0 +1,68 @@
+package bls
+
+// Verifier verifies BLS signatures. It mirrors the verification functions of this
+// package so that services can depend on a verifier obtained from NewVerifier, and an
+// alternative backend, such as a hardware accelerated one, can be swapped in without
+// touching their call sites.
+type Verifier interface {
+	// Verify a signature of a message by a public key.
+	Verify(sig Signature, pubKey PublicKey, msg []byte) bool
+	// AggregateVerify verifies an aggregate signature of distinct messages by their public keys.
+	AggregateVerify(sig Signature, pubKeys []PublicKey, msgs [][32]byte) bool
+	// FastAggregateVerify verifies an aggregate signature of a single message by several public keys.
+	FastAggregateVerify(sig Signature, pubKeys []PublicKey, msg [32]byte) bool
+	// VerifyCompressed verifies a compressed signature of a message by a compressed public key.
+	VerifyCompressed(sig, pubKey, msg []byte) bool
+	// VerifyCompressedBatch verifies independent compressed signature, public key and message triples as one batch.
+	VerifyCompressedBatch(sigs, pubKeys, msgs [][]byte) (bool, error)
+	// VerifyMultipleSignatures verifies signatures of distinct messages securely as one batch.
+	VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []PublicKey) (bool, error)
+}
+
+// NewVerifier returns the default verifier, which is backed by the configured BLS
+// implementation: blst, unless it is disabled in favor of herumi.
+func NewVerifier() Verifier {
+	return &defaultVerifier{}
+}
+
+// The verifier backed by the package functions, which dispatch to the configured implementation.
+type defaultVerifier struct{}
+
+// Verify a signature of a message by a public key.
+func (*defaultVerifier) Verify(sig Signature, pubKey PublicKey, msg []byte) bool {
+	if sig == nil || pubKey == nil {
+		return false
+	}
+	return sig.Verify(pubKey, msg)
+}
+
+// AggregateVerify verifies an aggregate signature of distinct messages by their public keys.
+func (*defaultVerifier) AggregateVerify(sig Signature, pubKeys []PublicKey, msgs [][32]byte) bool {
+	if sig == nil {
+		return false
+	}
+	return sig.AggregateVerify(pubKeys, msgs)
+}
+
+// FastAggregateVerify verifies an aggregate signature of a single message by several public keys.
+func (*defaultVerifier) FastAggregateVerify(sig Signature, pubKeys []PublicKey, msg [32]byte) bool {
+	if sig == nil {
+		return false
+	}
+	return sig.FastAggregateVerify(pubKeys, msg)
+}
+
+// VerifyCompressed verifies a compressed signature of a message by a compressed public key.
+func (*defaultVerifier) VerifyCompressed(sig, pubKey, msg []byte) bool {
+	return VerifyCompressed(sig, pubKey, msg)
+}
+
+// VerifyCompressedBatch verifies independent compressed signature, public key and message triples as one batch.
+func (*defaultVerifier) VerifyCompressedBatch(sigs, pubKeys, msgs [][]byte) (bool, error) {
+	return VerifyCompressedBatch(sigs, pubKeys, msgs)
+}
+
+// VerifyMultipleSignatures verifies signatures of distinct messages securely as one batch.
+func (*defaultVerifier) VerifyMultipleSignatures(sigs [][]byte, msgs [][32]byte, pubKeys []PublicKey) (bool, error) {
+	return VerifyMultipleSignatures(sigs, msgs, pubKeys)
+}
@@ -0,0 +1,47 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestVerifier(t *testing.T) {
+	for _, enableBlst := range []bool{false, true} {
+		reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableBlst: enableBlst})
+		verifier := NewVerifier()
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+		pubKeys := make([]PublicKey, 3)
+		sigs := make([]Signature, len(pubKeys))
+		rawSigs := make([][]byte, len(pubKeys))
+		for i := range pubKeys {
+			priv, err := RandKey()
+			require.NoError(t, err)
+			pubKeys[i] = priv.PublicKey()
+			sigs[i] = priv.Sign(msg[:])
+			rawSigs[i] = sigs[i].Marshal()
+		}
+		aggregated := MustAggregateSignatures(sigs)
+
+		assert.Equal(t, true, verifier.Verify(sigs[0], pubKeys[0], msg[:]))
+		assert.Equal(t, false, verifier.Verify(sigs[0], pubKeys[1], msg[:]))
+		assert.Equal(t, false, verifier.Verify(nil, pubKeys[0], msg[:]))
+		assert.Equal(t, true, verifier.AggregateVerify(aggregated, pubKeys, [][32]byte{msg, msg, msg}))
+		assert.Equal(t, true, verifier.FastAggregateVerify(aggregated, pubKeys, msg))
+		assert.Equal(t, false, verifier.FastAggregateVerify(aggregated, pubKeys[:2], msg))
+		assert.Equal(t, false, verifier.FastAggregateVerify(nil, pubKeys, msg))
+		assert.Equal(t, true, verifier.VerifyCompressed(rawSigs[0], pubKeys[0].Marshal(), msg[:]))
+		verified, err := verifier.VerifyCompressedBatch(rawSigs[:1], [][]byte{pubKeys[0].Marshal()}, [][]byte{msg[:]})
+		require.NoError(t, err)
+		assert.Equal(t, true, verified)
+		verified, err = verifier.VerifyMultipleSignatures(rawSigs, [][32]byte{msg, msg, msg}, pubKeys)
+		require.NoError(t, err)
+		assert.Equal(t, true, verified)
+		verified, err = verifier.VerifyMultipleSignatures(rawSigs, [][32]byte{msg, msg, {'b', 'a', 'd'}}, pubKeys)
+		require.NoError(t, err)
+		assert.Equal(t, false, verified)
+		reset()
+	}
+}
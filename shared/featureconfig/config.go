@@ -39,6 +39,7 @@ type Flags struct {
 	WriteSSZStateTransitions           bool // WriteSSZStateTransitions to tmp directory.
 	SkipBLSVerify                      bool // Skips BLS verification across the runtime.
 	EnableBlst                         bool // Enables new BLS library from supranational.
+	RejectInfiniteSignatures           bool // RejectInfiniteSignatures rejects the BLS signature at infinity when deserializing signatures.
 	PruneEpochBoundaryStates           bool // PruneEpochBoundaryStates prunes the epoch boundary state before last finalized check point.
 	EnableSnappyDBCompression          bool // EnableSnappyDBCompression in the database.
 	SlasherProtection                  bool // SlasherProtection protects validator fron sending over a slashable offense over the network using external slasher.
@@ -65,6 +66,9 @@ type Flags struct {
 
 	KafkaBootstrapServers          string // KafkaBootstrapServers to find kafka servers to stream blocks, attestations, etc.
 	AttestationAggregationStrategy string // AttestationAggregationStrategy defines aggregation strategy to be used when aggregating.
+	BLSExtraEntropyFile            string // BLSExtraEntropyFile is an additional entropy source mixed into batch signature verification.
+	BLSPublicKeyCacheSize          int    // BLSPublicKeyCacheSize is the number of decompressed BLS public keys to cache.
+	BLSVerificationTraceFile       string // BLSVerificationTraceFile is where the inputs and results of BLS signature verifications are traced for debugging.
 }
 
 var featureConfig *Flags
@@ -167,6 +171,18 @@ func ConfigureBeaconChain(ctx *cli.Context) {
 		log.Warn("Disabling new BLS library blst")
 		cfg.EnableBlst = false
 	}
+	if ctx.IsSet(blsExtraEntropyFile.Name) {
+		cfg.BLSExtraEntropyFile = ctx.String(blsExtraEntropyFile.Name)
+	}
+	cfg.BLSPublicKeyCacheSize = ctx.Int(blsPublicKeyCacheSize.Name)
+	if ctx.Bool(rejectInfiniteSignatures.Name) {
+		log.Warn("Rejecting infinite BLS signatures on deserialization")
+		cfg.RejectInfiniteSignatures = true
+	}
+	if ctx.IsSet(blsVerificationTraceFile.Name) {
+		log.Warn("Tracing BLS signature verifications, which is slow and only meant for debugging")
+		cfg.BLSVerificationTraceFile = ctx.String(blsVerificationTraceFile.Name)
+	}
 	cfg.EnablePruningDepositProofs = true
 	if ctx.Bool(disablePruningDepositProofs.Name) {
 		log.Warn("Disabling pruning deposit proofs")
@@ -55,6 +55,26 @@ var (
 		Name:  "disable-blst",
 		Usage: "Disables the new BLS library, blst, from Supranational",
 	}
+	blsExtraEntropyFile = &cli.StringFlag{
+		Name: "bls-extra-entropy-file",
+		Usage: "Path to an additional entropy source, such as a hardware RNG device, which is mixed into " +
+			"the random coefficients used for batch BLS signature verification.",
+	}
+	blsPublicKeyCacheSize = &cli.IntFlag{
+		Name:  "bls-pubkey-cache-size",
+		Usage: "The number of decompressed BLS public keys to keep in memory for signature verification.",
+		Value: 100000,
+	}
+	rejectInfiniteSignatures = &cli.BoolFlag{
+		Name: "reject-infinite-signatures",
+		Usage: "Rejects the BLS signature at infinity when deserializing signatures, rather than only " +
+			"failing its verification.",
+	}
+	blsVerificationTraceFile = &cli.StringFlag{
+		Name: "bls-verification-trace-file",
+		Usage: "(Debug) Path to a file which the inputs and result of every BLS signature verification are " +
+			"written to as JSON lines, to compare the client against the consensus spec tests.",
+	}
 	disableEth1DataMajorityVote = &cli.BoolFlag{
 		Name:  "disable-eth1-data-majority-vote",
 		Usage: "Disables the Voting With The Majority algorithm when voting for eth1data.",
@@ -130,6 +150,10 @@ var BeaconChainFlags = append(deprecatedFlags, []cli.Flag{
 	PyrmontTestnet,
 	Mainnet,
 	disableBlst,
+	blsExtraEntropyFile,
+	blsPublicKeyCacheSize,
+	blsVerificationTraceFile,
+	rejectInfiniteSignatures,
 	disableEth1DataMajorityVote,
 	enablePeerScorer,
 	enableLargerGossipHistory,
@@ -92,6 +92,7 @@ type BeaconChainConfig struct {
 	BLSSecretKeyLength        int           // BLSSecretKeyLength defines the expected length of BLS secret keys in bytes.
 	BLSPubkeyLength           int           // BLSPubkeyLength defines the expected length of BLS public keys in bytes.
 	BLSSignatureLength        int           // BLSSignatureLength defines the expected length of BLS signatures in bytes.
+	BLSDomainSeparationTag    string        // BLSDomainSeparationTag defines the domain separation tag BLS signatures are hashed to the curve with.
 	DefaultBufferSize         int           // DefaultBufferSize for channels across the Prysm repository.
 	ValidatorPrivkeyFileName  string        // ValidatorPrivKeyFileName specifies the string name of a validator private key file.
 	WithdrawalPrivkeyFileName string        // WithdrawalPrivKeyFileName specifies the string name of a withdrawal private key file.
@@ -113,6 +114,9 @@ type BeaconChainConfig struct {
 	NextForkEpoch       uint64            `yaml:"NEXT_FORK_EPOCH"`      // NextForkEpoch is used to track the epoch of the next fork, if any.
 	ForkVersionSchedule map[uint64][]byte // Schedule of fork versions by epoch number.
 
+	// Network values.
+	GenesisValidatorsRoot [32]byte `yaml:"GENESIS_VALIDATORS_ROOT"` // GenesisValidatorsRoot of the network, checked against the beacon node by the validator client. A zero root is not checked.
+
 	// Weak subjectivity values.
 	SafetyDecay uint64 // SafetyDecay is defined as the loss in the 1/3 consensus safety margin of the casper FFG mechanism.
 }
@@ -26,6 +26,8 @@ func LoadChainConfigFile(chainConfigFileName string) {
 	}
 	yamlFile = []byte(strings.Join(lines, "\n"))
 	conf := MainnetConfig()
+	// A custom chain is not mainnet, so its genesis validators root is only checked if set.
+	conf.GenesisValidatorsRoot = [32]byte{}
 	if err := yaml.Unmarshal(yamlFile, conf); err != nil {
 		log.WithError(err).Fatal("Failed to parse chain config yaml file.")
 	}
@@ -142,6 +142,7 @@ var mainnetBeaconConfig = &BeaconChainConfig{
 	BLSSecretKeyLength:        32,
 	BLSPubkeyLength:           48,
 	BLSSignatureLength:        96,
+	BLSDomainSeparationTag:    "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
 	DefaultBufferSize:         10000,
 	WithdrawalPrivkeyFileName: "/shardwithdrawalkey",
 	ValidatorPrivkeyFileName:  "/validatorprivatekey",
@@ -167,4 +168,10 @@ var mainnetBeaconConfig = &BeaconChainConfig{
 	ForkVersionSchedule: map[uint64][]byte{
 		// Any further forks must be specified here by their epoch number.
 	},
+
+	// Network values.
+	GenesisValidatorsRoot: [32]byte{
+		0x4b, 0x36, 0x3d, 0xb9, 0x4e, 0x28, 0x61, 0x20, 0xd7, 0x6e, 0xb9, 0x05, 0x34, 0x0f, 0xdd, 0x4e,
+		0x54, 0xbf, 0xe9, 0xf0, 0x6b, 0xf3, 0x3f, 0xf6, 0xcf, 0x5a, 0xd2, 0x7f, 0x51, 0x1b, 0xfe, 0x95,
+	},
 }
@@ -29,6 +29,7 @@ func PyrmontConfig() *BeaconChainConfig {
 	cfg.GenesisDelay = 432000
 	cfg.NetworkName = "pyrmont"
 	cfg.GenesisForkVersion = []byte{0x00, 0x00, 0x20, 0x09}
+	cfg.GenesisValidatorsRoot = [32]byte{}
 	cfg.SecondsPerETH1Block = 14
 	return cfg
 }
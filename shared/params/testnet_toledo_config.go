@@ -28,6 +28,7 @@ func ToledoConfig() *BeaconChainConfig {
 	cfg.MinGenesisTime = 1605009600
 	cfg.GenesisDelay = 86400
 	cfg.GenesisForkVersion = []byte{0x00, 0x70, 0x1E, 0xD0}
+	cfg.GenesisValidatorsRoot = [32]byte{}
 	cfg.NetworkName = "toledo"
 	cfg.SecondsPerETH1Block = 14
 	return cfg
@@ -468,7 +468,7 @@ func GenerateAttestations(bState *stateTrie.BeaconState, privs []bls.SecretKey,
 				sigs = append(sigs, privs[committee[b]].Sign(dataRoot[:]))
 			}
 
-			// bls.AggregateSignatures will return nil if sigs is 0.
+			// There is no aggregate of zero signatures.
 			if len(sigs) == 0 {
 				continue
 			}
@@ -476,7 +476,7 @@ func GenerateAttestations(bState *stateTrie.BeaconState, privs []bls.SecretKey,
 			att := &ethpb.Attestation{
 				Data:            attData,
 				AggregationBits: aggregationBits,
-				Signature:       bls.AggregateSignatures(sigs).Marshal(),
+				Signature:       bls.MustAggregateSignatures(sigs).Marshal(),
 			}
 			attestations = append(attestations, att)
 		}
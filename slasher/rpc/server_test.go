@@ -158,7 +158,7 @@ func TestServer_IsSlashableAttestationNoUpdate(t *testing.T) {
 		validatorSig := keys[idx].Sign(root[:])
 		sig = append(sig, validatorSig)
 	}
-	aggSig := bls.AggregateSignatures(sig)
+	aggSig := bls.MustAggregateSignatures(sig)
 	marshalledSig := aggSig.Marshal()
 
 	savedAttestation.Signature = marshalledSig
@@ -1,7 +1,6 @@
 package accounts
 
 import (
-	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -26,17 +25,9 @@ func filterPublicKeysFromUserInput(
 			)
 		}
 		for _, str := range pubKeyStrings {
-			pkString := str
-			if strings.Contains(pkString, "0x") {
-				pkString = pkString[2:]
-			}
-			pubKeyBytes, err := hex.DecodeString(pkString)
-			if err != nil {
-				return nil, errors.Wrapf(err, "could not decode string %s as hex", pkString)
-			}
-			blsPublicKey, err := bls.PublicKeyFromBytes(pubKeyBytes)
+			blsPublicKey, err := bls.PublicKeyFromHex(str)
 			if err != nil {
-				return nil, errors.Wrapf(err, "%#x is not a valid BLS public key", pubKeyBytes)
+				return nil, errors.Wrapf(err, "%s is not a valid BLS public key", str)
 			}
 			filteredPubKeys = append(filteredPubKeys, blsPublicKey)
 		}
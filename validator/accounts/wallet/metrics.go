@@ -0,0 +1,23 @@
+package wallet
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	keymanagerLoadDuration = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "validator_keymanager_load_seconds",
+			Help: "Time taken to initialize the keymanager and load its keys, by keymanager kind.",
+		},
+		[]string{"kind"},
+	)
+	keymanagerLoadedKeys = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "validator_keymanager_loaded_keys",
+			Help: "Number of validating keys loaded at keymanager initialization, by keymanager kind.",
+		},
+		[]string{"kind"},
+	)
+)
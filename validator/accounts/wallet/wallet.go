@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared/fileutil"
@@ -256,6 +257,7 @@ func (w *Wallet) Password() string {
 // InitializeKeymanager reads a keymanager config from disk at the wallet path,
 // unmarshals it based on the wallet's keymanager kind, and returns its value.
 func (w *Wallet) InitializeKeymanager(ctx context.Context) (keymanager.IKeymanager, error) {
+	start := time.Now()
 	var km keymanager.IKeymanager
 	var err error
 	switch w.KeymanagerKind() {
@@ -292,9 +294,34 @@ func (w *Wallet) InitializeKeymanager(ctx context.Context) (keymanager.IKeymanag
 	default:
 		return nil, fmt.Errorf("keymanager kind not supported: %s", w.keymanagerKind)
 	}
+	w.recordKeymanagerLoad(ctx, km, start)
 	return km, nil
 }
 
+// Reports how long loading the keymanager's keys took, so slow validator
+// startups can be correlated with the number of keys in the wallet.
+func (w *Wallet) recordKeymanagerLoad(ctx context.Context, km keymanager.IKeymanager, start time.Time) {
+	kind := w.keymanagerKind.String()
+	fields := logrus.Fields{
+		"kind": kind,
+	}
+	// Keys of a remote keymanager live in the remote signer, so
+	// there is no local key loading to count for it.
+	if w.keymanagerKind != keymanager.Remote {
+		pubKeys, err := km.FetchValidatingPublicKeys(ctx)
+		if err != nil {
+			log.WithError(err).Debug("Could not count loaded validating keys")
+		} else {
+			keymanagerLoadedKeys.WithLabelValues(kind).Set(float64(len(pubKeys)))
+			fields["numKeys"] = len(pubKeys)
+		}
+	}
+	duration := time.Since(start)
+	keymanagerLoadDuration.WithLabelValues(kind).Set(duration.Seconds())
+	fields["duration"] = duration
+	log.WithFields(fields).Debug("Loaded keymanager")
+}
+
 // WriteFileAtPath within the wallet directory given the desired path, filename, and raw data.
 func (w *Wallet) WriteFileAtPath(_ context.Context, filePath, fileName string, data []byte) error {
 	accountPath := filepath.Join(w.accountsPath, filePath)
@@ -1,10 +1,13 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	ptypes "github.com/gogo/protobuf/types"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
@@ -17,6 +20,8 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+var errNilSignature = errors.New("keymanager returned a nil signature")
+
 // SubmitAggregateAndProof submits the validator's signed slot signature to the beacon node
 // via gRPC. Beacon node will verify the slot signature and determine if the validator is also
 // an aggregator. If yes, then beacon node will broadcast aggregated signature and
@@ -31,6 +36,15 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 	duty, err := v.duty(pubKey)
 	if err != nil {
 		log.Errorf("Could not fetch validator assignment: %v", err)
+		v.recordMissedDuty(dutyAggregation, pubKey, slot, "could not fetch validator assignment", err)
+		if v.emitAccountMetrics {
+			ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
+	}
+	if err := v.checkClockSkewSafe(); err != nil {
+		log.Errorf("Local clock is dangerously skewed, not signing: %v", err)
+		v.recordMissedDuty(dutyAggregation, pubKey, slot, "local clock is dangerously skewed", err)
 		if v.emitAccountMetrics {
 			ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -50,6 +64,7 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 	slotSig, err := v.signSlot(ctx, pubKey, slot)
 	if err != nil {
 		log.Errorf("Could not sign slot: %v", err)
+		v.recordMissedDuty(dutyAggregation, pubKey, slot, "could not sign slot", err)
 		if v.emitAccountMetrics {
 			ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -61,29 +76,52 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 	// https://github.com/ethereum/eth2.0-specs/blob/v0.9.3/specs/validator/0_beacon-chain-validator.md#broadcast-aggregate
 	v.waitToSlotTwoThirds(ctx, slot)
 
-	res, err := v.validatorClient.SubmitAggregateSelectionProof(ctx, &ethpb.AggregateSelectionRequest{
+	req := &ethpb.AggregateSelectionRequest{
 		Slot:           slot,
 		CommitteeIndex: duty.CommitteeIndex,
 		PublicKey:      pubKey[:],
 		SlotSignature:  slotSig,
-	})
+	}
+	res, err := v.validatorClient.SubmitAggregateSelectionProof(ctx, req)
 	if err != nil {
-		status, ok := status.FromError(err)
-		if ok && status.Code() == codes.NotFound {
-			log.WithField("slot", slot).WithError(err).Warn("No attestations to aggregate")
-		} else {
-			log.WithField("slot", slot).WithError(err).Error("Could not submit slot signature to beacon node")
+		v.logAggregateSelectionErr(slot, pubKey, err)
+		return
+	}
+
+	// The aggregate may have been built on top of a head which has since been
+	// re-orged out. Rather than signing over stale data, re-request the aggregate
+	// once before giving up.
+	stale, err := v.isAggregateStale(ctx, res.AggregateAndProof)
+	if err != nil {
+		log.WithField("slot", slot).WithError(err).Error("Could not check aggregate against chain head")
+		v.recordMissedDuty(dutyAggregation, pubKey, slot, "could not check aggregate against chain head", err)
+		if v.emitAccountMetrics {
+			ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
+	}
+	if stale {
+		log.WithField("slot", slot).Warn("Aggregate references a stale head, requesting a fresh aggregate")
+		res, err = v.validatorClient.SubmitAggregateSelectionProof(ctx, req)
+		if err != nil {
+			v.logAggregateSelectionErr(slot, pubKey, err)
+			return
+		}
+		stale, err = v.isAggregateStale(ctx, res.AggregateAndProof)
+		if err != nil || stale {
+			log.WithField("slot", slot).WithError(err).Error("Could not obtain an aggregate for the current head")
+			v.recordMissedDuty(dutyAggregation, pubKey, slot, "could not obtain an aggregate for the current head", err)
 			if v.emitAccountMetrics {
 				ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
 			}
+			return
 		}
-
-		return
 	}
 
 	sig, err := v.aggregateAndProofSig(ctx, pubKey, res.AggregateAndProof)
 	if err != nil {
 		log.Errorf("Could not sign aggregate and proof: %v", err)
+		v.recordMissedDuty(dutyAggregation, pubKey, slot, "could not sign aggregate and proof", err)
 		return
 	}
 	_, err = v.validatorClient.SubmitSignedAggregateSelectionProof(ctx, &ethpb.SignedAggregateSubmitRequest{
@@ -94,6 +132,7 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 	})
 	if err != nil {
 		log.Errorf("Could not submit signed aggregate and proof to beacon node: %v", err)
+		v.recordMissedDuty(dutyAggregation, pubKey, slot, "could not submit signed aggregate and proof to beacon node", err)
 		if v.emitAccountMetrics {
 			ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -113,6 +152,78 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 
 }
 
+// logAggregateSelectionErr logs a failed aggregate selection request. Not finding
+// any attestations to aggregate is expected and is not counted as a failure.
+func (v *validator) logAggregateSelectionErr(slot uint64, pubKey [48]byte, err error) {
+	status, ok := status.FromError(err)
+	if ok && status.Code() == codes.NotFound {
+		log.WithField("slot", slot).WithError(err).Warn("No attestations to aggregate")
+		return
+	}
+	log.WithField("slot", slot).WithError(err).Error("Could not submit slot signature to beacon node")
+	v.recordMissedDuty(dutyAggregation, pubKey, slot, "could not submit slot signature to beacon node", err)
+	if v.emitAccountMetrics {
+		ValidatorAggFailVec.WithLabelValues(fmt.Sprintf("%#x", pubKey[:])).Inc()
+	}
+}
+
+// isAggregateStale returns true if the aggregate's beacon block root is no longer in the
+// beacon node's canonical chain, such as after a re-org. The head may have moved past the
+// aggregate's block root, as when the block of its slot arrived after the committee
+// attested, so rather than comparing them the head is walked back to the slot of that
+// block root. Heads too far ahead of it to walk back cheaply are not considered stale.
+func (v *validator) isAggregateStale(ctx context.Context, agg *ethpb.AggregateAttestationAndProof) (bool, error) {
+	if agg == nil || agg.Aggregate == nil || agg.Aggregate.Data == nil {
+		return false, errors.New("nil aggregate data")
+	}
+	head, err := v.beaconClient.GetChainHead(ctx, &ptypes.Empty{})
+	if err != nil {
+		return false, err
+	}
+	root := agg.Aggregate.Data.BeaconBlockRoot
+	if bytes.Equal(head.HeadBlockRoot, root) {
+		return false, nil
+	}
+	blk, err := v.blockByRoot(ctx, root)
+	if err != nil {
+		return false, err
+	}
+	if blk == nil {
+		// The beacon node no longer knows the block, so it was pruned from a stale fork.
+		return true, nil
+	}
+	ancestor := head.HeadBlockRoot
+	for i := uint64(0); i <= params.BeaconConfig().SlotsPerEpoch; i++ {
+		ancestorBlk, err := v.blockByRoot(ctx, ancestor)
+		if err != nil {
+			return false, err
+		}
+		if ancestorBlk == nil {
+			return false, fmt.Errorf("beacon node does not know block %#x of its canonical chain", ancestor)
+		}
+		if ancestorBlk.Slot <= blk.Slot {
+			return !bytes.Equal(ancestor, root), nil
+		}
+		ancestor = ancestorBlk.ParentRoot
+	}
+	return false, nil
+}
+
+// blockByRoot returns the beacon block with the given root, or nil if the beacon node
+// does not know it.
+func (v *validator) blockByRoot(ctx context.Context, root []byte) (*ethpb.BeaconBlock, error) {
+	blocks, err := v.beaconClient.ListBlocks(ctx, &ethpb.ListBlocksRequest{
+		QueryFilter: &ethpb.ListBlocksRequest_Root{Root: root},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request block %#x: %w", root, err)
+	}
+	if len(blocks.BlockContainers) == 0 || blocks.BlockContainers[0].Block == nil {
+		return nil, nil
+	}
+	return blocks.BlockContainers[0].Block.Block, nil
+}
+
 // This implements selection logic outlined in:
 // https://github.com/ethereum/eth2.0-specs/blob/v0.9.3/specs/validator/0_beacon-chain-validator.md#aggregation-selection
 func (v *validator) signSlot(ctx context.Context, pubKey [48]byte, slot uint64) ([]byte, error) {
@@ -135,6 +246,9 @@ func (v *validator) signSlot(ctx context.Context, pubKey [48]byte, slot uint64)
 	if err != nil {
 		return nil, err
 	}
+	if err := v.verifySignedRoot(pubKey, root, sig); err != nil {
+		return nil, err
+	}
 
 	return sig.Marshal(), nil
 }
@@ -146,40 +260,73 @@ func (v *validator) waitToSlotTwoThirds(ctx context.Context, slot uint64) {
 	_, span := trace.StartSpan(ctx, "validator.waitToSlotTwoThirds")
 	defer span.End()
 
+	time.Sleep(timeutils.Until(slotTwoThirdsTime(v.genesisTime, slot)))
+}
+
+// Returns the time two thirds into the given slot, at which aggregates are submitted.
+func slotTwoThirdsTime(genesisTime, slot uint64) time.Time {
 	oneThird := slotutil.DivideSlotBy(3 /* one third of slot duration */)
 	twoThird := oneThird + oneThird
 	delay := twoThird
 
-	startTime := slotutil.SlotStartTime(v.genesisTime, slot)
-	finalTime := startTime.Add(delay)
-	time.Sleep(timeutils.Until(finalTime))
+	startTime := slotutil.SlotStartTime(genesisTime, slot)
+	return startTime.Add(delay)
 }
 
 // This returns the signature of validator signing over aggregate and
 // proof object.
 func (v *validator) aggregateAndProofSig(ctx context.Context, pubKey [48]byte, agg *ethpb.AggregateAttestationAndProof) ([]byte, error) {
-	d, err := v.domainData(ctx, helpers.SlotToEpoch(agg.Aggregate.Data.Slot), params.BeaconConfig().DomainAggregateAndProof[:])
+	d, err := v.signingDomain(ctx, helpers.SlotToEpoch(agg.Aggregate.Data.Slot), params.BeaconConfig().DomainAggregateAndProof)
 	if err != nil {
 		return nil, err
 	}
 	var sig bls.Signature
-	root, err := helpers.ComputeSigningRoot(agg, d.SignatureDomain)
+	root, err := helpers.ComputeSigningRoot(agg, d)
 	if err != nil {
 		return nil, err
 	}
 	sig, err = v.keyManager.Sign(ctx, &validatorpb.SignRequest{
 		PublicKey:       pubKey[:],
 		SigningRoot:     root[:],
-		SignatureDomain: d.SignatureDomain,
+		SignatureDomain: d,
 		Object:          &validatorpb.SignRequest_AggregateAttestationAndProof{AggregateAttestationAndProof: agg},
 	})
 	if err != nil {
 		return nil, err
 	}
+	// A buggy keymanager, such as a remote signer, may return neither a signature nor an error.
+	if sig == nil {
+		ValidatorNilSignatures.Inc()
+		return nil, errNilSignature
+	}
+	if err := v.verifyAggregateAndProofSig(pubKey, agg, sig, d); err != nil {
+		return nil, err
+	}
 
 	return sig.Marshal(), nil
 }
 
+// Checks the signature returned by the keymanager over an aggregate and proof, with the
+// same check the beacon node runs on aggregates received over gossip. As with
+// verifySignedRoot, only signatures from remote keymanagers are checked.
+func (v *validator) verifyAggregateAndProofSig(pubKey [48]byte, agg *ethpb.AggregateAttestationAndProof, sig bls.Signature, domain []byte) error {
+	if !v.verifyKeymanagerSignatures {
+		return nil
+	}
+	pk, err := v.signingPubKey(pubKey)
+	if err != nil {
+		return err
+	}
+	root, err := agg.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if !helpers.VerifyAggregateAndProofSignature(pk, root, sig, domain) {
+		return fmt.Errorf("signature from keymanager does not verify for aggregate and proof %#x", root)
+	}
+	return nil
+}
+
 func (v *validator) addIndicesToLog(duty *ethpb.DutiesResponse_Duty) error {
 	v.attLogsLock.Lock()
 	defer v.attLogsLock.Unlock()
@@ -6,9 +6,13 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
 	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
@@ -31,10 +35,12 @@ func TestSubmitAggregateAndProof_GetDutiesRequestFailure(t *testing.T) {
 }
 
 func TestSubmitAggregateAndProof_SignFails(t *testing.T) {
+	hook := logTest.NewGlobal()
 	validator, m, validatorKey, finish := setup(t)
 	defer finish()
 	pubKey := [48]byte{}
 	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+	validator.keyManager = &failingAggregateKeymanager{mockKeymanager: validator.keyManager.(*mockKeymanager)}
 	validator.duties = &ethpb.DutiesResponse{
 		Duties: []*ethpb.DutiesResponse_Duty{
 			{
@@ -46,36 +52,114 @@ func TestSubmitAggregateAndProof_SignFails(t *testing.T) {
 	m.validatorClient.EXPECT().DomainData(
 		gomock.Any(), // ctx
 		gomock.Any(), // epoch
-	).Return(&ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil /*err*/)
+	).Return(selectionProofDomain(t, validator), nil /*err*/)
 
 	m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
 		gomock.Any(), // ctx
 		gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
-	).Return(&ethpb.AggregateSelectionResponse{
-		AggregateAndProof: &ethpb.AggregateAttestationAndProof{
-			AggregatorIndex: 0,
-			Aggregate: &ethpb.Attestation{
-				Data: &ethpb.AttestationData{
-					BeaconBlockRoot: make([]byte, 32),
-					Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
-					Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
-				},
-				Signature:       make([]byte, 96),
-				AggregationBits: make([]byte, 1),
+	).DoAndReturn(aggregateSelectionResponse(make([]byte, 32)))
+
+	m.beaconClient.EXPECT().GetChainHead(
+		gomock.Any(), // ctx
+		gomock.Any(), // empty
+	).Return(&ethpb.ChainHead{HeadBlockRoot: make([]byte, 32)}, nil)
+
+	validator.SubmitAggregateAndProof(context.Background(), 0, pubKey)
+	require.LogsContain(t, hook, "Could not sign aggregate and proof")
+}
+
+func TestSubmitAggregateAndProof_Ok(t *testing.T) {
+	validator, m, validatorKey, finish := setup(t)
+	defer finish()
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+	validator.duties = &ethpb.DutiesResponse{
+		Duties: []*ethpb.DutiesResponse_Duty{
+			{
+				PublicKey: validatorKey.PublicKey().Marshal(),
 			},
-			SelectionProof: make([]byte, 96),
 		},
-	}, nil)
+	}
 
 	m.validatorClient.EXPECT().DomainData(
 		gomock.Any(), // ctx
 		gomock.Any(), // epoch
-	).Return(&ethpb.DomainResponse{SignatureDomain: nil}, errors.New("bad domain root"))
+	).Return(selectionProofDomain(t, validator), nil /*err*/)
+
+	m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
+		gomock.Any(), // ctx
+		gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
+	).DoAndReturn(aggregateSelectionResponse(make([]byte, 32)))
+
+	m.beaconClient.EXPECT().GetChainHead(
+		gomock.Any(), // ctx
+		gomock.Any(), // empty
+	).Return(&ethpb.ChainHead{HeadBlockRoot: make([]byte, 32)}, nil)
+
+	m.validatorClient.EXPECT().SubmitSignedAggregateSelectionProof(
+		gomock.Any(), // ctx
+		gomock.AssignableToTypeOf(&ethpb.SignedAggregateSubmitRequest{}),
+	).Return(&ethpb.SignedAggregateSubmitResponse{AttestationDataRoot: make([]byte, 32)}, nil)
 
 	validator.SubmitAggregateAndProof(context.Background(), 0, pubKey)
 }
 
-func TestSubmitAggregateAndProof_Ok(t *testing.T) {
+func TestSubmitAggregateAndProof_RefetchesStaleAggregate(t *testing.T) {
+	hook := logTest.NewGlobal()
+	validator, m, validatorKey, finish := setup(t)
+	defer finish()
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+	validator.duties = &ethpb.DutiesResponse{
+		Duties: []*ethpb.DutiesResponse_Duty{
+			{
+				PublicKey: validatorKey.PublicKey().Marshal(),
+			},
+		},
+	}
+
+	staleRoot := bytesutil.PadTo([]byte("stale"), 32)
+	headRoot := bytesutil.PadTo([]byte("head"), 32)
+	mockBlocksByRoot(m, map[[32]byte]*ethpb.BeaconBlock{
+		bytesutil.ToBytes32(staleRoot): {Slot: 1},
+		bytesutil.ToBytes32(headRoot):  {Slot: 1},
+	})
+
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), // epoch
+	).Return(selectionProofDomain(t, validator), nil /*err*/)
+
+	// The first aggregate was built before a re-org, the second one matches the new head.
+	gomock.InOrder(
+		m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
+			gomock.Any(), // ctx
+			gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
+		).DoAndReturn(aggregateSelectionResponse(staleRoot)),
+		m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
+			gomock.Any(), // ctx
+			gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
+		).DoAndReturn(aggregateSelectionResponse(headRoot)),
+	)
+	m.beaconClient.EXPECT().GetChainHead(
+		gomock.Any(), // ctx
+		gomock.Any(), // empty
+	).Times(2).Return(&ethpb.ChainHead{HeadSlot: 1, HeadBlockRoot: headRoot}, nil)
+
+	m.validatorClient.EXPECT().SubmitSignedAggregateSelectionProof(
+		gomock.Any(), // ctx
+		gomock.AssignableToTypeOf(&ethpb.SignedAggregateSubmitRequest{}),
+	).DoAndReturn(func(_ context.Context, req *ethpb.SignedAggregateSubmitRequest) (*ethpb.SignedAggregateSubmitResponse, error) {
+		assert.DeepEqual(t, headRoot, req.SignedAggregateAndProof.Message.Aggregate.Data.BeaconBlockRoot)
+		return &ethpb.SignedAggregateSubmitResponse{AttestationDataRoot: make([]byte, 32)}, nil
+	})
+
+	validator.SubmitAggregateAndProof(context.Background(), 0, pubKey)
+	require.LogsContain(t, hook, "Aggregate references a stale head")
+}
+
+func TestSubmitAggregateAndProof_StaleAfterRefetch(t *testing.T) {
+	hook := logTest.NewGlobal()
 	validator, m, validatorKey, finish := setup(t)
 	defer finish()
 	pubKey := [48]byte{}
@@ -96,33 +180,131 @@ func TestSubmitAggregateAndProof_Ok(t *testing.T) {
 	m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
 		gomock.Any(), // ctx
 		gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
-	).Return(&ethpb.AggregateSelectionResponse{
+	).Times(2).Return(&ethpb.AggregateSelectionResponse{
 		AggregateAndProof: &ethpb.AggregateAttestationAndProof{
-			AggregatorIndex: 0,
 			Aggregate: &ethpb.Attestation{
 				Data: &ethpb.AttestationData{
 					BeaconBlockRoot: make([]byte, 32),
-					Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
-					Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
 				},
-				Signature:       make([]byte, 96),
-				AggregationBits: make([]byte, 1),
 			},
-			SelectionProof: make([]byte, 96),
 		},
 	}, nil)
-
-	m.validatorClient.EXPECT().DomainData(
+	m.beaconClient.EXPECT().GetChainHead(
 		gomock.Any(), // ctx
-		gomock.Any(), // epoch
-	).Return(&ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil /*err*/)
+		gomock.Any(), // empty
+	).Times(2).Return(&ethpb.ChainHead{HeadBlockRoot: bytesutil.PadTo([]byte("head"), 32)}, nil)
+	mockBlocksByRoot(m, nil)
 
-	m.validatorClient.EXPECT().SubmitSignedAggregateSelectionProof(
+	validator.SubmitAggregateAndProof(context.Background(), 0, pubKey)
+	require.LogsContain(t, hook, "Could not obtain an aggregate for the current head")
+}
+
+func TestIsAggregateStale(t *testing.T) {
+	genesisRoot := bytesutil.PadTo([]byte("genesis"), 32)
+	parentRoot := bytesutil.PadTo([]byte("parent"), 32)
+	headRoot := bytesutil.PadTo([]byte("head"), 32)
+	forkRoot := bytesutil.PadTo([]byte("fork"), 32)
+	blocks := map[[32]byte]*ethpb.BeaconBlock{
+		bytesutil.ToBytes32(genesisRoot): {Slot: 0},
+		bytesutil.ToBytes32(parentRoot):  {Slot: 2, ParentRoot: genesisRoot},
+		bytesutil.ToBytes32(headRoot):    {Slot: 4, ParentRoot: parentRoot},
+		bytesutil.ToBytes32(forkRoot):    {Slot: 3, ParentRoot: parentRoot},
+	}
+	tests := []struct {
+		name      string
+		blockRoot []byte
+		stale     bool
+	}{
+		{name: "head", blockRoot: headRoot, stale: false},
+		{name: "ancestor of a head which arrived after attesting", blockRoot: parentRoot, stale: false},
+		{name: "older ancestor", blockRoot: genesisRoot, stale: false},
+		{name: "re-orged out", blockRoot: forkRoot, stale: true},
+		{name: "unknown block", blockRoot: bytesutil.PadTo([]byte("unknown"), 32), stale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, m, _, finish := setup(t)
+			defer finish()
+			m.beaconClient.EXPECT().GetChainHead(
+				gomock.Any(), // ctx
+				gomock.Any(), // empty
+			).Return(&ethpb.ChainHead{HeadSlot: 4, HeadBlockRoot: headRoot}, nil)
+			mockBlocksByRoot(m, blocks)
+
+			stale, err := validator.isAggregateStale(context.Background(), &ethpb.AggregateAttestationAndProof{
+				Aggregate: &ethpb.Attestation{
+					Data: &ethpb.AttestationData{Slot: 4, BeaconBlockRoot: tt.blockRoot},
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.stale, stale)
+		})
+	}
+}
+
+// Serves the given blocks by their roots from the mock beacon chain client.
+func mockBlocksByRoot(m *mocks, blocks map[[32]byte]*ethpb.BeaconBlock) {
+	m.beaconClient.EXPECT().ListBlocks(
 		gomock.Any(), // ctx
-		gomock.AssignableToTypeOf(&ethpb.SignedAggregateSubmitRequest{}),
-	).Return(&ethpb.SignedAggregateSubmitResponse{AttestationDataRoot: make([]byte, 32)}, nil)
+		gomock.AssignableToTypeOf(&ethpb.ListBlocksRequest{}),
+	).DoAndReturn(func(_ context.Context, req *ethpb.ListBlocksRequest) (*ethpb.ListBlocksResponse, error) {
+		root := bytesutil.ToBytes32(req.GetRoot())
+		blk, ok := blocks[root]
+		if !ok {
+			return &ethpb.ListBlocksResponse{}, nil
+		}
+		return &ethpb.ListBlocksResponse{
+			BlockContainers: []*ethpb.BeaconBlockContainer{
+				{Block: &ethpb.SignedBeaconBlock{Block: blk}, BlockRoot: root[:]},
+			},
+		}, nil
+	}).AnyTimes()
+}
 
-	validator.SubmitAggregateAndProof(context.Background(), 0, pubKey)
+// Sets the genesis validators root of the validator, so signing domains are computed
+// locally, and returns the selection proof domain of the first epoch.
+func selectionProofDomain(t *testing.T, v *validator) *ethpb.DomainResponse {
+	v.genesisValidatorsRoot = make([]byte, 32)
+	domain, err := epochDomain(0, params.BeaconConfig().DomainSelectionProof, v.genesisValidatorsRoot)
+	require.NoError(t, err)
+	return &ethpb.DomainResponse{SignatureDomain: domain}
+}
+
+// Returns a beacon node response to an aggregate selection request, which builds an
+// aggregate of the block root around the slot signature of the request.
+func aggregateSelectionResponse(
+	root []byte,
+) func(context.Context, *ethpb.AggregateSelectionRequest) (*ethpb.AggregateSelectionResponse, error) {
+	return func(_ context.Context, req *ethpb.AggregateSelectionRequest) (*ethpb.AggregateSelectionResponse, error) {
+		return &ethpb.AggregateSelectionResponse{
+			AggregateAndProof: &ethpb.AggregateAttestationAndProof{
+				AggregatorIndex: 0,
+				Aggregate: &ethpb.Attestation{
+					Data: &ethpb.AttestationData{
+						Slot:            req.Slot,
+						BeaconBlockRoot: root,
+						Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+						Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+					},
+					Signature:       make([]byte, 96),
+					AggregationBits: make([]byte, 1),
+				},
+				SelectionProof: req.SlotSignature,
+			},
+		}, nil
+	}
+}
+
+// Signs everything but aggregates and proofs.
+type failingAggregateKeymanager struct {
+	*mockKeymanager
+}
+
+func (m *failingAggregateKeymanager) Sign(ctx context.Context, req *validatorpb.SignRequest) (bls.Signature, error) {
+	if _, ok := req.Object.(*validatorpb.SignRequest_AggregateAttestationAndProof); ok {
+		return nil, errors.New("keymanager is locked")
+	}
+	return m.mockKeymanager.Sign(ctx, req)
 }
 
 func TestWaitForSlotTwoThird_WaitCorrectly(t *testing.T) {
@@ -163,8 +345,85 @@ func TestAggregateAndProofSignature_CanSignValidSignature(t *testing.T) {
 		},
 		SelectionProof: make([]byte, 96),
 	}
+	// The signature is checked like the beacon node checks aggregates received over gossip.
+	validator.verifyKeymanagerSignatures = true
 	sig, err := validator.aggregateAndProofSig(context.Background(), pubKey, agg)
 	require.NoError(t, err)
 	_, err = bls.SignatureFromBytes(sig)
 	require.NoError(t, err)
 }
+
+func TestVerifyAggregateAndProofSig(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	otherKey, err := bls.RandKey()
+	require.NoError(t, err)
+	var pubKey [48]byte
+	copy(pubKey[:], secretKey.PublicKey().Marshal())
+	agg := &ethpb.AggregateAttestationAndProof{
+		Aggregate: &ethpb.Attestation{
+			AggregationBits: bitfield.NewBitlist(1), Data: &ethpb.AttestationData{
+				BeaconBlockRoot: make([]byte, 32),
+				Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			},
+			Signature: make([]byte, 96),
+		},
+		SelectionProof: make([]byte, 96),
+	}
+	domain := make([]byte, 32)
+	root, err := agg.HashTreeRoot()
+	require.NoError(t, err)
+	sig, err := helpers.SignWithDomain(secretKey, root, domain)
+	require.NoError(t, err)
+	otherSig, err := helpers.SignWithDomain(otherKey, root, domain)
+	require.NoError(t, err)
+
+	// Signatures from local keymanagers are not checked.
+	v := &validator{}
+	require.NoError(t, v.verifyAggregateAndProofSig(pubKey, agg, otherSig, domain))
+
+	v.verifyKeymanagerSignatures = true
+	require.NoError(t, v.verifyAggregateAndProofSig(pubKey, agg, sig, domain))
+	assert.ErrorContains(t, "does not verify", v.verifyAggregateAndProofSig(pubKey, agg, otherSig, domain))
+	otherDomain := bytesutil.PadTo([]byte{1}, 32)
+	assert.ErrorContains(t, "does not verify", v.verifyAggregateAndProofSig(pubKey, agg, sig, otherDomain))
+}
+
+type nilSignatureKeymanager struct {
+	mockKeymanager
+}
+
+func (m *nilSignatureKeymanager) Sign(_ context.Context, _ *validatorpb.SignRequest) (bls.Signature, error) {
+	return nil, nil
+}
+
+func TestAggregateAndProofSignature_NilSignature(t *testing.T) {
+	validator, m, validatorKey, finish := setup(t)
+	defer finish()
+	validator.keyManager = &nilSignatureKeymanager{}
+
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		&ethpb.DomainRequest{Epoch: 0, Domain: params.BeaconConfig().DomainAggregateAndProof[:]},
+	).Return(&ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil /*err*/)
+
+	agg := &ethpb.AggregateAttestationAndProof{
+		AggregatorIndex: 0,
+		Aggregate: &ethpb.Attestation{
+			AggregationBits: bitfield.NewBitlist(1), Data: &ethpb.AttestationData{
+				BeaconBlockRoot: make([]byte, 32),
+				Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			},
+			Signature: make([]byte, 96),
+		},
+		SelectionProof: make([]byte, 96),
+	}
+	nilSignatures := testutil.ToFloat64(ValidatorNilSignatures)
+	_, err := validator.aggregateAndProofSig(context.Background(), pubKey, agg)
+	assert.Equal(t, errNilSignature, err)
+	assert.Equal(t, nilSignatures+1, testutil.ToFloat64(ValidatorNilSignatures))
+}
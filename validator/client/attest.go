@@ -34,6 +34,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	duty, err := v.duty(pubKey)
 	if err != nil {
 		log.WithError(err).Error("Could not fetch validator assignment")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "could not fetch validator assignment", err)
 		if v.emitAccountMetrics {
 			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -43,6 +44,14 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 		log.Debug("Empty committee for validator duty, not attesting")
 		return
 	}
+	if err := v.checkClockSkewSafe(); err != nil {
+		log.WithError(err).Error("Local clock is dangerously skewed, not signing")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "local clock is dangerously skewed", err)
+		if v.emitAccountMetrics {
+			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
+	}
 
 	v.waitToSlotOneThird(ctx, slot)
 
@@ -53,6 +62,15 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	data, err := v.validatorClient.GetAttestationData(ctx, req)
 	if err != nil {
 		log.WithError(err).Error("Could not request attestation to sign at slot")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "could not request attestation to sign", err)
+		if v.emitAccountMetrics {
+			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
+	}
+	if err := v.verifyAttestationData(ctx, req, data); err != nil {
+		log.WithError(err).Error("Attestation data could not be verified against the verification beacon node, not signing")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "could not verify attestation data against verification beacon node", err)
 		if v.emitAccountMetrics {
 			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -65,6 +83,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	}
 	if err := v.preAttSignValidations(ctx, indexedAtt, pubKey); err != nil {
 		log.WithError(err).Error("Failed attestation slashing protection check")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "failed attestation slashing protection check", err)
 		log.WithFields(
 			attestationLogFields(pubKey, indexedAtt),
 		).Debug("Attempted slashable attestation details")
@@ -74,6 +93,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	sig, signingRoot, err := v.signAtt(ctx, pubKey, data)
 	if err != nil {
 		log.WithError(err).Error("Could not sign attestation")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "could not sign attestation", err)
 		if v.emitAccountMetrics {
 			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -91,6 +111,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	}
 	if !found {
 		log.Errorf("Validator ID %d not found in committee of %v", duty.ValidatorIndex, duty.Committee)
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "validator not found in committee", nil)
 		if v.emitAccountMetrics {
 			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -108,6 +129,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	indexedAtt.Signature = sig
 	if err := v.postAttSignUpdate(ctx, indexedAtt, pubKey, signingRoot); err != nil {
 		log.WithError(err).Error("Failed attestation slashing protection check")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "failed attestation slashing protection check", err)
 		log.WithFields(
 			attestationLogFields(pubKey, indexedAtt),
 		).Debug("Attempted slashable attestation details")
@@ -119,6 +141,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	attResp, err := v.validatorClient.ProposeAttestation(ctx, attestation)
 	if err != nil {
 		log.WithError(err).Error("Could not submit attestation to beacon node")
+		v.recordMissedDuty(dutyAttestation, pubKey, slot, "could not submit attestation to beacon node", err)
 		if v.emitAccountMetrics {
 			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -180,6 +203,9 @@ func (v *validator) signAtt(ctx context.Context, pubKey [48]byte, data *ethpb.At
 	if err != nil {
 		return nil, [32]byte{}, err
 	}
+	if err := v.verifySignedRoot(pubKey, root, sig); err != nil {
+		return nil, [32]byte{}, err
+	}
 
 	return sig.Marshal(), root, nil
 }
@@ -221,10 +247,14 @@ func (v *validator) waitToSlotOneThird(ctx context.Context, slot uint64) {
 	_, span := trace.StartSpan(ctx, "validator.waitToSlotOneThird")
 	defer span.End()
 
+	time.Sleep(timeutils.Until(slotOneThirdTime(v.genesisTime, slot)))
+}
+
+// Returns the time one third into the given slot, at which attestations are submitted.
+func slotOneThirdTime(genesisTime, slot uint64) time.Time {
 	delay := slotutil.DivideSlotBy(3 /* a third of the slot duration */)
-	startTime := slotutil.SlotStartTime(v.genesisTime, slot)
-	finalTime := startTime.Add(delay)
-	time.Sleep(timeutils.Until(finalTime))
+	startTime := slotutil.SlotStartTime(genesisTime, slot)
+	return startTime.Add(delay)
 }
 
 func attestationLogFields(pubKey [48]byte, indexedAtt *ethpb.IndexedAttestation) logrus.Fields {
@@ -56,6 +56,12 @@ func (v *validator) preAttSignValidations(ctx context.Context, indexedAtt *ethpb
 		}
 		return errors.New(failedAttLocalProtectionErr)
 	}
+	if v.slashingWarningMargin > 0 {
+		// The warning never blocks signing, so failing to check it is only logged.
+		if err := v.warnIfNearSurroundVote(ctx, attesterHistory, indexedAtt.Data, pubKey); err != nil {
+			log.WithError(err).Debug("Could not check attestation against the slashing warning margin")
+		}
+	}
 	if featureconfig.Get().SlasherProtection && v.protector != nil {
 		if !v.protector.CheckAttestationSafety(ctx, indexedAtt) {
 			if v.emitAccountMetrics {
@@ -231,6 +237,114 @@ func isSurroundVote(
 	return false, nil
 }
 
+// warnIfNearSurroundVote logs a warning and increments a metric when an attestation which is
+// not slashable comes within the configured margin of epochs of surrounding, or being
+// surrounded by, an attestation in the validator's signing history.
+func (v *validator) warnIfNearSurroundVote(
+	ctx context.Context,
+	history kv.EncHistoryData,
+	data *ethpb.AttestationData,
+	pubKey [48]byte,
+) error {
+	closest, err := nearSurroundVote(ctx, history, data.Source.Epoch, data.Target.Epoch, v.slashingWarningMargin)
+	if err != nil {
+		return err
+	}
+	if closest == nil {
+		return nil
+	}
+	fmtKey := fmt.Sprintf("%#x", pubKey[:])
+	log.WithFields(logrus.Fields{
+		"publicKey":                     fmtKey,
+		"sourceEpoch":                   data.Source.Epoch,
+		"targetEpoch":                   data.Target.Epoch,
+		"previouslyAttestedSourceEpoch": closest.prevSource,
+		"previouslyAttestedTargetEpoch": closest.prevTarget,
+		"epochsFromSurroundVote":        closest.distance,
+	}).Warn("Attestation is close to a surround vote with respect to the local slashing protection history")
+	if v.emitAccountMetrics {
+		ValidatorAttestNearSlashableVec.WithLabelValues(fmtKey).Inc()
+	}
+	return nil
+}
+
+// surroundMargin describes how close a new attestation is to a surround
+// vote with a previous attestation from the signing history.
+type surroundMargin struct {
+	prevSource uint64
+	prevTarget uint64
+	distance   uint64
+}
+
+// nearSurroundVote finds the previous attestation in the history which the new attestation
+// comes closest to surrounding or being surrounded by. It returns nil if no previous attestation
+// is within margin epochs of a surround vote.
+func nearSurroundVote(
+	ctx context.Context,
+	history kv.EncHistoryData,
+	sourceEpoch,
+	targetEpoch,
+	margin uint64,
+) (*surroundMargin, error) {
+	if history == nil {
+		return nil, nil
+	}
+	latestEpochWritten, err := history.GetLatestEpochWritten(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get latest epoch written from encapsulated data")
+	}
+	// Previous attestations with a target before this epoch are
+	// further than the margin from a surround vote in either direction.
+	start := uint64(0)
+	if sourceEpoch+1 > margin {
+		start = sourceEpoch + 1 - margin
+	}
+	var closest *surroundMargin
+	for i := start; i <= latestEpochWritten; i++ {
+		// Votes for the same target are double votes, which are checked separately.
+		if i == targetEpoch {
+			continue
+		}
+		historicalAtt, err := checkHistoryAtTargetEpoch(ctx, history, latestEpochWritten, i)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not check historical attestation at target epoch: %d", i)
+		}
+		if historicalAtt.IsEmpty() {
+			continue
+		}
+		distance := surroundDistance(historicalAtt.Source, i, sourceEpoch, targetEpoch)
+		if distance <= margin && (closest == nil || distance < closest.distance) {
+			closest = &surroundMargin{
+				prevSource: historicalAtt.Source,
+				prevTarget: i,
+				distance:   distance,
+			}
+		}
+	}
+	return closest, nil
+}
+
+// surroundDistance returns the total number of epochs the source and target of a new
+// attestation would need to move by to surround, or be surrounded by, a previous one.
+func surroundDistance(prevSource, prevTarget, newSource, newTarget uint64) uint64 {
+	// Surrounding needs newSource < prevSource and prevTarget < newTarget.
+	surrounding := epochsAbove(newSource+1, prevSource) + epochsAbove(prevTarget+1, newTarget)
+	// Being surrounded needs prevSource < newSource and newTarget < prevTarget.
+	surrounded := epochsAbove(prevSource+1, newSource) + epochsAbove(newTarget+1, prevTarget)
+	if surrounding < surrounded {
+		return surrounding
+	}
+	return surrounded
+}
+
+// Returns how many epochs a is above b, or 0 if it is not above b.
+func epochsAbove(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return 0
+}
+
 func surroundedByPrevAttestation(prevSource, prevTarget, newSource, newTarget uint64) bool {
 	return prevSource < newSource && newTarget < prevTarget
 }
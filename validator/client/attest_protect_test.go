@@ -15,6 +15,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 	"github.com/prysmaticlabs/prysm/validator/db/kv"
 	mockSlasher "github.com/prysmaticlabs/prysm/validator/testing"
+	logTest "github.com/sirupsen/logrus/hooks/test"
 )
 
 func TestPreSignatureValidation(t *testing.T) {
@@ -767,3 +768,90 @@ func Test_differenceOutsideWeakSubjectivityBounds(t *testing.T) {
 		})
 	}
 }
+
+func TestWarnIfNearSurroundVote(t *testing.T) {
+	ctx := context.Background()
+	hook := logTest.NewGlobal()
+	v := &validator{slashingWarningMargin: 1}
+	pubKey := [48]byte{1}
+	// Mark an attestation spanning epochs 2 to 3.
+	history, err := kv.MarkAllAsAttestedSinceLatestWrittenEpoch(ctx, kv.NewAttestationHistoryArray(0), 3, &kv.HistoryData{
+		Source:      2,
+		SigningRoot: bytesutil.PadTo([]byte{1}, 32),
+	})
+	require.NoError(t, err)
+	newAttData := func(source, target uint64) *ethpb.AttestationData {
+		return &ethpb.AttestationData{
+			Source: &ethpb.Checkpoint{Epoch: source},
+			Target: &ethpb.Checkpoint{Epoch: target},
+		}
+	}
+
+	// A vote advancing both source and target is clearly safe.
+	require.NoError(t, v.warnIfNearSurroundVote(ctx, history, newAttData(3, 4), pubKey))
+	require.LogsDoNotContain(t, hook, "close to a surround vote")
+
+	// A vote keeping the same source while advancing the target would
+	// surround the previous attestation if its source went back one epoch.
+	require.NoError(t, v.warnIfNearSurroundVote(ctx, history, newAttData(2, 4), pubKey))
+	require.LogsContain(t, hook, "close to a surround vote")
+	require.LogsContain(t, hook, "epochsFromSurroundVote=1")
+}
+
+func Test_surroundDistance(t *testing.T) {
+	tests := []struct {
+		name       string
+		prevSource uint64
+		prevTarget uint64
+		newSource  uint64
+		newTarget  uint64
+		want       uint64
+	}{
+		{
+			name:       "surrounding vote",
+			prevSource: 3,
+			prevTarget: 5,
+			newSource:  2,
+			newTarget:  6,
+			want:       0,
+		},
+		{
+			name:       "surrounded vote",
+			prevSource: 2,
+			prevTarget: 6,
+			newSource:  3,
+			newTarget:  5,
+			want:       0,
+		},
+		{
+			name:       "same source with a later target",
+			prevSource: 3,
+			prevTarget: 5,
+			newSource:  3,
+			newTarget:  6,
+			want:       1,
+		},
+		{
+			name:       "consecutive votes",
+			prevSource: 3,
+			prevTarget: 4,
+			newSource:  4,
+			newTarget:  5,
+			want:       2,
+		},
+		{
+			name:       "distant votes",
+			prevSource: 3,
+			prevTarget: 4,
+			newSource:  10,
+			newTarget:  11,
+			want:       8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := surroundDistance(tt.prevSource, tt.prevTarget, tt.newSource, tt.newTarget)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
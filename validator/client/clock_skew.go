@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The interval at which the local clock is checked against the reference clock.
+const clockSkewCheckInterval = 5 * time.Minute
+
+// The time allowed for a time server to answer a query.
+const ntpQueryTimeout = 5 * time.Second
+
+// NTP timestamps count seconds since 1900, 70 years before the Unix epoch.
+const ntpEpochOffset = 2208988800
+
+// ReferenceClock can report how far a trusted clock, such as a time
+// server, is ahead of the local clock.
+type ReferenceClock interface {
+	ClockOffset(ctx context.Context) (time.Duration, error)
+}
+
+// NTPClock is a reference clock which queries a time server using SNTP, as defined in RFC 4330.
+type NTPClock struct {
+	addr string
+}
+
+// NewNTPClock creates a reference clock for the time server at the given address,
+// such as pool.ntp.org:123.
+func NewNTPClock(addr string) *NTPClock {
+	return &NTPClock{addr: addr}
+}
+
+// ClockOffset queries the time server once, and returns how far its clock is ahead
+// of the local clock, compensating for the network delay of the query.
+func (c *NTPClock) ClockOffset(ctx context.Context) (time.Duration, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", c.addr)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not dial time server %s", c.addr)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.WithError(err).Debug("Could not close time server connection")
+		}
+	}()
+	deadline := time.Now().Add(ntpQueryTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	// A client request with no leap indicator, version 4 and mode 3.
+	req := make([]byte, 48)
+	req[0] = 0<<6 | 4<<3 | 3
+	sent := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, errors.Wrapf(err, "could not query time server %s", c.addr)
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not read time server %s response", c.addr)
+	}
+	received := time.Now()
+	if n < len(resp) {
+		return 0, errors.Errorf("time server %s response is %d bytes, expected %d", c.addr, n, len(resp))
+	}
+	if mode := resp[0] & 0x7; mode != 4 {
+		return 0, errors.Errorf("time server %s responded with mode %d, expected server mode", c.addr, mode)
+	}
+	// A stratum of 0 is a kiss of death message, rather than a timestamp.
+	if stratum := resp[1]; stratum == 0 {
+		return 0, errors.Errorf("time server %s refused the query", c.addr)
+	}
+	serverReceived := ntpTime(resp[32:40])
+	serverSent := ntpTime(resp[40:48])
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+// Converts a 64 bit NTP timestamp, in seconds and fractions of a second since 1900, to a time.
+func ntpTime(b []byte) time.Time {
+	secs := int64(binary.BigEndian.Uint32(b[:4])) - ntpEpochOffset
+	frac := int64(binary.BigEndian.Uint32(b[4:8]))
+	return time.Unix(secs, (frac*int64(time.Second))>>32)
+}
+
+// MonitorClockSkew checks the local clock against the reference clock when the validator
+// starts and periodically after, until the context is canceled. Duties are performed at
+// times derived from the local clock, so a skewed clock makes the validator miss them or
+// perform them early, and signing is refused once the skew is dangerously large.
+func (v *validator) MonitorClockSkew(ctx context.Context) {
+	if v.referenceClock == nil {
+		return
+	}
+	v.checkClockSkew(ctx)
+	ticker := time.NewTicker(clockSkewCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v.checkClockSkew(ctx)
+		}
+	}
+}
+
+// Measures the skew of the local clock against the reference clock. A failed check
+// keeps the previously measured skew.
+func (v *validator) checkClockSkew(ctx context.Context) {
+	offset, err := v.referenceClock.ClockOffset(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Could not check the local clock against the reference clock")
+		return
+	}
+	v.clockSkewLock.Lock()
+	v.clockSkew = offset
+	v.clockSkewLock.Unlock()
+	ValidatorClockSkewSeconds.Set(offset.Seconds())
+
+	log := log.WithField("skew", offset)
+	switch skew := absDuration(offset); {
+	case v.clockSkewRefusalThreshold > 0 && skew >= v.clockSkewRefusalThreshold:
+		log.WithField("threshold", v.clockSkewRefusalThreshold).Error(
+			"Local clock is dangerously skewed from the reference clock, refusing to sign until it is corrected",
+		)
+	case v.clockSkewWarningThreshold > 0 && skew >= v.clockSkewWarningThreshold:
+		log.WithField("threshold", v.clockSkewWarningThreshold).Warn(
+			"Local clock is skewed from the reference clock, duties may be missed or performed early",
+		)
+	default:
+		log.Debug("Checked the local clock against the reference clock")
+	}
+}
+
+// Returns an error if the last measured skew of the local clock is too large to sign safely.
+func (v *validator) checkClockSkewSafe() error {
+	if v.clockSkewRefusalThreshold == 0 {
+		return nil
+	}
+	v.clockSkewLock.RLock()
+	defer v.clockSkewLock.RUnlock()
+	if absDuration(v.clockSkew) >= v.clockSkewRefusalThreshold {
+		return errors.Errorf(
+			"local clock is skewed by %v, at least the refusal threshold of %v", v.clockSkew, v.clockSkewRefusalThreshold,
+		)
+	}
+	return nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
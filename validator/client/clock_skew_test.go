@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+type mockReferenceClock struct {
+	offset time.Duration
+	err    error
+}
+
+func (m *mockReferenceClock) ClockOffset(_ context.Context) (time.Duration, error) {
+	return m.offset, m.err
+}
+
+func TestCheckClockSkew(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  time.Duration
+		wantLog string
+		refuse  bool
+	}{
+		{
+			name:   "accurate clock",
+			offset: 10 * time.Millisecond,
+		},
+		{
+			name:    "skewed clock",
+			offset:  -time.Second,
+			wantLog: "Local clock is skewed from the reference clock",
+		},
+		{
+			name:    "dangerously skewed clock",
+			offset:  time.Minute,
+			wantLog: "refusing to sign until it is corrected",
+			refuse:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := logTest.NewGlobal()
+			v := &validator{
+				referenceClock:            &mockReferenceClock{offset: tt.offset},
+				clockSkewWarningThreshold: 500 * time.Millisecond,
+				clockSkewRefusalThreshold: 4 * time.Second,
+			}
+			v.checkClockSkew(context.Background())
+			if tt.wantLog != "" {
+				require.LogsContain(t, hook, tt.wantLog)
+			} else {
+				require.LogsDoNotContain(t, hook, "skewed")
+			}
+			if tt.refuse {
+				assert.ErrorContains(t, "local clock is skewed by 1m0s", v.checkClockSkewSafe())
+			} else {
+				assert.NoError(t, v.checkClockSkewSafe())
+			}
+		})
+	}
+}
+
+func TestCheckClockSkew_FailedCheckKeepsSkew(t *testing.T) {
+	hook := logTest.NewGlobal()
+	clock := &mockReferenceClock{offset: time.Minute}
+	v := &validator{
+		referenceClock:            clock,
+		clockSkewRefusalThreshold: 4 * time.Second,
+	}
+	v.checkClockSkew(context.Background())
+	clock.err = errors.New("time server unavailable")
+	v.checkClockSkew(context.Background())
+	require.LogsContain(t, hook, "time server unavailable")
+	assert.NotNil(t, v.checkClockSkewSafe())
+}
+
+func TestSubmitAttestation_ClockSkewRefusal(t *testing.T) {
+	hook := logTest.NewGlobal()
+	validator, _, validatorKey, finish := setup(t)
+	defer finish()
+	validator.missedDuties = newMissedDutyBuffer(maxMissedDuties)
+	validator.referenceClock = &mockReferenceClock{offset: -time.Minute}
+	validator.clockSkewRefusalThreshold = 4 * time.Second
+	validator.checkClockSkew(context.Background())
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+	validator.duties = &ethpb.DutiesResponse{Duties: []*ethpb.DutiesResponse_Duty{
+		{
+			PublicKey:      validatorKey.PublicKey().Marshal(),
+			CommitteeIndex: 5,
+			Committee:      []uint64{0, 7},
+			ValidatorIndex: 7,
+		},
+	}}
+
+	// No attestation data is requested, as signing is refused.
+	validator.SubmitAttestation(context.Background(), 30, pubKey)
+	require.LogsContain(t, hook, "Local clock is dangerously skewed, not signing")
+	missed := validator.recentMissedDuties()
+	require.Equal(t, 1, len(missed))
+	assert.Equal(t, dutyAttestation, missed[0].Duty)
+}
+
+func TestNTPClock_ClockOffset(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	// Serve a single query with a clock an hour ahead of the local clock.
+	go func() {
+		req := make([]byte, 48)
+		_, addr, err := conn.ReadFrom(req)
+		if err != nil {
+			return
+		}
+		now := time.Now().Add(time.Hour)
+		resp := make([]byte, 48)
+		resp[0] = 0<<6 | 4<<3 | 4
+		resp[1] = 1
+		secs := uint32(now.Unix() + ntpEpochOffset)
+		frac := uint32((uint64(now.Nanosecond()) << 32) / uint64(time.Second))
+		for _, offset := range []int{32, 40} {
+			binary.BigEndian.PutUint32(resp[offset:], secs)
+			binary.BigEndian.PutUint32(resp[offset+4:], frac)
+		}
+		if _, err := conn.WriteTo(resp, addr); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	offset, err := NewNTPClock(conn.LocalAddr().String()).ClockOffset(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, true, absDuration(offset-time.Hour) < time.Second, "Unexpected offset %v", offset)
+}
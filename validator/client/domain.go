@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/p2putils"
+)
+
+// ComputeDomain computes the signing domain for a domain type from the fork version
+// and genesis validators root, so the validator client can derive a domain without
+// requesting it from the beacon node.
+func ComputeDomain(domainType [4]byte, forkVersion, genesisValidatorsRoot []byte) ([]byte, error) {
+	if len(forkVersion) != 4 {
+		return nil, fmt.Errorf("fork version must be 4 bytes, received %d", len(forkVersion))
+	}
+	if len(genesisValidatorsRoot) != 32 {
+		return nil, fmt.Errorf("genesis validators root must be 32 bytes, received %d", len(genesisValidatorsRoot))
+	}
+	return helpers.ComputeDomain(domainType, forkVersion, genesisValidatorsRoot)
+}
+
+// Returns the signing domain for the epoch, computing it locally when possible and
+// falling back to requesting it from the beacon node otherwise.
+func (v *validator) signingDomain(ctx context.Context, epoch uint64, domainType [4]byte) ([]byte, error) {
+	d, err := v.localDomain(ctx, epoch, domainType)
+	if err == nil {
+		return d, nil
+	}
+	log.WithError(err).Debug("Could not compute domain locally, requesting it from the beacon node")
+	res, err := v.domainData(ctx, epoch, domainType[:])
+	if err != nil {
+		return nil, err
+	}
+	return res.SignatureDomain, nil
+}
+
+// Computes the signing domain for the epoch from the configured fork schedule and the
+// genesis validators root of the beacon node.
+func (v *validator) localDomain(ctx context.Context, epoch uint64, domainType [4]byte) ([]byte, error) {
+	genesisValidatorsRoot, err := v.fetchGenesisValidatorsRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return epochDomain(epoch, domainType, genesisValidatorsRoot)
+}
+
+// Computes the signing domain of the domain type at the epoch from the fork version the
+// configured fork schedule has at that epoch.
+func epochDomain(epoch uint64, domainType [4]byte, genesisValidatorsRoot []byte) ([]byte, error) {
+	fork, err := p2putils.Fork(epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not determine fork version")
+	}
+	return ComputeDomain(domainType, fork.CurrentVersion, genesisValidatorsRoot)
+}
+
+// Returns the genesis validators root of the beacon node, which only needs
+// to be requested once as it never changes.
+func (v *validator) fetchGenesisValidatorsRoot(ctx context.Context) ([]byte, error) {
+	v.genesisValidatorsRootLock.Lock()
+	defer v.genesisValidatorsRootLock.Unlock()
+	if len(v.genesisValidatorsRoot) != 0 {
+		return v.genesisValidatorsRoot, nil
+	}
+	if v.genesisFetcher == nil {
+		return nil, errors.New("no genesis fetcher configured")
+	}
+	genesis, err := v.genesisFetcher.GenesisInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch genesis info")
+	}
+	v.genesisValidatorsRoot = genesis.GenesisValidatorsRoot
+	return v.genesisValidatorsRoot, nil
+}
+
+// VerifyWithEpochDomain verifies a signature over an object root under the signing domain of
+// the domain type at the given epoch. The domain is always derived from the epoch itself,
+// taking the fork schedule into account, so callers can not verify a signature against the
+// domain of a stale epoch by mistake.
+func VerifyWithEpochDomain(
+	pubKey []byte,
+	objectRoot [32]byte,
+	domainType [4]byte,
+	epoch uint64,
+	sig []byte,
+	genesisValidatorsRoot []byte,
+) (bool, error) {
+	publicKey, err := bls.PublicKeyFromBytes(pubKey)
+	if err != nil {
+		return false, errors.Wrap(err, "could not convert bytes to public key")
+	}
+	signature, err := bls.SignatureFromBytes(sig)
+	if err != nil {
+		return false, errors.Wrap(err, "could not convert bytes to signature")
+	}
+	domain, err := epochDomain(epoch, domainType, genesisValidatorsRoot)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not get signing domain for epoch %d", epoch)
+	}
+	signingRoot, err := (&pb.SigningData{
+		ObjectRoot: objectRoot[:],
+		Domain:     domain,
+	}).HashTreeRoot()
+	if err != nil {
+		return false, errors.Wrap(err, "could not compute signing root")
+	}
+	return signature.Verify(publicKey, signingRoot[:]), nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
+)
+
+// The fraction of a slot before the start of an epoch at which its signing domains are prefetched.
+const domainPrefetchSlotDivisor = 2
+
+// PrefetchDomainData runs until the context is canceled, populating the domain data cache
+// with the signing domains duties request from it shortly before each epoch starts,
+// so duties at the start of an epoch do not wait on DomainData requests. Domains can change
+// when the fork version changes, which can happen once per epoch, so they are fetched again
+// for every epoch.
+func (v *validator) PrefetchDomainData(ctx context.Context) {
+	epoch := nextDomainPrefetchEpoch(v.genesisTime, timeutils.Now())
+	for {
+		wait := domainPrefetchTime(v.genesisTime, epoch).Sub(timeutils.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		v.prefetchDomainData(ctx, epoch)
+
+		// Skip any epochs which started while the domains were being fetched.
+		epoch++
+		if next := nextDomainPrefetchEpoch(v.genesisTime, timeutils.Now()); next > epoch {
+			epoch = next
+		}
+	}
+}
+
+// Requests the signing domains of the epoch which duties read from the domain data cache,
+// so they are cached. The aggregate and proof domain is left out, as it is computed locally
+// by signingDomain and only requested when that fails.
+func (v *validator) prefetchDomainData(ctx context.Context, epoch uint64) {
+	for _, d := range [][]byte{
+		params.BeaconConfig().DomainRandao[:],
+		params.BeaconConfig().DomainBeaconAttester[:],
+		params.BeaconConfig().DomainBeaconProposer[:],
+		params.BeaconConfig().DomainSelectionProof[:],
+	} {
+		if _, err := v.domainData(ctx, epoch, d); err != nil {
+			log.WithError(err).WithField("epoch", epoch).Errorf("Failed to prefetch domain data for domain %#x", d)
+			ValidatorDomainPrefetchFail.Inc()
+			continue
+		}
+		ValidatorDomainPrefetchSuccess.Inc()
+	}
+}
+
+// Returns the first epoch which has not started yet at the given time.
+func nextDomainPrefetchEpoch(genesisTime uint64, now time.Time) uint64 {
+	genesis := time.Unix(int64(genesisTime), 0)
+	if now.Before(genesis) {
+		return 0
+	}
+	epochDuration := time.Duration(params.BeaconConfig().SlotsPerEpoch*params.BeaconConfig().SecondsPerSlot) * time.Second
+	return uint64(now.Sub(genesis)/epochDuration) + 1
+}
+
+// Returns the time at which the signing domains of the epoch are prefetched,
+// which is shortly before the first slot of the epoch starts.
+func domainPrefetchTime(genesisTime, epoch uint64) time.Time {
+	epochStart := slotutil.SlotStartTime(genesisTime, epoch*params.BeaconConfig().SlotsPerEpoch)
+	return epochStart.Add(-slotutil.DivideSlotBy(domainPrefetchSlotDivisor))
+}
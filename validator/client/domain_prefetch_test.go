@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestNextDomainPrefetchEpoch(t *testing.T) {
+	genesisTime := uint64(1000)
+	genesis := time.Unix(int64(genesisTime), 0)
+	epochDuration := time.Duration(params.BeaconConfig().SlotsPerEpoch*params.BeaconConfig().SecondsPerSlot) * time.Second
+	tests := []struct {
+		name string
+		now  time.Time
+		want uint64
+	}{
+		{
+			name: "before genesis",
+			now:  genesis.Add(-time.Minute),
+			want: 0,
+		},
+		{
+			name: "at genesis",
+			now:  genesis,
+			want: 1,
+		},
+		{
+			name: "just before epoch 1",
+			now:  genesis.Add(epochDuration - time.Millisecond),
+			want: 1,
+		},
+		{
+			name: "at epoch 1",
+			now:  genesis.Add(epochDuration),
+			want: 2,
+		},
+		{
+			name: "during epoch 5",
+			now:  genesis.Add(5*epochDuration + epochDuration/2),
+			want: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextDomainPrefetchEpoch(genesisTime, tt.now))
+		})
+	}
+}
+
+func TestDomainPrefetchTime(t *testing.T) {
+	genesisTime := uint64(1000)
+	secondsPerSlot := params.BeaconConfig().SecondsPerSlot
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+
+	// Domains are prefetched half a slot before the epoch starts.
+	epochStart := time.Unix(int64(genesisTime+3*slotsPerEpoch*secondsPerSlot), 0)
+	prefetchTime := domainPrefetchTime(genesisTime, 3)
+	assert.Equal(t, epochStart.Add(-time.Duration(secondsPerSlot)*time.Second/2), prefetchTime)
+
+	// This is during the last slot of the previous epoch, after the epoch has become the next one to prefetch.
+	lastSlotStart := epochStart.Add(-time.Duration(secondsPerSlot) * time.Second)
+	assert.Equal(t, true, prefetchTime.After(lastSlotStart))
+	assert.Equal(t, uint64(3), nextDomainPrefetchEpoch(genesisTime, prefetchTime))
+}
+
+func TestPrefetchDomainData(t *testing.T) {
+	hook := logTest.NewGlobal()
+	validator, m, _, finish := setup(t)
+	defer finish()
+
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		&ethpb.DomainRequest{Epoch: 4, Domain: params.BeaconConfig().DomainSelectionProof[:]},
+	).Return(nil, errors.New("beacon node unavailable"))
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), // epoch
+	).Times(3).DoAndReturn(func(_ context.Context, req *ethpb.DomainRequest) (*ethpb.DomainResponse, error) {
+		require.Equal(t, uint64(4), req.Epoch)
+		return &ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil
+	})
+
+	// A domain which cannot be fetched does not stop the others from being prefetched.
+	validator.prefetchDomainData(context.Background(), 4)
+	require.LogsContain(t, hook, "Failed to prefetch domain data")
+	require.LogsContain(t, hook, "beacon node unavailable")
+}
+
+func TestPrefetchDomainData_StopsOnContextCancel(t *testing.T) {
+	validator, _, _, finish := setup(t)
+	defer finish()
+	// Genesis is an hour away, so no domains are due to be prefetched during the test.
+	validator.genesisTime = uint64(timeutils.Now().Add(time.Hour).Unix())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan struct{})
+	go func() {
+		validator.PrefetchDomainData(ctx)
+		close(exited)
+	}()
+	cancel()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Domain prefetching did not stop after the context was canceled")
+	}
+}
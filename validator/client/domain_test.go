@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+// Mainnet genesis validators root and the aggregate and proof domain the beacon node
+// serves for it at the genesis fork version.
+const (
+	mainnetGenesisValidatorsRoot   = "4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"
+	mainnetAggregateAndProofDomain = "06000000b5303f2ad2010d699a76c8e62350947421a3e4a979779642cfdb0f66"
+)
+
+func TestComputeDomain(t *testing.T) {
+	genesisValidatorsRoot, err := hex.DecodeString(mainnetGenesisValidatorsRoot)
+	require.NoError(t, err)
+	beaconDomain, err := hex.DecodeString(mainnetAggregateAndProofDomain)
+	require.NoError(t, err)
+
+	d, err := ComputeDomain(params.BeaconConfig().DomainAggregateAndProof, []byte{0, 0, 0, 0}, genesisValidatorsRoot)
+	require.NoError(t, err)
+	assert.DeepEqual(t, beaconDomain, d)
+
+	_, err = ComputeDomain(params.BeaconConfig().DomainAggregateAndProof, []byte{0, 0}, genesisValidatorsRoot)
+	assert.ErrorContains(t, "fork version must be 4 bytes", err)
+	_, err = ComputeDomain(params.BeaconConfig().DomainAggregateAndProof, []byte{0, 0, 0, 0}, nil)
+	assert.ErrorContains(t, "genesis validators root must be 32 bytes", err)
+}
+
+func TestSigningDomain_MatchesBeaconDomain(t *testing.T) {
+	validator, m, _, finish := setup(t)
+	defer finish()
+	genesisValidatorsRoot, err := hex.DecodeString(mainnetGenesisValidatorsRoot)
+	require.NoError(t, err)
+	beaconDomain, err := hex.DecodeString(mainnetAggregateAndProofDomain)
+	require.NoError(t, err)
+	domainType := params.BeaconConfig().DomainAggregateAndProof
+
+	// Without genesis information the domain is requested from the beacon node.
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		&ethpb.DomainRequest{Epoch: 0, Domain: domainType[:]},
+	).Return(&ethpb.DomainResponse{SignatureDomain: beaconDomain}, nil /*err*/)
+	remote, err := validator.signingDomain(context.Background(), 0, domainType)
+	require.NoError(t, err)
+	assert.DeepEqual(t, beaconDomain, remote)
+
+	// Once genesis information is available the domain is computed locally.
+	validator.genesisFetcher = &mockGenesisFetcher{
+		genesis: &ethpb.Genesis{GenesisValidatorsRoot: genesisValidatorsRoot},
+	}
+	local, err := validator.signingDomain(context.Background(), 0, domainType)
+	require.NoError(t, err)
+	assert.DeepEqual(t, remote, local)
+}
+
+func TestAggregateAndProofSignature_LocalDomain(t *testing.T) {
+	validator, _, validatorKey, finish := setup(t)
+	defer finish()
+	genesisValidatorsRoot, err := hex.DecodeString(mainnetGenesisValidatorsRoot)
+	require.NoError(t, err)
+	beaconDomain, err := hex.DecodeString(mainnetAggregateAndProofDomain)
+	require.NoError(t, err)
+	validator.genesisFetcher = &mockGenesisFetcher{
+		genesis: &ethpb.Genesis{GenesisValidatorsRoot: genesisValidatorsRoot},
+	}
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+
+	agg := &ethpb.AggregateAttestationAndProof{
+		AggregatorIndex: 0,
+		Aggregate: &ethpb.Attestation{
+			AggregationBits: bitfield.NewBitlist(1), Data: &ethpb.AttestationData{
+				BeaconBlockRoot: make([]byte, 32),
+				Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			},
+			Signature: make([]byte, 96),
+		},
+		SelectionProof: make([]byte, 96),
+	}
+	// No DomainData request is expected.
+	sig, err := validator.aggregateAndProofSig(context.Background(), pubKey, agg)
+	require.NoError(t, err)
+	root, err := helpers.ComputeSigningRoot(agg, beaconDomain)
+	require.NoError(t, err)
+	blsSig, err := bls.SignatureFromBytes(sig)
+	require.NoError(t, err)
+	assert.Equal(t, true, blsSig.Verify(validatorKey.PublicKey(), root[:]))
+}
+
+func TestVerifyWithEpochDomain_ForkSchedule(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	c := params.BeaconConfig()
+	c.ForkVersionSchedule = map[uint64][]byte{
+		10: {1, 0, 0, 0},
+	}
+	params.OverrideBeaconConfig(c)
+
+	genesisValidatorsRoot, err := hex.DecodeString(mainnetGenesisValidatorsRoot)
+	require.NoError(t, err)
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	pubKey := secretKey.PublicKey().Marshal()
+	objectRoot := [32]byte{'r', 'o', 'o', 't'}
+	domainType := params.BeaconConfig().DomainBeaconAttester
+
+	// Sign under the domain of an epoch before the fork.
+	domain, err := ComputeDomain(domainType, c.GenesisForkVersion, genesisValidatorsRoot)
+	require.NoError(t, err)
+	signingRoot, err := (&pb.SigningData{ObjectRoot: objectRoot[:], Domain: domain}).HashTreeRoot()
+	require.NoError(t, err)
+	sig := secretKey.Sign(signingRoot[:]).Marshal()
+
+	ok, err := VerifyWithEpochDomain(pubKey, objectRoot, domainType, 5, sig, genesisValidatorsRoot)
+	require.NoError(t, err)
+	assert.Equal(t, true, ok, "Signature should verify under the domain of its own epoch")
+	ok, err = VerifyWithEpochDomain(pubKey, objectRoot, domainType, 10, sig, genesisValidatorsRoot)
+	require.NoError(t, err)
+	assert.Equal(t, false, ok, "Signature should not verify under the domain of a later fork")
+	ok, err = VerifyWithEpochDomain(pubKey, objectRoot, params.BeaconConfig().DomainBeaconProposer, 5, sig, genesisValidatorsRoot)
+	require.NoError(t, err)
+	assert.Equal(t, false, ok, "Signature should not verify under the domain of another domain type")
+
+	_, err = VerifyWithEpochDomain(pubKey, objectRoot, domainType, 5, []byte{1, 2, 3}, genesisValidatorsRoot)
+	assert.ErrorContains(t, "could not convert bytes to signature", err)
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+)
+
+// DutyCountdown reports when a validator's next attestation, block proposal and
+// aggregation duties are due. Duties which are not scheduled within the duties
+// currently known to the validator client are marked as not found.
+type DutyCountdown struct {
+	PublicKey         [48]byte
+	HasAttestation    bool
+	AttestationSlot   uint64
+	TimeToAttestation time.Duration
+	HasProposal       bool
+	ProposalSlot      uint64
+	TimeToProposal    time.Duration
+	HasAggregation    bool
+	AggregationSlot   uint64
+	TimeToAggregation time.Duration
+}
+
+// Computes the time until the next duties of each validator from the current and next
+// epoch duties, using the same slot timing the validator client waits on before
+// performing each duty. Aggregation duties are the ones found when the duties were
+// last updated, so the countdown does not sign selection proofs.
+func (v *validator) dutyCountdowns(now time.Time) ([]*DutyCountdown, error) {
+	duties := v.currentDuties()
+	if duties == nil {
+		return nil, errors.New("validator duties are not yet available")
+	}
+	allDuties := make([]*ethpb.DutiesResponse_Duty, 0, len(duties.CurrentEpochDuties)+len(duties.NextEpochDuties))
+	allDuties = append(allDuties, duties.CurrentEpochDuties...)
+	allDuties = append(allDuties, duties.NextEpochDuties...)
+
+	countdownsByKey := make(map[[48]byte]*DutyCountdown)
+	countdowns := make([]*DutyCountdown, 0)
+	for _, duty := range allDuties {
+		if duty == nil {
+			continue
+		}
+		pubKey := bytesutil.ToBytes48(duty.PublicKey)
+		countdown, ok := countdownsByKey[pubKey]
+		if !ok {
+			countdown = &DutyCountdown{PublicKey: pubKey}
+			countdownsByKey[pubKey] = countdown
+			countdowns = append(countdowns, countdown)
+		}
+		for _, slot := range duty.ProposerSlots {
+			proposalTime := slotutil.SlotStartTime(v.genesisTime, slot)
+			if proposalTime.Before(now) || (countdown.HasProposal && slot >= countdown.ProposalSlot) {
+				continue
+			}
+			countdown.HasProposal = true
+			countdown.ProposalSlot = slot
+			countdown.TimeToProposal = proposalTime.Sub(now)
+		}
+		// Current epoch duties come first, so the first upcoming attester
+		// slot seen for a validator is its next one.
+		attestationTime := slotOneThirdTime(v.genesisTime, duty.AttesterSlot)
+		if !countdown.HasAttestation && !attestationTime.Before(now) {
+			countdown.HasAttestation = true
+			countdown.AttestationSlot = duty.AttesterSlot
+			countdown.TimeToAttestation = attestationTime.Sub(now)
+		}
+		aggregationTime := slotTwoThirdsTime(v.genesisTime, duty.AttesterSlot)
+		if !countdown.HasAggregation && !aggregationTime.Before(now) && v.isCachedAggregator(pubKey, duty.AttesterSlot) {
+			countdown.HasAggregation = true
+			countdown.AggregationSlot = duty.AttesterSlot
+			countdown.TimeToAggregation = aggregationTime.Sub(now)
+		}
+	}
+	return countdowns, nil
+}
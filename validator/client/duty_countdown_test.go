@@ -0,0 +1,86 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestDutyCountdowns(t *testing.T) {
+	validator, _, validatorKey, finish := setup(t)
+	defer finish()
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+	otherPubKey := [48]byte{1}
+
+	secondsPerSlot := params.BeaconConfig().SecondsPerSlot
+	slotDuration := time.Duration(secondsPerSlot) * time.Second
+	validator.genesisTime = 1000
+	// The current time is exactly at the start of slot 10.
+	now := time.Unix(int64(validator.genesisTime+10*secondsPerSlot), 0)
+	validator.duties = &ethpb.DutiesResponse{
+		CurrentEpochDuties: []*ethpb.DutiesResponse_Duty{
+			{
+				PublicKey:     pubKey[:],
+				AttesterSlot:  12,
+				ProposerSlots: []uint64{9, 14},
+			},
+			{
+				PublicKey:    otherPubKey[:],
+				AttesterSlot: 5,
+			},
+		},
+		NextEpochDuties: []*ethpb.DutiesResponse_Duty{
+			{
+				PublicKey:    pubKey[:],
+				AttesterSlot: 40,
+			},
+			{
+				PublicKey:    otherPubKey[:],
+				AttesterSlot: 35,
+			},
+		},
+	}
+	validator.aggregatorSlots = map[[48]byte][]uint64{
+		pubKey:      {12},
+		otherPubKey: {35},
+	}
+
+	countdowns, err := validator.dutyCountdowns(now)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(countdowns))
+
+	assert.DeepEqual(t, &DutyCountdown{
+		PublicKey:         pubKey,
+		HasAttestation:    true,
+		AttestationSlot:   12,
+		TimeToAttestation: 2*slotDuration + slotDuration/3,
+		HasProposal:       true,
+		ProposalSlot:      14,
+		TimeToProposal:    4 * slotDuration,
+		HasAggregation:    true,
+		AggregationSlot:   12,
+		TimeToAggregation: 2*slotDuration + 2*(slotDuration/3),
+	}, countdowns[0])
+
+	// The other validator's current epoch attestation has already passed,
+	// so its next duty is in the next epoch.
+	assert.Equal(t, otherPubKey, countdowns[1].PublicKey)
+	assert.Equal(t, true, countdowns[1].HasAttestation)
+	assert.Equal(t, uint64(35), countdowns[1].AttestationSlot)
+	assert.Equal(t, 25*slotDuration+slotDuration/3, countdowns[1].TimeToAttestation)
+	assert.Equal(t, false, countdowns[1].HasProposal)
+	assert.Equal(t, true, countdowns[1].HasAggregation)
+	assert.Equal(t, uint64(35), countdowns[1].AggregationSlot)
+}
+
+func TestDutyCountdowns_NoDuties(t *testing.T) {
+	validator, _, _, finish := setup(t)
+	defer finish()
+	_, err := validator.dutyCountdowns(time.Now())
+	assert.ErrorContains(t, "validator duties are not yet available", err)
+}
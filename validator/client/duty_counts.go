@@ -0,0 +1,64 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// DutyCounts reports how many attestation, proposal, aggregation and sync committee
+// duties the validators managed by the validator client have in an epoch.
+type DutyCounts struct {
+	Epoch         uint64
+	Attestations  uint64
+	Proposals     uint64
+	Aggregations  uint64
+	SyncCommittee uint64
+}
+
+// Counts the duties of an epoch from the current and next epoch duties. Aggregation duties
+// are the ones found when the duties were last updated, so counting them does not sign
+// selection proofs. There are no sync committees in phase 0, so no sync committee duties.
+func (v *validator) dutyCounts(epoch uint64) (*DutyCounts, error) {
+	duties := v.currentDuties()
+	if duties == nil {
+		return nil, errors.New("validator duties are not yet available")
+	}
+	startSlot, err := helpers.StartSlot(epoch)
+	if err != nil {
+		return nil, err
+	}
+	endSlot := startSlot + params.BeaconConfig().SlotsPerEpoch
+	inEpoch := func(slot uint64) bool {
+		return slot >= startSlot && slot < endSlot
+	}
+
+	allDuties := make([]*ethpb.DutiesResponse_Duty, 0, len(duties.CurrentEpochDuties)+len(duties.NextEpochDuties))
+	allDuties = append(allDuties, duties.CurrentEpochDuties...)
+	allDuties = append(allDuties, duties.NextEpochDuties...)
+
+	counts := &DutyCounts{Epoch: epoch}
+	for _, duty := range allDuties {
+		if duty == nil {
+			continue
+		}
+		for _, slot := range duty.ProposerSlots {
+			if inEpoch(slot) {
+				counts.Proposals++
+			}
+		}
+		if duty.Status != ethpb.ValidatorStatus_ACTIVE && duty.Status != ethpb.ValidatorStatus_EXITING {
+			continue
+		}
+		if !inEpoch(duty.AttesterSlot) {
+			continue
+		}
+		counts.Attestations++
+		if v.isCachedAggregator(bytesutil.ToBytes48(duty.PublicKey), duty.AttesterSlot) {
+			counts.Aggregations++
+		}
+	}
+	return counts, nil
+}
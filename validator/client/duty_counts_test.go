@@ -0,0 +1,82 @@
+package client
+
+import (
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestDutyCounts(t *testing.T) {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	aggregating := [48]byte{1}
+	attesting := [48]byte{2}
+	pending := [48]byte{3}
+	exiting := [48]byte{4}
+	v := &validator{
+		duties: &ethpb.DutiesResponse{
+			CurrentEpochDuties: []*ethpb.DutiesResponse_Duty{
+				{
+					PublicKey:     aggregating[:],
+					Status:        ethpb.ValidatorStatus_ACTIVE,
+					AttesterSlot:  slotsPerEpoch + 3,
+					ProposerSlots: []uint64{slotsPerEpoch + 1, slotsPerEpoch + 7},
+				},
+				{
+					PublicKey:    attesting[:],
+					Status:       ethpb.ValidatorStatus_ACTIVE,
+					AttesterSlot: slotsPerEpoch + 5,
+				},
+				// Validators which are not active have no attester duties.
+				{
+					PublicKey: pending[:],
+					Status:    ethpb.ValidatorStatus_PENDING,
+				},
+				{
+					PublicKey:    exiting[:],
+					Status:       ethpb.ValidatorStatus_EXITING,
+					AttesterSlot: 2*slotsPerEpoch - 1,
+				},
+				nil,
+			},
+			NextEpochDuties: []*ethpb.DutiesResponse_Duty{
+				{
+					PublicKey:     aggregating[:],
+					Status:        ethpb.ValidatorStatus_ACTIVE,
+					AttesterSlot:  2*slotsPerEpoch + 3,
+					ProposerSlots: []uint64{2*slotsPerEpoch + 1},
+				},
+			},
+		},
+		aggregatorSlots: map[[48]byte][]uint64{
+			aggregating: {slotsPerEpoch + 3, 2*slotsPerEpoch + 3},
+			// An aggregator of an earlier epoch does not aggregate this epoch.
+			attesting: {5},
+		},
+	}
+
+	counts, err := v.dutyCounts(1)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &DutyCounts{
+		Epoch:        1,
+		Attestations: 3,
+		Proposals:    2,
+		Aggregations: 1,
+	}, counts)
+
+	// Next epoch duties are counted once their epoch has started, before the duties are updated.
+	counts, err = v.dutyCounts(2)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &DutyCounts{
+		Epoch:        2,
+		Attestations: 1,
+		Proposals:    1,
+		Aggregations: 1,
+	}, counts)
+
+	v.duties = nil
+	_, err = v.dutyCounts(1)
+	assert.ErrorContains(t, "validator duties are not yet available", err)
+}
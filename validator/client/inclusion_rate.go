@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// InclusionRate reports the fraction of a validator's attestations which were included
+// on chain over a window of epochs. Only epochs in which the validator was active, and
+// so expected to attest, count towards the rate.
+type InclusionRate struct {
+	PublicKey      [48]byte
+	ActiveEpochs   uint64
+	IncludedEpochs uint64
+	Rate           float64
+}
+
+// InclusionRateWindow is the range of epochs, inclusive, inclusion rates were computed over.
+type InclusionRateWindow struct {
+	StartEpoch uint64
+	EndEpoch   uint64
+	Rates      []*InclusionRate
+}
+
+// Computes the attestation inclusion rate of each validator over the last numEpochs epochs
+// whose attestations can no longer be included, from the attestations included in blocks
+// as reported by the beacon node. An attestation for an epoch may be included until the end
+// of the following epoch, so the window ends two epochs before the current one.
+func (v *validator) inclusionRates(ctx context.Context, now time.Time, numEpochs uint64) (*InclusionRateWindow, error) {
+	if numEpochs == 0 {
+		return nil, errors.New("number of epochs must be greater than 0")
+	}
+	if now.Unix() < int64(v.genesisTime) {
+		return nil, errors.New("chain has not started yet")
+	}
+	slotsSinceGenesis := uint64(now.Unix()-int64(v.genesisTime)) / params.BeaconConfig().SecondsPerSlot
+	currentEpoch := slotsSinceGenesis / params.BeaconConfig().SlotsPerEpoch
+	if currentEpoch < 2 {
+		return nil, errors.New("no epoch has passed its attestation inclusion window yet")
+	}
+	endEpoch := currentEpoch - 2
+	startEpoch := uint64(0)
+	if endEpoch+1 > numEpochs {
+		startEpoch = endEpoch + 1 - numEpochs
+	}
+
+	pubKeys, err := v.keyManager.FetchValidatingPublicKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch validating public keys")
+	}
+	window := &InclusionRateWindow{
+		StartEpoch: startEpoch,
+		EndEpoch:   endEpoch,
+		Rates:      make([]*InclusionRate, len(pubKeys)),
+	}
+	if len(pubKeys) == 0 {
+		return window, nil
+	}
+	ratesByKey := make(map[[48]byte]*InclusionRate, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		window.Rates[i] = &InclusionRate{PublicKey: pubKey}
+		ratesByKey[pubKey] = window.Rates[i]
+	}
+
+	validators, err := v.listValidators(ctx, bytesutil.FromBytes48Array(pubKeys))
+	if err != nil {
+		return nil, err
+	}
+	ratesByIndex := make(map[uint64]*InclusionRate, len(validators))
+	includedByIndex := make(map[uint64]map[uint64]bool, len(validators))
+	for _, container := range validators {
+		if container.Validator == nil {
+			continue
+		}
+		rate, ok := ratesByKey[bytesutil.ToBytes48(container.Validator.PublicKey)]
+		if !ok {
+			continue
+		}
+		// Validators which were not active in the window were not expected to attest.
+		for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+			if container.Validator.ActivationEpoch <= epoch && epoch < container.Validator.ExitEpoch {
+				rate.ActiveEpochs++
+			}
+		}
+		ratesByIndex[container.Index] = rate
+		includedByIndex[container.Index] = make(map[uint64]bool)
+	}
+
+	// Attestations for the last epoch of the window may be included in the epoch after it.
+	for epoch := startEpoch; epoch <= endEpoch+1; epoch++ {
+		atts, err := v.listIndexedAttestations(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+		for _, att := range atts {
+			if att.Data == nil || att.Data.Target == nil {
+				continue
+			}
+			target := att.Data.Target.Epoch
+			if target < startEpoch || target > endEpoch {
+				continue
+			}
+			for _, index := range att.AttestingIndices {
+				if included, ok := includedByIndex[index]; ok {
+					included[target] = true
+				}
+			}
+		}
+	}
+	for index, rate := range ratesByIndex {
+		rate.IncludedEpochs = uint64(len(includedByIndex[index]))
+		if rate.ActiveEpochs > 0 {
+			rate.Rate = float64(rate.IncludedEpochs) / float64(rate.ActiveEpochs)
+		}
+	}
+	return window, nil
+}
+
+// Lists the validators with the given public keys, following pagination.
+func (v *validator) listValidators(ctx context.Context, pubKeys [][]byte) ([]*ethpb.Validators_ValidatorContainer, error) {
+	validators := make([]*ethpb.Validators_ValidatorContainer, 0, len(pubKeys))
+	req := &ethpb.ListValidatorsRequest{
+		PublicKeys: pubKeys,
+	}
+	for {
+		resp, err := v.beaconClient.ListValidators(ctx, req)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list validators")
+		}
+		validators = append(validators, resp.ValidatorList...)
+		if resp.NextPageToken == "" || len(validators) >= int(resp.TotalSize) {
+			return validators, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// Lists the indexed attestations included in blocks of an epoch, following pagination.
+func (v *validator) listIndexedAttestations(ctx context.Context, epoch uint64) ([]*ethpb.IndexedAttestation, error) {
+	atts := make([]*ethpb.IndexedAttestation, 0)
+	req := &ethpb.ListIndexedAttestationsRequest{
+		QueryFilter: &ethpb.ListIndexedAttestationsRequest_Epoch{Epoch: epoch},
+	}
+	for {
+		resp, err := v.beaconClient.ListIndexedAttestations(ctx, req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not list attestations included in epoch %d", epoch)
+		}
+		atts = append(atts, resp.IndexedAttestations...)
+		// An empty result comes with a non empty page token, so the total size is checked too.
+		if resp.NextPageToken == "" || len(atts) >= int(resp.TotalSize) {
+			return atts, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestInclusionRates(t *testing.T) {
+	validator, m, validatorKey, finish := setup(t)
+	defer finish()
+	pubKey := bytesutil.ToBytes48(validatorKey.PublicKey().Marshal())
+	// A validator activated in the middle of the window.
+	otherKey, err := bls.RandKey()
+	require.NoError(t, err)
+	otherPubKey := bytesutil.ToBytes48(otherKey.PublicKey().Marshal())
+	// A validator which has not been activated yet.
+	pendingKey, err := bls.RandKey()
+	require.NoError(t, err)
+	pendingPubKey := bytesutil.ToBytes48(pendingKey.PublicKey().Marshal())
+	validator.keyManager.(*mockKeymanager).keysMap[otherPubKey] = otherKey
+	validator.keyManager.(*mockKeymanager).keysMap[pendingPubKey] = pendingKey
+
+	// The current epoch is 12, so the window of 4 epochs is epochs 7 to 10.
+	validator.genesisTime = 1000
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	now := time.Unix(int64(validator.genesisTime+12*slotsPerEpoch*params.BeaconConfig().SecondsPerSlot), 0)
+
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	m.beaconClient.EXPECT().ListValidators(
+		gomock.Any(), // ctx
+		gomock.Any(), // req
+	).Return(&ethpb.Validators{
+		ValidatorList: []*ethpb.Validators_ValidatorContainer{
+			{Index: 1, Validator: &ethpb.Validator{PublicKey: pubKey[:], ExitEpoch: farFuture}},
+			{Index: 2, Validator: &ethpb.Validator{PublicKey: otherPubKey[:], ActivationEpoch: 9, ExitEpoch: farFuture}},
+			{Index: 3, Validator: &ethpb.Validator{PublicKey: pendingPubKey[:], ActivationEpoch: farFuture, ExitEpoch: farFuture}},
+		},
+		TotalSize: 3,
+	}, nil)
+
+	att := func(target uint64, indices ...uint64) *ethpb.IndexedAttestation {
+		return &ethpb.IndexedAttestation{
+			AttestingIndices: indices,
+			Data: &ethpb.AttestationData{
+				Target: &ethpb.Checkpoint{Epoch: target},
+			},
+		}
+	}
+	// Attestations included in the blocks of each epoch. Validator 1 misses epoch 9
+	// and validator 2 misses epoch 9, its first active epoch.
+	includedAtts := map[uint64][]*ethpb.IndexedAttestation{
+		7:  {att(6, 1), att(7, 1)},
+		8:  {att(8, 1, 4)},
+		9:  {},
+		10: {att(10, 1)},
+		11: {att(10, 2, 5), att(11, 1, 2)},
+	}
+	m.beaconClient.EXPECT().ListIndexedAttestations(
+		gomock.Any(), // ctx
+		gomock.Any(), // req
+	).DoAndReturn(func(_ context.Context, req *ethpb.ListIndexedAttestationsRequest) (*ethpb.ListIndexedAttestationsResponse, error) {
+		epoch := req.QueryFilter.(*ethpb.ListIndexedAttestationsRequest_Epoch).Epoch
+		atts, ok := includedAtts[epoch]
+		require.Equal(t, true, ok, "Unexpected request for epoch %d", epoch)
+		// The beacon node returns a non empty page token even for the last page.
+		return &ethpb.ListIndexedAttestationsResponse{
+			IndexedAttestations: atts,
+			TotalSize:           int32(len(atts)),
+			NextPageToken:       "0",
+		}, nil
+	}).Times(5)
+
+	window, err := validator.inclusionRates(context.Background(), now, 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), window.StartEpoch)
+	assert.Equal(t, uint64(10), window.EndEpoch)
+	require.Equal(t, 3, len(window.Rates))
+	rates := make(map[[48]byte]*InclusionRate)
+	for _, rate := range window.Rates {
+		rates[rate.PublicKey] = rate
+	}
+	assert.DeepEqual(t, &InclusionRate{
+		PublicKey:      pubKey,
+		ActiveEpochs:   4,
+		IncludedEpochs: 3,
+		Rate:           0.75,
+	}, rates[pubKey])
+	assert.DeepEqual(t, &InclusionRate{
+		PublicKey:      otherPubKey,
+		ActiveEpochs:   2,
+		IncludedEpochs: 1,
+		Rate:           0.5,
+	}, rates[otherPubKey])
+	assert.DeepEqual(t, &InclusionRate{
+		PublicKey: pendingPubKey,
+	}, rates[pendingPubKey])
+}
+
+func TestInclusionRates_Errors(t *testing.T) {
+	validator, _, _, finish := setup(t)
+	defer finish()
+	validator.genesisTime = 1000
+	_, err := validator.inclusionRates(context.Background(), time.Unix(2000, 0), 0)
+	assert.ErrorContains(t, "number of epochs must be greater than 0", err)
+	_, err = validator.inclusionRates(context.Background(), time.Unix(500, 0), 4)
+	assert.ErrorContains(t, "chain has not started yet", err)
+	_, err = validator.inclusionRates(context.Background(), time.Unix(1000, 0), 4)
+	assert.ErrorContains(t, "no epoch has passed its attestation inclusion window yet", err)
+}
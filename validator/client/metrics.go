@@ -173,6 +173,49 @@ var (
 			"pubkey",
 		},
 	)
+	// ValidatorDomainPrefetchSuccess used to count signing domains prefetched ahead of their epoch.
+	ValidatorDomainPrefetchSuccess = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "successful_domain_prefetches",
+			Help:      "Count the signing domains prefetched ahead of their epoch.",
+		},
+	)
+	// ValidatorDomainPrefetchFail used to count signing domains which could not be prefetched.
+	ValidatorDomainPrefetchFail = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "failed_domain_prefetches",
+			Help:      "Count the signing domains which could not be prefetched ahead of their epoch.",
+		},
+	)
+	// ValidatorClockSkewSeconds used to track the last measured skew of the local clock.
+	ValidatorClockSkewSeconds = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "clock_skew_seconds",
+			Help:      "How far the reference clock was ahead of the local clock when last checked, in seconds.",
+		},
+	)
+	// ValidatorAttestNearSlashableVec used to count attestations signed within the
+	// slashing warning margin of a surround vote.
+	ValidatorAttestNearSlashableVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "validator_attestations_near_slashable_total",
+			Help: "Count the attestations signed within the slashing warning margin of a surround vote.",
+		},
+		[]string{
+			"pubkey",
+		},
+	)
+	// ValidatorNilSignatures used to count signing requests for which the keymanager returned no signature.
+	ValidatorNilSignatures = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "nil_signatures",
+			Help:      "Count the signing requests for which the keymanager returned neither a signature nor an error.",
+		},
+	)
 	// ValidatorAttestFailVecSlasher used to count failed attestations by slashing protection.
 	ValidatorAttestFailVecSlasher = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
+)
+
+// maxMissedDuties is the number of most recent missed duties the validator client keeps.
+const maxMissedDuties = 256
+
+// Duties a validator can miss.
+const (
+	dutyAttestation = "attestation"
+	dutyProposal    = "proposal"
+	dutyAggregation = "aggregation"
+)
+
+// MissedDuty records a duty the validator client failed to complete, and why.
+type MissedDuty struct {
+	PublicKey [48]byte
+	Slot      uint64
+	Duty      string
+	Reason    string
+	Time      time.Time
+}
+
+// missedDutyBuffer is a rolling buffer of the most recent missed duties,
+// which evicts the oldest missed duty once full.
+type missedDutyBuffer struct {
+	lock   sync.RWMutex
+	duties []*MissedDuty
+	next   int
+	full   bool
+}
+
+func newMissedDutyBuffer(size int) *missedDutyBuffer {
+	return &missedDutyBuffer{
+		duties: make([]*MissedDuty, size),
+	}
+}
+
+func (b *missedDutyBuffer) add(duty *MissedDuty) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.duties[b.next] = duty
+	b.next = (b.next + 1) % len(b.duties)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Returns the missed duties in the buffer, most recent first.
+func (b *missedDutyBuffer) recent() []*MissedDuty {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	size := b.next
+	if b.full {
+		size = len(b.duties)
+	}
+	recent := make([]*MissedDuty, size)
+	for i := 0; i < size; i++ {
+		recent[i] = b.duties[(b.next-1-i+len(b.duties))%len(b.duties)]
+	}
+	return recent
+}
+
+// Records a duty the validator could not complete, along with the error which caused it.
+func (v *validator) recordMissedDuty(duty string, pubKey [48]byte, slot uint64, reason string, err error) {
+	if v.missedDuties == nil {
+		return
+	}
+	if err != nil {
+		reason = fmt.Sprintf("%s: %v", reason, err)
+	}
+	v.missedDuties.add(&MissedDuty{
+		PublicKey: pubKey,
+		Slot:      slot,
+		Duty:      duty,
+		Reason:    reason,
+		Time:      timeutils.Now(),
+	})
+}
+
+// Returns the duties the validator most recently missed, most recent first.
+func (v *validator) recentMissedDuties() []*MissedDuty {
+	if v.missedDuties == nil {
+		return []*MissedDuty{}
+	}
+	return v.missedDuties.recent()
+}
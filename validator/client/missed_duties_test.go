@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMissedDutyBuffer(t *testing.T) {
+	b := newMissedDutyBuffer(3)
+	assert.Equal(t, 0, len(b.recent()))
+
+	b.add(&MissedDuty{Slot: 1})
+	b.add(&MissedDuty{Slot: 2})
+	recent := b.recent()
+	require.Equal(t, 2, len(recent))
+	assert.Equal(t, uint64(2), recent[0].Slot)
+	assert.Equal(t, uint64(1), recent[1].Slot)
+
+	// Once full, the oldest missed duties are evicted.
+	for slot := uint64(3); slot <= 7; slot++ {
+		b.add(&MissedDuty{Slot: slot})
+	}
+	recent = b.recent()
+	require.Equal(t, 3, len(recent))
+	assert.Equal(t, uint64(7), recent[0].Slot)
+	assert.Equal(t, uint64(6), recent[1].Slot)
+	assert.Equal(t, uint64(5), recent[2].Slot)
+}
+
+func TestRecordMissedDuty_NotTracked(t *testing.T) {
+	v := &validator{}
+	v.recordMissedDuty(dutyAttestation, [48]byte{}, 1, "could not sign attestation", nil)
+	assert.Equal(t, 0, len(v.recentMissedDuties()))
+}
+
+func TestRecordMissedDuty_FailedDuties(t *testing.T) {
+	validator, _, validatorKey, finish := setup(t)
+	defer finish()
+	validator.missedDuties = newMissedDutyBuffer(maxMissedDuties)
+	validator.duties = &ethpb.DutiesResponse{Duties: []*ethpb.DutiesResponse_Duty{}}
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+
+	validator.SubmitAttestation(context.Background(), 1, pubKey)
+	validator.SubmitAggregateAndProof(context.Background(), 2, pubKey)
+
+	missed := validator.recentMissedDuties()
+	require.Equal(t, 2, len(missed))
+	assert.Equal(t, dutyAggregation, missed[0].Duty)
+	assert.Equal(t, uint64(2), missed[0].Slot)
+	assert.Equal(t, pubKey, missed[0].PublicKey)
+	assert.Equal(t, true, strings.HasPrefix(missed[0].Reason, "could not fetch validator assignment: pubkey"))
+	assert.Equal(t, dutyAttestation, missed[1].Duty)
+	assert.Equal(t, uint64(1), missed[1].Slot)
+	assert.Equal(t, false, missed[1].Time.IsZero())
+}
+
+func TestRecordMissedDuty_AggregateSelectionFailure(t *testing.T) {
+	validator, m, validatorKey, finish := setup(t)
+	defer finish()
+	validator.missedDuties = newMissedDutyBuffer(maxMissedDuties)
+	pubKey := [48]byte{}
+	copy(pubKey[:], validatorKey.PublicKey().Marshal())
+	validator.duties = &ethpb.DutiesResponse{
+		Duties: []*ethpb.DutiesResponse_Duty{
+			{
+				PublicKey: validatorKey.PublicKey().Marshal(),
+			},
+		},
+	}
+
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), // epoch
+	).Return(&ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil /*err*/).Times(2)
+	gomock.InOrder(
+		m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
+			gomock.Any(), // ctx
+			gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
+		).Return(nil, errors.New("beacon node unavailable")),
+		// Having no attestations to aggregate is not a missed duty.
+		m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
+			gomock.Any(), // ctx
+			gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
+		).Return(nil, status.Error(codes.NotFound, "no attestations to aggregate")),
+	)
+
+	validator.SubmitAggregateAndProof(context.Background(), 0, pubKey)
+	validator.SubmitAggregateAndProof(context.Background(), 1, pubKey)
+
+	missed := validator.recentMissedDuties()
+	require.Equal(t, 1, len(missed))
+	assert.Equal(t, dutyAggregation, missed[0].Duty)
+	assert.Equal(t, uint64(0), missed[0].Slot)
+	assert.Equal(t, "could not submit slot signature to beacon node: beacon node unavailable", missed[0].Reason)
+}
@@ -16,6 +16,7 @@ type FakeValidator struct {
 	WaitForWalletInitializationCalled bool
 	WaitForActivationCalled           bool
 	WaitForChainStartCalled           bool
+	CheckNetworkCalled                bool
 	WaitForSyncCalled                 bool
 	SlasherReadyCalled                bool
 	NextSlotCalled                    bool
@@ -64,6 +65,12 @@ func (fv *FakeValidator) WaitForChainStart(_ context.Context) error {
 	return nil
 }
 
+// CheckNetwork for mocking.
+func (fv *FakeValidator) CheckNetwork(_ context.Context) error {
+	fv.CheckNetworkCalled = true
+	return nil
+}
+
 // WaitForActivation for mocking.
 func (fv *FakeValidator) WaitForActivation(_ context.Context) error {
 	fv.WaitForActivationCalled = true
@@ -151,8 +158,11 @@ func (fv *FakeValidator) SubmitAggregateAndProof(_ context.Context, _ uint64, _
 // LogAttestationsSubmitted for mocking.
 func (fv *FakeValidator) LogAttestationsSubmitted() {}
 
-// UpdateDomainDataCaches for mocking.
-func (fv *FakeValidator) UpdateDomainDataCaches(context.Context, uint64) {}
+// PrefetchDomainData for mocking.
+func (fv *FakeValidator) PrefetchDomainData(context.Context) {}
+
+// MonitorClockSkew for mocking.
+func (fv *FakeValidator) MonitorClockSkew(context.Context) {}
 
 // BalancesByPubkeys for mocking.
 func (fv *FakeValidator) BalancesByPubkeys(_ context.Context) map[[48]byte]uint64 {
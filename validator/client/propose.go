@@ -41,11 +41,21 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	span.AddAttributes(trace.StringAttribute("validator", fmt.Sprintf("%#x", pubKey)))
 	log := log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:])))
 
+	if err := v.checkClockSkewSafe(); err != nil {
+		log.WithError(err).Error("Local clock is dangerously skewed, not signing")
+		v.recordMissedDuty(dutyProposal, pubKey, slot, "local clock is dangerously skewed", err)
+		if v.emitAccountMetrics {
+			ValidatorProposeFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
+	}
+
 	// Sign randao reveal, it's used to request block from beacon node
 	epoch := slot / params.BeaconConfig().SlotsPerEpoch
 	randaoReveal, err := v.signRandaoReveal(ctx, pubKey, epoch)
 	if err != nil {
 		log.WithError(err).Error("Failed to sign randao reveal")
+		v.recordMissedDuty(dutyProposal, pubKey, slot, "failed to sign randao reveal", err)
 		if v.emitAccountMetrics {
 			ValidatorProposeFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -60,6 +70,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	})
 	if err != nil {
 		log.WithField("blockSlot", slot).WithError(err).Error("Failed to request block from beacon node")
+		v.recordMissedDuty(dutyProposal, pubKey, slot, "failed to request block from beacon node", err)
 		if v.emitAccountMetrics {
 			ValidatorProposeFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -70,6 +81,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 		log.WithFields(
 			blockLogFields(pubKey, b, nil),
 		).WithError(err).Error("Failed block slashing protection check")
+		v.recordMissedDuty(dutyProposal, pubKey, slot, "failed block slashing protection check", err)
 		return
 	}
 
@@ -77,6 +89,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	sig, domain, err := v.signBlock(ctx, pubKey, epoch, b)
 	if err != nil {
 		log.WithError(err).Error("Failed to sign block")
+		v.recordMissedDuty(dutyProposal, pubKey, slot, "failed to sign block", err)
 		if v.emitAccountMetrics {
 			ValidatorProposeFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -91,6 +104,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 		log.WithFields(
 			blockLogFields(pubKey, b, sig),
 		).WithError(err).Error("Failed block slashing protection check")
+		v.recordMissedDuty(dutyProposal, pubKey, slot, "failed block slashing protection check", err)
 		return
 	}
 
@@ -98,6 +112,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	blkResp, err := v.validatorClient.ProposeBlock(ctx, blk)
 	if err != nil {
 		log.WithError(err).Error("Failed to propose block")
+		v.recordMissedDuty(dutyProposal, pubKey, slot, "failed to propose block", err)
 		if v.emitAccountMetrics {
 			ValidatorProposeFailVec.WithLabelValues(fmtKey).Inc()
 		}
@@ -190,6 +205,9 @@ func (v *validator) signRandaoReveal(ctx context.Context, pubKey [48]byte, epoch
 	if err != nil {
 		return nil, err
 	}
+	if err := v.verifySignedRoot(pubKey, root, randaoReveal); err != nil {
+		return nil, err
+	}
 	return randaoReveal.Marshal(), nil
 }
 
@@ -217,6 +235,9 @@ func (v *validator) signBlock(ctx context.Context, pubKey [48]byte, epoch uint64
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "could not sign block proposal")
 	}
+	if err := v.verifySignedRoot(pubKey, blockRoot, sig); err != nil {
+		return nil, nil, err
+	}
 	return sig.Marshal(), domain, nil
 }
 
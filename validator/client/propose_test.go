@@ -26,6 +26,7 @@ import (
 
 type mocks struct {
 	validatorClient *mock.MockBeaconNodeValidatorClient
+	beaconClient    *mock.MockBeaconChainClient
 	nodeClient      *mock.MockNodeClient
 	signExitFunc    func(context.Context, *validatorpb.SignRequest) (bls.Signature, error)
 }
@@ -41,12 +42,27 @@ func (mockSignature) AggregateVerify([]bls.PublicKey, [][32]byte) bool {
 func (mockSignature) FastAggregateVerify([]bls.PublicKey, [32]byte) bool {
 	return true
 }
+func (mockSignature) IsAggregate(int, []bls.PublicKey, [32]byte) bool {
+	return true
+}
 func (mockSignature) Marshal() []byte {
 	return make([]byte, 32)
 }
+func (mockSignature) MarshalHex() string {
+	return "0x"
+}
 func (m mockSignature) Copy() bls.Signature {
 	return m
 }
+func (mockSignature) Equals(bls.Signature) bool {
+	return true
+}
+func (mockSignature) ContributorCount() int {
+	return 1
+}
+func (mockSignature) IsInfinite() bool {
+	return false
+}
 
 func setup(t *testing.T) (*validator, *mocks, bls.SecretKey, func()) {
 	validatorKey, err := bls.RandKey()
@@ -57,6 +73,7 @@ func setup(t *testing.T) (*validator, *mocks, bls.SecretKey, func()) {
 	ctrl := gomock.NewController(t)
 	m := &mocks{
 		validatorClient: mock.NewMockBeaconNodeValidatorClient(ctrl),
+		beaconClient:    mock.NewMockBeaconChainClient(ctrl),
 		nodeClient:      mock.NewMockNodeClient(ctrl),
 		signExitFunc: func(ctx context.Context, req *validatorpb.SignRequest) (bls.Signature, error) {
 			return mockSignature{}, nil
@@ -77,6 +94,7 @@ func setup(t *testing.T) (*validator, *mocks, bls.SecretKey, func()) {
 		db:                             valDB,
 		keyManager:                     km,
 		validatorClient:                m.validatorClient,
+		beaconClient:                   m.beaconClient,
 		graffiti:                       []byte{},
 		attLogs:                        make(map[[32]byte]*attSubmitted),
 		aggregatedSlotCommitteeIDCache: aggregatedSlotCommitteeIDCache,
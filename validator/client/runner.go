@@ -6,7 +6,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -19,6 +18,7 @@ import (
 type Validator interface {
 	Done()
 	WaitForChainStart(ctx context.Context) error
+	CheckNetwork(ctx context.Context) error
 	WaitForSync(ctx context.Context) error
 	WaitForActivation(ctx context.Context) error
 	SlasherReady(ctx context.Context) error
@@ -34,7 +34,8 @@ type Validator interface {
 	SubmitAggregateAndProof(ctx context.Context, slot uint64, pubKey [48]byte)
 	LogAttestationsSubmitted()
 	ResetAttesterProtectionData()
-	UpdateDomainDataCaches(ctx context.Context, slot uint64)
+	PrefetchDomainData(ctx context.Context)
+	MonitorClockSkew(ctx context.Context)
 	WaitForWalletInitialization(ctx context.Context) error
 	AllValidatorsAreExited(ctx context.Context) (bool, error)
 }
@@ -57,6 +58,7 @@ func run(ctx context.Context, v Validator) {
 		cleanup()
 		log.Fatalf("Wallet is not ready: %v", err)
 	}
+	go v.MonitorClockSkew(ctx)
 	if featureconfig.Get().SlasherProtection {
 		if err := v.SlasherReady(ctx); err != nil {
 			log.Fatalf("Slasher is not ready: %v", err)
@@ -65,6 +67,9 @@ func run(ctx context.Context, v Validator) {
 	if err := v.WaitForChainStart(ctx); err != nil {
 		log.Fatalf("Could not determine if beacon chain started: %v", err)
 	}
+	if err := v.CheckNetwork(ctx); err != nil {
+		log.Fatalf("Beacon node is not on the validator's configured network: %v", err)
+	}
 	if err := v.WaitForSync(ctx); err != nil {
 		log.Fatalf("Could not determine if beacon node synced: %v", err)
 	}
@@ -78,6 +83,7 @@ func run(ctx context.Context, v Validator) {
 	if err := v.UpdateDuties(ctx, headSlot); err != nil {
 		handleAssignmentError(err, headSlot)
 	}
+	go v.PrefetchDomainData(ctx)
 
 	for {
 		ctx, span := trace.StartSpan(ctx, "validator.processSlot")
@@ -120,11 +126,6 @@ func run(ctx context.Context, v Validator) {
 				continue
 			}
 
-			// Start fetching domain data for the next epoch.
-			if helpers.IsEpochEnd(slot) {
-				go v.UpdateDomainDataCaches(ctx, slot+1)
-			}
-
 			var wg sync.WaitGroup
 
 			allRoles, err := v.RolesAt(ctx, slot)
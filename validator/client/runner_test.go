@@ -30,6 +30,12 @@ func TestCancelledContext_WaitsForChainStart(t *testing.T) {
 	assert.Equal(t, true, v.WaitForChainStartCalled, "Expected WaitForChainStart() to be called")
 }
 
+func TestCancelledContext_ChecksNetwork(t *testing.T) {
+	v := &FakeValidator{}
+	run(cancelledContext(), v)
+	assert.Equal(t, true, v.CheckNetworkCalled, "Expected CheckNetwork() to be called")
+}
+
 func TestCancelledContext_WaitsForActivation(t *testing.T) {
 	v := &FakeValidator{}
 	run(cancelledContext(), v)
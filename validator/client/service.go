@@ -15,15 +15,16 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/grpcutils"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
 	"github.com/prysmaticlabs/prysm/validator/accounts/wallet"
 	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
-	"github.com/prysmaticlabs/prysm/validator/keymanager/imported"
 	slashingprotection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/plugin/ocgrpc"
@@ -40,60 +41,112 @@ type SyncChecker interface {
 	Syncing(ctx context.Context) (bool, error)
 }
 
+// Keymanagers which can notify subscribers when their validating keys
+// change at runtime, such as the imported and derived keymanagers.
+type accountChangesSubscriber interface {
+	SubscribeAccountChanges(pubKeysChan chan [][48]byte) event.Subscription
+}
+
 // GenesisFetcher can retrieve genesis information such as
 // the genesis time and the validator deposit contract address.
 type GenesisFetcher interface {
 	GenesisInfo(ctx context.Context) (*ethpb.Genesis, error)
 }
 
+// DutyCountdownFetcher can report the time until the next
+// duties of each validator managed by the validator client.
+type DutyCountdownFetcher interface {
+	DutyCountdowns(ctx context.Context) ([]*DutyCountdown, error)
+}
+
+// DutyCountFetcher can count the duties the validators managed by
+// the validator client have in the current epoch.
+type DutyCountFetcher interface {
+	DutyCounts(ctx context.Context) (*DutyCounts, error)
+}
+
+// InclusionRateFetcher can compute the attestation inclusion rate of
+// each validator managed by the validator client over a window of epochs.
+type InclusionRateFetcher interface {
+	InclusionRates(ctx context.Context, numEpochs uint64) (*InclusionRateWindow, error)
+}
+
+// MissedDutyFetcher can report the duties the validator client recently missed.
+type MissedDutyFetcher interface {
+	MissedDuties(ctx context.Context) ([]*MissedDuty, error)
+}
+
+// SigningDomainFetcher can determine the signing domain of a domain type at the current epoch.
+type SigningDomainFetcher interface {
+	CurrentSigningDomain(ctx context.Context, domainType [4]byte) ([]byte, error)
+}
+
 // BeaconNodeInfoFetcher can retrieve information such as the logs endpoint
-// from a beacon node via RPC.
+// or the statuses of validators from a beacon node via RPC.
 type BeaconNodeInfoFetcher interface {
 	BeaconLogsEndpoint(ctx context.Context) (string, error)
+	ValidatorStatuses(ctx context.Context, pubKeys [][48]byte) (map[[48]byte]ethpb.ValidatorStatus, error)
 }
 
 // ValidatorService represents a service to manage the validator client
 // routine.
 type ValidatorService struct {
-	useWeb                bool
-	emitAccountMetrics    bool
-	logValidatorBalances  bool
-	conn                  *grpc.ClientConn
-	grpcRetryDelay        time.Duration
-	grpcRetries           uint
-	maxCallRecvMsgSize    int
-	walletInitializedFeed *event.Feed
-	cancel                context.CancelFunc
-	db                    db.Database
-	dataDir               string
-	withCert              string
-	endpoint              string
-	validator             Validator
-	protector             slashingprotection.Protector
-	ctx                   context.Context
-	keyManager            keymanager.IKeymanager
-	grpcHeaders           []string
-	graffiti              []byte
+	useWeb                        bool
+	emitAccountMetrics            bool
+	logValidatorBalances          bool
+	verifyKeymanagerSignatures    bool
+	slashingWarningMargin         uint64
+	conn                          *grpc.ClientConn
+	verificationConn              *grpc.ClientConn
+	verificationEndpoint          string
+	verificationCert              string
+	verificationHeadSlotTolerance uint64
+	clockSkewTimeServer           string
+	clockSkewWarningThreshold     time.Duration
+	clockSkewRefusalThreshold     time.Duration
+	grpcRetryDelay                time.Duration
+	grpcRetries                   uint
+	maxCallRecvMsgSize            int
+	walletInitializedFeed         *event.Feed
+	cancel                        context.CancelFunc
+	db                            db.Database
+	dataDir                       string
+	withCert                      string
+	endpoint                      string
+	validator                     Validator
+	protector                     slashingprotection.Protector
+	ctx                           context.Context
+	keyManager                    keymanager.IKeymanager
+	grpcHeaders                   []string
+	graffiti                      []byte
 }
 
 // Config for the validator service.
 type Config struct {
-	UseWeb                     bool
-	LogValidatorBalances       bool
-	EmitAccountMetrics         bool
-	WalletInitializedFeed      *event.Feed
-	GrpcRetriesFlag            uint
-	GrpcRetryDelay             time.Duration
-	GrpcMaxCallRecvMsgSizeFlag int
-	Protector                  slashingprotection.Protector
-	Endpoint                   string
-	Validator                  Validator
-	ValDB                      db.Database
-	KeyManager                 keymanager.IKeymanager
-	GraffitiFlag               string
-	CertFlag                   string
-	DataDir                    string
-	GrpcHeadersFlag            string
+	UseWeb                        bool
+	LogValidatorBalances          bool
+	EmitAccountMetrics            bool
+	VerifyKeymanagerSignatures    bool
+	SlashingWarningMargin         uint64
+	WalletInitializedFeed         *event.Feed
+	GrpcRetriesFlag               uint
+	GrpcRetryDelay                time.Duration
+	GrpcMaxCallRecvMsgSizeFlag    int
+	Protector                     slashingprotection.Protector
+	Endpoint                      string
+	VerificationEndpoint          string
+	VerificationCertFlag          string
+	VerificationHeadSlotTolerance uint64
+	ClockSkewTimeServer           string
+	ClockSkewWarningThreshold     time.Duration
+	ClockSkewRefusalThreshold     time.Duration
+	Validator                     Validator
+	ValDB                         db.Database
+	KeyManager                    keymanager.IKeymanager
+	GraffitiFlag                  string
+	CertFlag                      string
+	DataDir                       string
+	GrpcHeadersFlag               string
 }
 
 // NewValidatorService creates a new validator service for the service
@@ -101,24 +154,32 @@ type Config struct {
 func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	return &ValidatorService{
-		ctx:                   ctx,
-		cancel:                cancel,
-		endpoint:              cfg.Endpoint,
-		withCert:              cfg.CertFlag,
-		dataDir:               cfg.DataDir,
-		graffiti:              []byte(cfg.GraffitiFlag),
-		keyManager:            cfg.KeyManager,
-		logValidatorBalances:  cfg.LogValidatorBalances,
-		emitAccountMetrics:    cfg.EmitAccountMetrics,
-		maxCallRecvMsgSize:    cfg.GrpcMaxCallRecvMsgSizeFlag,
-		grpcRetries:           cfg.GrpcRetriesFlag,
-		grpcRetryDelay:        cfg.GrpcRetryDelay,
-		grpcHeaders:           strings.Split(cfg.GrpcHeadersFlag, ","),
-		protector:             cfg.Protector,
-		validator:             cfg.Validator,
-		db:                    cfg.ValDB,
-		walletInitializedFeed: cfg.WalletInitializedFeed,
-		useWeb:                cfg.UseWeb,
+		ctx:                           ctx,
+		cancel:                        cancel,
+		endpoint:                      cfg.Endpoint,
+		verificationEndpoint:          cfg.VerificationEndpoint,
+		verificationCert:              cfg.VerificationCertFlag,
+		verificationHeadSlotTolerance: cfg.VerificationHeadSlotTolerance,
+		clockSkewTimeServer:           cfg.ClockSkewTimeServer,
+		clockSkewWarningThreshold:     cfg.ClockSkewWarningThreshold,
+		clockSkewRefusalThreshold:     cfg.ClockSkewRefusalThreshold,
+		withCert:                      cfg.CertFlag,
+		dataDir:                       cfg.DataDir,
+		graffiti:                      []byte(cfg.GraffitiFlag),
+		keyManager:                    cfg.KeyManager,
+		logValidatorBalances:          cfg.LogValidatorBalances,
+		emitAccountMetrics:            cfg.EmitAccountMetrics,
+		verifyKeymanagerSignatures:    cfg.VerifyKeymanagerSignatures,
+		slashingWarningMargin:         cfg.SlashingWarningMargin,
+		maxCallRecvMsgSize:            cfg.GrpcMaxCallRecvMsgSizeFlag,
+		grpcRetries:                   cfg.GrpcRetriesFlag,
+		grpcRetryDelay:                cfg.GrpcRetryDelay,
+		grpcHeaders:                   strings.Split(cfg.GrpcHeadersFlag, ","),
+		protector:                     cfg.Protector,
+		validator:                     cfg.Validator,
+		db:                            cfg.ValDB,
+		walletInitializedFeed:         cfg.WalletInitializedFeed,
+		useWeb:                        cfg.UseWeb,
 	}, nil
 }
 
@@ -162,6 +223,32 @@ func (v *ValidatorService) Start() {
 	}
 
 	v.conn = conn
+
+	// Attestation data is cross-checked against an independent beacon node when one is configured.
+	var verificationValidatorClient ethpb.BeaconNodeValidatorClient
+	var verificationBeaconClient ethpb.BeaconChainClient
+	if v.verificationEndpoint != "" {
+		verificationDialOpts := ConstructDialOptions(
+			v.maxCallRecvMsgSize,
+			v.verificationCert,
+			v.grpcRetries,
+			v.grpcRetryDelay,
+			streamInterceptor,
+		)
+		if verificationDialOpts == nil {
+			return
+		}
+		verificationConn, err := grpc.DialContext(v.ctx, v.verificationEndpoint, verificationDialOpts...)
+		if err != nil {
+			log.Errorf("Could not dial verification beacon node endpoint: %s, %v", v.verificationEndpoint, err)
+			return
+		}
+		v.verificationConn = verificationConn
+		verificationValidatorClient = ethpb.NewBeaconNodeValidatorClient(verificationConn)
+		verificationBeaconClient = ethpb.NewBeaconChainClient(verificationConn)
+		log.WithField("endpoint", v.verificationEndpoint).Info("Verifying attestation data against verification beacon node")
+	}
+
 	cache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1920, // number of keys to track.
 		MaxCost:     192,  // maximum cost of cache, 1 item = 1 cost.
@@ -177,15 +264,31 @@ func (v *ValidatorService) Start() {
 		return
 	}
 
+	// The local clock is checked against a time server when one is configured.
+	var referenceClock ReferenceClock
+	if v.clockSkewTimeServer != "" {
+		referenceClock = NewNTPClock(v.clockSkewTimeServer)
+		log.WithField("timeServer", v.clockSkewTimeServer).Info("Checking the local clock against time server")
+	}
+
 	v.validator = &validator{
 		db:                             v.db,
 		validatorClient:                ethpb.NewBeaconNodeValidatorClient(v.conn),
 		beaconClient:                   ethpb.NewBeaconChainClient(v.conn),
+		verificationValidatorClient:    verificationValidatorClient,
+		verificationBeaconClient:       verificationBeaconClient,
+		verificationHeadSlotTolerance:  v.verificationHeadSlotTolerance,
 		node:                           ethpb.NewNodeClient(v.conn),
+		genesisFetcher:                 v,
+		referenceClock:                 referenceClock,
+		clockSkewWarningThreshold:      v.clockSkewWarningThreshold,
+		clockSkewRefusalThreshold:      v.clockSkewRefusalThreshold,
 		keyManager:                     v.keyManager,
 		graffiti:                       v.graffiti,
 		logValidatorBalances:           v.logValidatorBalances,
 		emitAccountMetrics:             v.emitAccountMetrics,
+		verifyKeymanagerSignatures:     v.verifyKeymanagerSignatures,
+		slashingWarningMargin:          v.slashingWarningMargin,
 		startBalances:                  make(map[[48]byte]uint64),
 		prevBalance:                    make(map[[48]byte]uint64),
 		attLogs:                        make(map[[32]byte]*attSubmitted),
@@ -195,6 +298,7 @@ func (v *ValidatorService) Start() {
 		voteStats:                      voteStats{startEpoch: ^uint64(0)},
 		useWeb:                         v.useWeb,
 		walletInitializedFeed:          v.walletInitializedFeed,
+		missedDuties:                   newMissedDutyBuffer(maxMissedDuties),
 	}
 	go run(v.ctx, v.validator)
 	go v.recheckKeys(v.ctx)
@@ -204,6 +308,11 @@ func (v *ValidatorService) Start() {
 func (v *ValidatorService) Stop() error {
 	v.cancel()
 	log.Info("Stopping service")
+	if v.verificationConn != nil {
+		if err := v.verificationConn.Close(); err != nil {
+			log.WithError(err).Error("Could not close connection to verification beacon node")
+		}
+	}
 	if v.conn != nil {
 		return v.conn.Close()
 	}
@@ -321,6 +430,55 @@ func (v *ValidatorService) GenesisInfo(ctx context.Context) (*ethpb.Genesis, err
 	return nc.GetGenesis(ctx, &ptypes.Empty{})
 }
 
+// DutyCountdowns reports the time until the next attestation, proposal and
+// aggregation duties of each validator managed by the validator client.
+func (v *ValidatorService) DutyCountdowns(ctx context.Context) ([]*DutyCountdown, error) {
+	val, ok := v.validator.(*validator)
+	if !ok || val == nil {
+		return nil, errors.New("validator client has not started")
+	}
+	return val.dutyCountdowns(timeutils.Now())
+}
+
+// DutyCounts counts the attestation, proposal, aggregation and sync committee duties
+// the validators managed by the validator client have in the current epoch.
+func (v *ValidatorService) DutyCounts(_ context.Context) (*DutyCounts, error) {
+	val, ok := v.validator.(*validator)
+	if !ok || val == nil {
+		return nil, errors.New("validator client has not started")
+	}
+	return val.dutyCounts(helpers.SlotToEpoch(helpers.CurrentSlot(val.genesisTime)))
+}
+
+// InclusionRates computes the fraction of attestations of each validator managed by the
+// validator client which were included on chain over the last numEpochs finished epochs.
+func (v *ValidatorService) InclusionRates(ctx context.Context, numEpochs uint64) (*InclusionRateWindow, error) {
+	val, ok := v.validator.(*validator)
+	if !ok || val == nil {
+		return nil, errors.New("validator client has not started")
+	}
+	return val.inclusionRates(ctx, timeutils.Now(), numEpochs)
+}
+
+// MissedDuties returns the duties the validator client most recently failed to
+// complete, most recent first.
+func (v *ValidatorService) MissedDuties(_ context.Context) ([]*MissedDuty, error) {
+	val, ok := v.validator.(*validator)
+	if !ok || val == nil {
+		return nil, errors.New("validator client has not started")
+	}
+	return val.recentMissedDuties(), nil
+}
+
+// CurrentSigningDomain returns the signing domain of the domain type at the current epoch.
+func (v *ValidatorService) CurrentSigningDomain(ctx context.Context, domainType [4]byte) ([]byte, error) {
+	val, ok := v.validator.(*validator)
+	if !ok || val == nil {
+		return nil, errors.New("validator client has not started")
+	}
+	return val.signingDomain(ctx, helpers.SlotToEpoch(helpers.CurrentSlot(val.genesisTime)), domainType)
+}
+
 // BeaconLogsEndpoint retrieves the websocket endpoint string at which
 // clients can subscribe to for beacon node logs.
 func (v *ValidatorService) BeaconLogsEndpoint(ctx context.Context) (string, error) {
@@ -332,15 +490,37 @@ func (v *ValidatorService) BeaconLogsEndpoint(ctx context.Context) (string, erro
 	return resp.BeaconLogsEndpoint, nil
 }
 
+// ValidatorStatuses retrieves the current status of each of the given validators from the beacon node.
+func (v *ValidatorService) ValidatorStatuses(ctx context.Context, pubKeys [][48]byte) (map[[48]byte]ethpb.ValidatorStatus, error) {
+	req := &ethpb.MultipleValidatorStatusRequest{
+		PublicKeys: make([][]byte, len(pubKeys)),
+	}
+	for i := range pubKeys {
+		req.PublicKeys[i] = pubKeys[i][:]
+	}
+	resp, err := ethpb.NewBeaconNodeValidatorClient(v.conn).MultipleValidatorStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.PublicKeys) != len(resp.Statuses) {
+		return nil, errors.New("number of status responses did not match number of public keys")
+	}
+	statuses := make(map[[48]byte]ethpb.ValidatorStatus, len(resp.Statuses))
+	for i, status := range resp.Statuses {
+		statuses[bytesutil.ToBytes48(resp.PublicKeys[i])] = status.Status
+	}
+	return statuses, nil
+}
+
 // to accounts changes in the keymanager, then updates those keys'
 // buckets in bolt DB if a bucket for a key does not exist.
 func recheckValidatingKeysBucket(ctx context.Context, valDB db.Database, km keymanager.IKeymanager) {
-	importedKeymanager, ok := km.(*imported.Keymanager)
+	subscriber, ok := km.(accountChangesSubscriber)
 	if !ok {
 		return
 	}
 	validatingPubKeysChan := make(chan [][48]byte, 1)
-	sub := importedKeymanager.SubscribeAccountChanges(validatingPubKeysChan)
+	sub := subscriber.SubscribeAccountChanges(validatingPubKeysChan)
 	defer sub.Unsubscribe()
 	for {
 		select {
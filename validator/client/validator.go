@@ -21,6 +21,7 @@ import (
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
@@ -37,9 +38,13 @@ import (
 )
 
 // reconnectPeriod is the frequency that we try to restart our
-// slasher connection when the slasher client connection is not ready.
+// slasher connection when the slasher client connection is not ready,
+// and to check the network of a beacon node we could not reach.
 var reconnectPeriod = 5 * time.Second
 
+// errWrongNetwork is returned by CheckNetwork when the beacon node is on another network.
+var errWrongNetwork = errors.New("the beacon node is likely running on a different network")
+
 // ValidatorRole defines the validator role.
 type ValidatorRole int8
 
@@ -54,29 +59,48 @@ type validator struct {
 	logValidatorBalances               bool
 	useWeb                             bool
 	emitAccountMetrics                 bool
+	verifyKeymanagerSignatures         bool
+	slashingWarningMargin              uint64
+	verificationHeadSlotTolerance      uint64
 	domainDataLock                     sync.Mutex
+	genesisValidatorsRootLock          sync.Mutex
+	clockSkewLock                      sync.RWMutex
 	attLogsLock                        sync.Mutex
 	aggregatedSlotCommitteeIDCacheLock sync.Mutex
 	prevBalanceLock                    sync.RWMutex
 	attesterHistoryByPubKeyLock        sync.RWMutex
+	aggregatorSlotsLock                sync.RWMutex
+	dutiesLock                         sync.RWMutex
+	signingPubKeysLock                 sync.RWMutex
 	walletInitializedFeed              *event.Feed
 	genesisTime                        uint64
+	genesisValidatorsRoot              []byte
+	clockSkew                          time.Duration
+	clockSkewWarningThreshold          time.Duration
+	clockSkewRefusalThreshold          time.Duration
 	domainDataCache                    *ristretto.Cache
 	aggregatedSlotCommitteeIDCache     *lru.Cache
 	ticker                             *slotutil.SlotTicker
 	attesterHistoryByPubKey            map[[48]byte]kv.EncHistoryData
+	aggregatorSlots                    map[[48]byte][]uint64
 	prevBalance                        map[[48]byte]uint64
+	signingPubKeys                     map[[48]byte]bls.PublicKey
 	duties                             *ethpb.DutiesResponse
 	startBalances                      map[[48]byte]uint64
 	attLogs                            map[[32]byte]*attSubmitted
 	node                               ethpb.NodeClient
+	genesisFetcher                     GenesisFetcher
+	referenceClock                     ReferenceClock
 	keyManager                         keymanager.IKeymanager
 	beaconClient                       ethpb.BeaconChainClient
 	validatorClient                    ethpb.BeaconNodeValidatorClient
+	verificationValidatorClient        ethpb.BeaconNodeValidatorClient
+	verificationBeaconClient           ethpb.BeaconChainClient
 	protector                          slashingprotection.Protector
 	db                                 vdb.Database
 	graffiti                           []byte
 	voteStats                          voteStats
+	missedDuties                       *missedDutyBuffer
 }
 
 // Done cleans up the validator.
@@ -105,6 +129,7 @@ func (v *validator) WaitForWalletInitialization(ctx context.Context) error {
 				return errors.Wrap(err, "could not read keymanager")
 			}
 			v.keyManager = keyManager
+			v.verifyKeymanagerSignatures = w.KeymanagerKind() == keymanager.Remote
 			return nil
 		case <-ctx.Done():
 			return errors.New("context canceled")
@@ -156,6 +181,9 @@ func (v *validator) WaitForChainStart(ctx context.Context) error {
 				)
 			}
 		}
+		v.genesisValidatorsRootLock.Lock()
+		v.genesisValidatorsRoot = chainStartRes.GenesisValidatorsRoot
+		v.genesisValidatorsRootLock.Unlock()
 	}
 
 	// Once the ChainStart log is received, we update the genesis time of the validator client
@@ -165,6 +193,77 @@ func (v *validator) WaitForChainStart(ctx context.Context) error {
 	return nil
 }
 
+// CheckNetwork ensures the beacon node is on the network the validator is configured
+// for by checking its genesis validators root matches the one of the configured network,
+// if known, and the signing domain it serves at the current epoch matches the one
+// computed from the locally configured fork schedule. WaitForChainStart already checked
+// the genesis validators root against the validator database, and the domain is cached
+// for the duties of the epoch, so the check does not add requests to the beacon node.
+// Signing with domains from another network would produce messages which are silently
+// rejected. Failing to reach the beacon node is retried, so only a network mismatch or
+// the context being canceled returns an error.
+func (v *validator) CheckNetwork(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "validator.CheckNetwork")
+	defer span.End()
+	err := v.checkNetwork(ctx)
+	if err == nil || errors.Is(err, errWrongNetwork) {
+		return err
+	}
+	ticker := time.NewTicker(reconnectPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			log.WithError(err).Info("Could not check the network of the beacon node. Trying again")
+			err = v.checkNetwork(ctx)
+			if err == nil || errors.Is(err, errWrongNetwork) {
+				return err
+			}
+		case <-ctx.Done():
+			return errors.New("context canceled, no longer checking the network of the beacon node")
+		}
+	}
+}
+
+// Checks the beacon node is on the configured network once. Only a mismatch
+// returns an error wrapping errWrongNetwork.
+func (v *validator) checkNetwork(ctx context.Context) error {
+	genesisValidatorsRoot, err := v.fetchGenesisValidatorsRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis validators root")
+	}
+	wantRoot := params.BeaconConfig().GenesisValidatorsRoot
+	if wantRoot != [32]byte{} && !bytes.Equal(wantRoot[:], genesisValidatorsRoot) {
+		return fmt.Errorf(
+			"beacon node genesis validators root %#x does not match root %#x of the configured %s network: %w",
+			genesisValidatorsRoot,
+			wantRoot,
+			params.BeaconConfig().NetworkName,
+			errWrongNetwork,
+		)
+	}
+	epoch := helpers.SlotToEpoch(helpers.CurrentSlot(v.genesisTime))
+	domainType := params.BeaconConfig().DomainBeaconAttester
+	res, err := v.domainData(ctx, epoch, domainType[:])
+	if err != nil {
+		return errors.Wrap(err, "could not get domain data from beacon node")
+	}
+	wantDomain, err := epochDomain(epoch, domainType, genesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not compute expected domain")
+	}
+	if !bytes.Equal(wantDomain, res.SignatureDomain) {
+		return fmt.Errorf(
+			"beacon node signing domain %#x does not match domain %#x expected at epoch %d: %w",
+			res.SignatureDomain,
+			wantDomain,
+			epoch,
+			errWrongNetwork,
+		)
+	}
+	return nil
+}
+
 // WaitForSync checks whether the beacon node has sync to the latest head.
 func (v *validator) WaitForSync(ctx context.Context) error {
 	ctx, span := trace.StartSpan(ctx, "validator.WaitForSync")
@@ -381,17 +480,22 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 	// If duties is nil it means we have had no prior duties and just started up.
 	resp, err := v.validatorClient.GetDuties(ctx, req)
 	if err != nil {
+		v.dutiesLock.Lock()
 		v.duties = nil // Clear assignments so we know to retry the request.
+		v.dutiesLock.Unlock()
 		log.Error(err)
 		return err
 	}
 
+	v.dutiesLock.Lock()
 	v.duties = resp
+	v.dutiesLock.Unlock()
 	v.logDuties(slot, v.duties.Duties)
 	subscribeSlots := make([]uint64, 0, len(validatingKeys))
 	subscribeCommitteeIDs := make([]uint64, 0, len(validatingKeys))
 	subscribeIsAggregator := make([]bool, 0, len(validatingKeys))
 	alreadySubscribed := make(map[[64]byte]bool)
+	aggregatorSlots := make(map[[48]byte][]uint64)
 
 	for _, duty := range v.duties.Duties {
 		pk := bytesutil.ToBytes48(duty.PublicKey)
@@ -410,6 +514,7 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 			}
 			if aggregator {
 				alreadySubscribed[alreadySubscribedKey] = true
+				aggregatorSlots[pk] = append(aggregatorSlots[pk], attesterSlot)
 			}
 
 			subscribeSlots = append(subscribeSlots, attesterSlot)
@@ -435,12 +540,14 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 				continue
 			}
 
-			aggregator, err := v.isAggregator(ctx, duty.Committee, attesterSlot, bytesutil.ToBytes48(duty.PublicKey))
+			pk := bytesutil.ToBytes48(duty.PublicKey)
+			aggregator, err := v.isAggregator(ctx, duty.Committee, attesterSlot, pk)
 			if err != nil {
 				return errors.Wrap(err, "could not check if a validator is an aggregator")
 			}
 			if aggregator {
 				alreadySubscribed[alreadySubscribedKey] = true
+				aggregatorSlots[pk] = append(aggregatorSlots[pk], attesterSlot)
 			}
 
 			subscribeSlots = append(subscribeSlots, attesterSlot)
@@ -449,6 +556,12 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 		}
 	}
 
+	// Aggregators are only found for the subnets subscribed to, so a validator sharing
+	// a committee with another aggregating validator is not recorded as an aggregator.
+	v.aggregatorSlotsLock.Lock()
+	v.aggregatorSlots = aggregatorSlots
+	v.aggregatorSlotsLock.Unlock()
+
 	_, err = v.validatorClient.SubscribeCommitteeSubnets(ctx, &ethpb.CommitteeSubnetsSubscribeRequest{
 		Slots:        subscribeSlots,
 		CommitteeIds: subscribeCommitteeIDs,
@@ -458,6 +571,27 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 	return err
 }
 
+// Duties are only updated by the goroutine performing the duties, so other
+// goroutines read them through this accessor.
+func (v *validator) currentDuties() *ethpb.DutiesResponse {
+	v.dutiesLock.RLock()
+	defer v.dutiesLock.RUnlock()
+	return v.duties
+}
+
+// Whether a validator was found to be an aggregator at a slot when the duties were
+// last updated. Unlike isAggregator, this does not sign a selection proof.
+func (v *validator) isCachedAggregator(pubKey [48]byte, slot uint64) bool {
+	v.aggregatorSlotsLock.RLock()
+	defer v.aggregatorSlotsLock.RUnlock()
+	for _, aggregatorSlot := range v.aggregatorSlots[pubKey] {
+		if aggregatorSlot == slot {
+			return true
+		}
+	}
+	return false
+}
+
 // RolesAt slot returns the validator roles at the given slot. Returns nil if the
 // validator is known to not have a roles at the slot. Returns UNKNOWN if the
 // validator assignments are unknown. Otherwise returns a valid ValidatorRole map.
@@ -556,25 +690,6 @@ func (v *validator) isAggregator(ctx context.Context, committee []uint64, slot u
 	return binary.LittleEndian.Uint64(b[:8])%modulo == 0, nil
 }
 
-// UpdateDomainDataCaches by making calls for all of the possible domain data. These can change when
-// the fork version changes which can happen once per epoch. Although changing for the fork version
-// is very rare, a validator should check these data every epoch to be sure the validator is
-// participating on the correct fork version.
-func (v *validator) UpdateDomainDataCaches(ctx context.Context, slot uint64) {
-	for _, d := range [][]byte{
-		params.BeaconConfig().DomainRandao[:],
-		params.BeaconConfig().DomainBeaconAttester[:],
-		params.BeaconConfig().DomainBeaconProposer[:],
-		params.BeaconConfig().DomainSelectionProof[:],
-		params.BeaconConfig().DomainAggregateAndProof[:],
-	} {
-		_, err := v.domainData(ctx, helpers.SlotToEpoch(slot), d)
-		if err != nil {
-			log.WithError(err).Errorf("Failed to update domain data for domain %v", d)
-		}
-	}
-}
-
 // AllValidatorsAreExited informs whether all validators have already exited.
 func (v *validator) AllValidatorsAreExited(ctx context.Context) (bool, error) {
 	validatingKeys, err := v.keyManager.FetchValidatingPublicKeys(ctx)
@@ -632,6 +747,46 @@ func (v *validator) domainData(ctx context.Context, epoch uint64, domain []byte)
 	return res, nil
 }
 
+// verifySignedRoot checks the signature returned by the keymanager is valid for the
+// expected signing root before it is sent out to the beacon node. Only signatures from
+// remote keymanagers are checked, as local keymanagers sign with keys the validator client
+// holds itself, which would only add a signature verification to every duty.
+func (v *validator) verifySignedRoot(pubKey [48]byte, root [32]byte, sig bls.Signature) error {
+	if !v.verifyKeymanagerSignatures {
+		return nil
+	}
+	pk, err := v.signingPubKey(pubKey)
+	if err != nil {
+		return err
+	}
+	if !bls.VerifyStrictRoot(pk, root, sig) {
+		return fmt.Errorf("signature from keymanager does not verify for signing root %#x", root)
+	}
+	return nil
+}
+
+// Returns the decompressed public key of a validator. Keys are cached so they are not
+// decompressed and validated again for every duty.
+func (v *validator) signingPubKey(pubKey [48]byte) (bls.PublicKey, error) {
+	v.signingPubKeysLock.RLock()
+	pk, ok := v.signingPubKeys[pubKey]
+	v.signingPubKeysLock.RUnlock()
+	if ok {
+		return pk, nil
+	}
+	pk, err := bls.PublicKeyFromBytes(pubKey[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert public key")
+	}
+	v.signingPubKeysLock.Lock()
+	defer v.signingPubKeysLock.Unlock()
+	if v.signingPubKeys == nil {
+		v.signingPubKeys = make(map[[48]byte]bls.PublicKey)
+	}
+	v.signingPubKeys[pubKey] = pk
+	return pk, nil
+}
+
 func (v *validator) logDuties(slot uint64, duties []*ethpb.DutiesResponse_Duty) {
 	attesterKeys := make([][]string, params.BeaconConfig().SlotsPerEpoch)
 	for i := range attesterKeys {
@@ -11,6 +11,7 @@ import (
 	ptypes "github.com/gogo/protobuf/types"
 	"github.com/golang/mock/gomock"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
 	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
@@ -18,6 +19,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
 	"github.com/prysmaticlabs/prysm/validator/db/kv"
 	dbTest "github.com/prysmaticlabs/prysm/validator/db/testing"
 	"github.com/sirupsen/logrus"
@@ -95,6 +97,124 @@ func generateMockStatusResponse(pubkeys [][]byte) *ethpb.ValidatorActivationResp
 	return &ethpb.ValidatorActivationResponse{Statuses: multipleStatus}
 }
 
+type mockGenesisFetcher struct {
+	genesis *ethpb.Genesis
+}
+
+func (m *mockGenesisFetcher) GenesisInfo(_ context.Context) (*ethpb.Genesis, error) {
+	return m.genesis, nil
+}
+
+func TestCheckNetwork(t *testing.T) {
+	genesisValidatorsRoot := bytesutil.ToBytes32([]byte("validators"))
+	otherGenesisValidatorsRoot := bytesutil.ToBytes32([]byte("other network"))
+	domainType := params.BeaconConfig().DomainBeaconAttester
+	tests := []struct {
+		name           string
+		configuredRoot [32]byte
+		beaconRoot     [32]byte
+		beaconForkVer  []byte
+		wantErr        string
+	}{
+		{
+			name:           "matching network",
+			configuredRoot: genesisValidatorsRoot,
+			beaconRoot:     genesisValidatorsRoot,
+			beaconForkVer:  params.BeaconConfig().GenesisForkVersion,
+		},
+		{
+			name:          "no configured genesis validators root",
+			beaconRoot:    genesisValidatorsRoot,
+			beaconForkVer: params.BeaconConfig().GenesisForkVersion,
+		},
+		{
+			name:           "mismatched configured genesis validators root",
+			configuredRoot: otherGenesisValidatorsRoot,
+			beaconRoot:     genesisValidatorsRoot,
+			beaconForkVer:  params.BeaconConfig().GenesisForkVersion,
+			wantErr:        "does not match root",
+		},
+		{
+			name:          "mismatched genesis validators root",
+			beaconRoot:    otherGenesisValidatorsRoot,
+			beaconForkVer: params.BeaconConfig().GenesisForkVersion,
+			wantErr:       "likely running on a different network",
+		},
+		{
+			name:           "mismatched fork version",
+			configuredRoot: genesisValidatorsRoot,
+			beaconRoot:     genesisValidatorsRoot,
+			beaconForkVer:  []byte{0xff, 0xff, 0xff, 0xff},
+			wantErr:        "likely running on a different network",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params.SetupTestConfigCleanup(t)
+			cfg := params.BeaconConfig().Copy()
+			cfg.GenesisValidatorsRoot = tt.configuredRoot
+			params.OverrideBeaconConfig(cfg)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+			// The genesis validators root is the one received by WaitForChainStart, so
+			// no genesis info is requested.
+			v := validator{
+				validatorClient:       client,
+				genesisTime:           uint64(timeutils.Now().Unix()),
+				genesisValidatorsRoot: genesisValidatorsRoot[:],
+			}
+			beaconDomain, err := helpers.ComputeDomain(domainType, tt.beaconForkVer, tt.beaconRoot[:])
+			require.NoError(t, err)
+			client.EXPECT().DomainData(
+				gomock.Any(),
+				&ethpb.DomainRequest{Epoch: 0, Domain: domainType[:]},
+			).Return(&ethpb.DomainResponse{SignatureDomain: beaconDomain}, nil).MaxTimes(1)
+
+			err = v.CheckNetwork(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckNetwork_RetriesUnreachableBeaconNode(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.GenesisValidatorsRoot = [32]byte{}
+	params.OverrideBeaconConfig(cfg)
+	defer func(period time.Duration) {
+		reconnectPeriod = period
+	}(reconnectPeriod)
+	reconnectPeriod = 10 * time.Millisecond
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	genesisValidatorsRoot := bytesutil.ToBytes32([]byte("validators"))
+	v := validator{
+		validatorClient:       client,
+		genesisTime:           uint64(timeutils.Now().Unix()),
+		genesisValidatorsRoot: genesisValidatorsRoot[:],
+	}
+	domainType := params.BeaconConfig().DomainBeaconAttester
+	domain, err := helpers.ComputeDomain(domainType, params.BeaconConfig().GenesisForkVersion, genesisValidatorsRoot[:])
+	require.NoError(t, err)
+	gomock.InOrder(
+		client.EXPECT().DomainData(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused")),
+		client.EXPECT().DomainData(gomock.Any(), gomock.Any()).Return(&ethpb.DomainResponse{SignatureDomain: domain}, nil),
+	)
+	require.NoError(t, v.CheckNetwork(context.Background()))
+
+	// Canceling the context stops the retries.
+	client.EXPECT().DomainData(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused")).AnyTimes()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorContains(t, "context canceled", v.CheckNetwork(ctx))
+}
+
 func TestWaitForChainStart_SetsGenesisInfo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -131,6 +251,7 @@ func TestWaitForChainStart_SetsGenesisInfo(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.DeepEqual(t, genesisValidatorsRoot[:], savedGenValRoot, "Unexpected saved genesis validator root")
+	assert.DeepEqual(t, genesisValidatorsRoot[:], v.genesisValidatorsRoot, "Unexpected genesis validators root")
 	assert.Equal(t, genesis, v.genesisTime, "Unexpected chain start time")
 	assert.NotNil(t, v.ticker, "Expected ticker to be set, received nil")
 
@@ -728,6 +849,73 @@ func TestUpdateDuties_OK(t *testing.T) {
 	assert.Equal(t, resp.Duties[0].ValidatorIndex, v.duties.Duties[0].ValidatorIndex, "Unexpected validator assignments")
 }
 
+func TestUpdateDuties_RecordsAggregatorsOfNewSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	slot := params.BeaconConfig().SlotsPerEpoch
+	privKey, err := bls.RandKey()
+	require.NoError(t, err)
+	pubKey := bytesutil.ToBytes48(privKey.PublicKey().Marshal())
+	// The keymanager can not sign for this key, so updating the duties fails if a
+	// selection proof is signed for it.
+	otherPubKey := [48]byte{1}
+	km := &mockKeymanager{
+		keysMap: map[[48]byte]bls.SecretKey{
+			pubKey: privKey,
+		},
+	}
+	// A single member committee always has an aggregator.
+	resp := &ethpb.DutiesResponse{
+		Duties: []*ethpb.DutiesResponse_Duty{
+			{
+				Status:         ethpb.ValidatorStatus_ACTIVE,
+				AttesterSlot:   slot,
+				CommitteeIndex: 1,
+				Committee:      []uint64{0},
+				PublicKey:      pubKey[:],
+			},
+			{
+				Status:         ethpb.ValidatorStatus_ACTIVE,
+				AttesterSlot:   slot,
+				CommitteeIndex: 1,
+				Committee:      []uint64{0},
+				PublicKey:      otherPubKey[:],
+			},
+		},
+	}
+	nextResp := &ethpb.DutiesResponse{
+		Duties: []*ethpb.DutiesResponse_Duty{
+			{
+				Status:         ethpb.ValidatorStatus_ACTIVE,
+				AttesterSlot:   2 * slot,
+				CommitteeIndex: 2,
+				Committee:      []uint64{0},
+				PublicKey:      pubKey[:],
+			},
+		},
+	}
+	v := validator{
+		keyManager:      km,
+		validatorClient: client,
+	}
+	client.EXPECT().GetDuties(gomock.Any(), gomock.Any()).Return(resp, nil)
+	client.EXPECT().GetDuties(gomock.Any(), gomock.Any()).Return(nextResp, nil)
+	client.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), // epoch
+	).Return(&ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil).AnyTimes()
+	client.EXPECT().SubscribeCommitteeSubnets(gomock.Any(), &ethpb.CommitteeSubnetsSubscribeRequest{
+		Slots:        []uint64{slot, 2 * slot},
+		CommitteeIds: []uint64{1, 2},
+		IsAggregator: []bool{true, true},
+	}).Return(nil, nil)
+
+	require.NoError(t, v.UpdateDuties(context.Background(), slot))
+	assert.DeepEqual(t, map[[48]byte][]uint64{pubKey: {slot, 2 * slot}}, v.aggregatorSlots)
+}
+
 func TestUpdateProtections_OK(t *testing.T) {
 	ctx := context.Background()
 	pubKey1 := [48]byte{1}
@@ -1081,3 +1269,24 @@ func TestAllValidatorsAreExited_CorrectRequest(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, false, exited)
 }
+
+func TestVerifySignedRoot(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	otherKey, err := bls.RandKey()
+	require.NoError(t, err)
+	var pubKey [48]byte
+	copy(pubKey[:], secretKey.PublicKey().Marshal())
+	root := [32]byte{'a'}
+
+	// Signatures from local keymanagers are not checked.
+	v := &validator{}
+	require.NoError(t, v.verifySignedRoot(pubKey, root, otherKey.Sign(root[:])))
+
+	v.verifyKeymanagerSignatures = true
+	require.NoError(t, v.verifySignedRoot(pubKey, root, secretKey.Sign(root[:])))
+	assert.ErrorContains(t, "does not verify", v.verifySignedRoot(pubKey, root, otherKey.Sign(root[:])))
+	assert.ErrorContains(t, "does not verify", v.verifySignedRoot(pubKey, [32]byte{'b'}, secretKey.Sign(root[:])))
+	// The decompressed public key is cached after the first verification.
+	assert.Equal(t, 1, len(v.signingPubKeys))
+}
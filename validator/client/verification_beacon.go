@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// Cross-checks attestation data fetched from the beacon node against the independent
+// verification beacon node, when one is configured, before the data is signed.
+//
+// The beacon nodes must agree on the target checkpoint. They may briefly disagree on
+// the head while one of them has not yet processed the latest blocks, so a differing
+// head block root is tolerated as long as the verification beacon node knows the block
+// and it is at most the configured number of slots behind its own head.
+func (v *validator) verifyAttestationData(ctx context.Context, req *ethpb.AttestationDataRequest, data *ethpb.AttestationData) error {
+	if v.verificationValidatorClient == nil {
+		return nil
+	}
+	remoteData, err := v.verificationValidatorClient.GetAttestationData(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "could not request attestation data from verification beacon node")
+	}
+	if !checkpointsEqual(data.Target, remoteData.Target) {
+		return fmt.Errorf(
+			"beacon nodes disagree on the target checkpoint: %s, verification beacon node has %s",
+			checkpointString(data.Target),
+			checkpointString(remoteData.Target),
+		)
+	}
+	if bytes.Equal(data.BeaconBlockRoot, remoteData.BeaconBlockRoot) {
+		return nil
+	}
+
+	blocks, err := v.verificationBeaconClient.ListBlocks(ctx, &ethpb.ListBlocksRequest{
+		QueryFilter: &ethpb.ListBlocksRequest_Root{Root: data.BeaconBlockRoot},
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not request head block from verification beacon node")
+	}
+	if len(blocks.BlockContainers) == 0 || blocks.BlockContainers[0].Block == nil || blocks.BlockContainers[0].Block.Block == nil {
+		return fmt.Errorf("verification beacon node does not know head block %#x", data.BeaconBlockRoot)
+	}
+	headSlot := blocks.BlockContainers[0].Block.Block.Slot
+	remoteHead, err := v.verificationBeaconClient.GetChainHead(ctx, &ptypes.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "could not request chain head from verification beacon node")
+	}
+	if remoteHead.HeadSlot > headSlot+v.verificationHeadSlotTolerance {
+		return fmt.Errorf(
+			"head block at slot %d is more than %d slots behind the verification beacon node head at slot %d",
+			headSlot,
+			v.verificationHeadSlotTolerance,
+			remoteHead.HeadSlot,
+		)
+	}
+	return nil
+}
+
+func checkpointsEqual(a, b *ethpb.Checkpoint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Epoch == b.Epoch && bytes.Equal(a.Root, b.Root)
+}
+
+func checkpointString(c *ethpb.Checkpoint) string {
+	if c == nil {
+		return "no checkpoint"
+	}
+	return fmt.Sprintf("epoch %d root %#x", c.Epoch, c.Root)
+}
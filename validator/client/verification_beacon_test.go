@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+type verificationMocks struct {
+	validatorClient *mock.MockBeaconNodeValidatorClient
+	beaconClient    *mock.MockBeaconChainClient
+}
+
+// Configures the validator to verify attestation data against a mocked verification beacon node.
+func setupVerificationBeacon(t *testing.T, v *validator) (*verificationMocks, func()) {
+	ctrl := gomock.NewController(t)
+	m := &verificationMocks{
+		validatorClient: mock.NewMockBeaconNodeValidatorClient(ctrl),
+		beaconClient:    mock.NewMockBeaconChainClient(ctrl),
+	}
+	v.verificationValidatorClient = m.validatorClient
+	v.verificationBeaconClient = m.beaconClient
+	v.verificationHeadSlotTolerance = 1
+	return m, ctrl.Finish
+}
+
+func testAttestationData(head, target string) *ethpb.AttestationData {
+	headRoot := bytesutil.ToBytes32([]byte(head))
+	targetRoot := bytesutil.ToBytes32([]byte(target))
+	sourceRoot := bytesutil.ToBytes32([]byte("source"))
+	return &ethpb.AttestationData{
+		Slot:            30,
+		BeaconBlockRoot: headRoot[:],
+		Target:          &ethpb.Checkpoint{Root: targetRoot[:], Epoch: 4},
+		Source:          &ethpb.Checkpoint{Root: sourceRoot[:], Epoch: 3},
+	}
+}
+
+func TestSubmitAttestation_VerificationBeacon(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteData *ethpb.AttestationData
+		signed     bool
+	}{
+		{
+			name:       "beacon nodes agree",
+			remoteData: testAttestationData("head", "target"),
+			signed:     true,
+		},
+		{
+			name:       "beacon nodes disagree on target",
+			remoteData: testAttestationData("head", "other target"),
+			signed:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := logTest.NewGlobal()
+			validator, m, validatorKey, finish := setup(t)
+			defer finish()
+			remote, remoteFinish := setupVerificationBeacon(t, validator)
+			defer remoteFinish()
+			validator.missedDuties = newMissedDutyBuffer(maxMissedDuties)
+			validatorIndex := uint64(7)
+			pubKey := [48]byte{}
+			copy(pubKey[:], validatorKey.PublicKey().Marshal())
+			validator.duties = &ethpb.DutiesResponse{Duties: []*ethpb.DutiesResponse_Duty{
+				{
+					PublicKey:      validatorKey.PublicKey().Marshal(),
+					CommitteeIndex: 5,
+					Committee:      []uint64{0, validatorIndex},
+					ValidatorIndex: validatorIndex,
+				},
+			}}
+
+			m.validatorClient.EXPECT().GetAttestationData(
+				gomock.Any(), // ctx
+				gomock.AssignableToTypeOf(&ethpb.AttestationDataRequest{}),
+			).Return(testAttestationData("head", "target"), nil)
+			remote.validatorClient.EXPECT().GetAttestationData(
+				gomock.Any(), // ctx
+				&ethpb.AttestationDataRequest{Slot: 30, CommitteeIndex: 5},
+			).Return(tt.remoteData, nil)
+			if tt.signed {
+				m.validatorClient.EXPECT().DomainData(
+					gomock.Any(), // ctx
+					gomock.Any(), // epoch
+				).Times(2).Return(&ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil /*err*/)
+				m.validatorClient.EXPECT().ProposeAttestation(
+					gomock.Any(), // ctx
+					gomock.AssignableToTypeOf(&ethpb.Attestation{}),
+				).Return(&ethpb.AttestResponse{}, nil /* error */)
+			}
+
+			validator.SubmitAttestation(context.Background(), 30, pubKey)
+
+			missed := validator.recentMissedDuties()
+			if tt.signed {
+				require.LogsDoNotContain(t, hook, "not signing")
+				assert.Equal(t, 0, len(missed))
+			} else {
+				require.LogsContain(t, hook, "beacon nodes disagree on the target checkpoint")
+				require.LogsContain(t, hook, "not signing")
+				require.Equal(t, 1, len(missed))
+				assert.Equal(t, dutyAttestation, missed[0].Duty)
+			}
+		})
+	}
+}
+
+func TestVerifyAttestationData_HeadSlotTolerance(t *testing.T) {
+	data := testAttestationData("head", "target")
+	tests := []struct {
+		name           string
+		knownHeadBlock bool
+		remoteHeadSlot uint64
+		wantErr        string
+	}{
+		{
+			name:           "head block within tolerance",
+			knownHeadBlock: true,
+			remoteHeadSlot: 30,
+		},
+		{
+			name:           "head block too far behind",
+			knownHeadBlock: true,
+			remoteHeadSlot: 31,
+			wantErr:        "head block at slot 29 is more than 1 slots behind the verification beacon node head at slot 31",
+		},
+		{
+			name:    "head block unknown",
+			wantErr: "verification beacon node does not know head block",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, _, _, finish := setup(t)
+			defer finish()
+			remote, remoteFinish := setupVerificationBeacon(t, validator)
+			defer remoteFinish()
+			req := &ethpb.AttestationDataRequest{Slot: 30, CommitteeIndex: 5}
+
+			remote.validatorClient.EXPECT().GetAttestationData(
+				gomock.Any(), // ctx
+				req,
+			).Return(testAttestationData("other head", "target"), nil)
+			blocks := &ethpb.ListBlocksResponse{}
+			if tt.knownHeadBlock {
+				blocks.BlockContainers = []*ethpb.BeaconBlockContainer{
+					{
+						Block:     &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 29}},
+						BlockRoot: data.BeaconBlockRoot,
+					},
+				}
+				remote.beaconClient.EXPECT().GetChainHead(
+					gomock.Any(), // ctx
+					gomock.Any(), // empty
+				).Return(&ethpb.ChainHead{HeadSlot: tt.remoteHeadSlot}, nil)
+			}
+			remote.beaconClient.EXPECT().ListBlocks(
+				gomock.Any(), // ctx
+				&ethpb.ListBlocksRequest{QueryFilter: &ethpb.ListBlocksRequest_Root{Root: data.BeaconBlockRoot}},
+			).Return(blocks, nil)
+
+			err := validator.verifyAttestationData(context.Background(), req, data)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyAttestationData_NoVerificationBeacon(t *testing.T) {
+	validator, _, _, finish := setup(t)
+	defer finish()
+	err := validator.verifyAttestationData(context.Background(), &ethpb.AttestationDataRequest{}, testAttestationData("head", "target"))
+	assert.NoError(t, err)
+}
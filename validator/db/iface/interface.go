@@ -35,4 +35,7 @@ type ValidatorDB interface {
 	SaveAttestationHistoryForPubKeysV2(ctx context.Context, historyByPubKeys map[[48]byte]kv.EncHistoryData) error
 	SaveAttestationHistoryForPubKeyV2(ctx context.Context, pubKey [48]byte, history kv.EncHistoryData) error
 	AttestedPublicKeys(ctx context.Context) ([][48]byte, error)
+
+	// Slashing protection auditing methods.
+	SlashingProtectionInconsistencies(ctx context.Context, publicKey [48]byte) ([]string, error)
 }
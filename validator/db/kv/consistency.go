@@ -0,0 +1,154 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// SlashingProtectionInconsistencies scans the proposal and attestation history of a validator
+// public key, returning a description of every record which is inconsistent with the others.
+// Records are never inconsistent when written by the validator client, so inconsistencies
+// indicate a corrupted database, which could make the validator miss a duty or sign a
+// slashable message.
+func (store *Store) SlashingProtectionInconsistencies(ctx context.Context, publicKey [48]byte) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.SlashingProtectionInconsistencies")
+	defer span.End()
+
+	inconsistencies := make([]string, 0)
+	err := store.view(func(tx *bolt.Tx) error {
+		inconsistencies = append(inconsistencies, proposalInconsistencies(tx, publicKey)...)
+		found, err := attestationInconsistencies(ctx, tx, publicKey)
+		if err != nil {
+			return err
+		}
+		inconsistencies = append(inconsistencies, found...)
+		return nil
+	})
+	return inconsistencies, err
+}
+
+// Checks the proposals of a public key, which are stored by slot, against its lowest and
+// highest signed proposal slots. Pruning only removes the oldest proposals, so the highest
+// signed proposal is always in the history.
+func proposalInconsistencies(tx *bolt.Tx, publicKey [48]byte) []string {
+	inconsistencies := make([]string, 0)
+	lowest, hasLowest := uint64FromBucket(tx.Bucket(lowestSignedProposalsBucket), publicKey)
+	highest, hasHighest := uint64FromBucket(tx.Bucket(highestSignedProposalsBucket), publicKey)
+	var minSlot, maxSlot uint64
+	numProposals := 0
+	if valBucket := tx.Bucket(newHistoricProposalsBucket).Bucket(publicKey[:]); valBucket != nil {
+		c := valBucket.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if len(k) != uint64Size {
+				inconsistencies = append(inconsistencies, fmt.Sprintf("proposal history key %#x is not a slot", k))
+				continue
+			}
+			slot := bytesutil.BytesToUint64BigEndian(k)
+			if len(v) != 0 && len(v) != signingRootSize {
+				inconsistencies = append(inconsistencies, fmt.Sprintf(
+					"proposal at slot %d has a signing root of %d bytes", slot, len(v),
+				))
+			}
+			// Keys are big endian, so the cursor visits slots in increasing order.
+			if numProposals == 0 {
+				minSlot = slot
+			}
+			maxSlot = slot
+			numProposals++
+		}
+	}
+	if numProposals == 0 {
+		if hasLowest || hasHighest {
+			inconsistencies = append(inconsistencies, "signed proposal slots are recorded without any proposal history")
+		}
+		return inconsistencies
+	}
+	if !hasLowest {
+		inconsistencies = append(inconsistencies, "no lowest signed proposal slot is recorded")
+	} else if lowest > minSlot {
+		inconsistencies = append(inconsistencies, fmt.Sprintf(
+			"lowest signed proposal slot %d is after the proposal at slot %d", lowest, minSlot,
+		))
+	}
+	if !hasHighest {
+		inconsistencies = append(inconsistencies, "no highest signed proposal slot is recorded")
+	} else if highest != maxSlot {
+		inconsistencies = append(inconsistencies, fmt.Sprintf(
+			"highest signed proposal slot %d does not match the latest proposal at slot %d", highest, maxSlot,
+		))
+	}
+	return inconsistencies
+}
+
+// Checks the attestation history of a public key over the latest weak subjectivity period,
+// which is all the history keeps, against its lowest signed source and target epochs.
+func attestationInconsistencies(ctx context.Context, tx *bolt.Tx, publicKey [48]byte) ([]string, error) {
+	inconsistencies := make([]string, 0)
+	enc := tx.Bucket(newHistoricAttestationsBucket).Get(publicKey[:])
+	if enc == nil {
+		return inconsistencies, nil
+	}
+	history := EncHistoryData(enc)
+	if err := history.assertSize(); err != nil {
+		return append(inconsistencies, fmt.Sprintf("attestation history is malformed: %v", err)), nil
+	}
+	latestEpochWritten, err := history.GetLatestEpochWritten(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lowestSource, hasLowestSource := uint64FromBucket(tx.Bucket(lowestSignedSourceBucket), publicKey)
+	lowestTarget, hasLowestTarget := uint64FromBucket(tx.Bucket(lowestSignedTargetBucket), publicKey)
+
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	oldestTarget := uint64(0)
+	if latestEpochWritten >= wsPeriod {
+		oldestTarget = latestEpochWritten - wsPeriod + 1
+	}
+	for target := oldestTarget; target <= latestEpochWritten; target++ {
+		data, err := history.GetTargetData(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		if data.IsEmpty() {
+			// The latest epoch written is only moved forward when attesting for it.
+			if target == latestEpochWritten && target != 0 {
+				inconsistencies = append(inconsistencies, fmt.Sprintf(
+					"no attestation is recorded for the latest written target epoch %d", target,
+				))
+			}
+			continue
+		}
+		if data.Source > target {
+			inconsistencies = append(inconsistencies, fmt.Sprintf(
+				"attestation for target epoch %d has a later source epoch %d", target, data.Source,
+			))
+		}
+		if hasLowestSource && data.Source < lowestSource {
+			inconsistencies = append(inconsistencies, fmt.Sprintf(
+				"attestation for target epoch %d has source epoch %d before the lowest signed source epoch %d",
+				target, data.Source, lowestSource,
+			))
+		}
+		if hasLowestTarget && target < lowestTarget {
+			inconsistencies = append(inconsistencies, fmt.Sprintf(
+				"attestation for target epoch %d is before the lowest signed target epoch %d", target, lowestTarget,
+			))
+		}
+	}
+	return inconsistencies, nil
+}
+
+// Reads the big endian uint64 stored under a public key, reporting whether one is stored.
+func uint64FromBucket(bucket *bolt.Bucket, publicKey [48]byte) (uint64, bool) {
+	enc := bucket.Get(publicKey[:])
+	// 8 because bytesutil.BytesToUint64BigEndian will return 0 if input is less than 8 bytes.
+	if len(enc) < 8 {
+		return 0, false
+	}
+	return bytesutil.BytesToUint64BigEndian(enc), true
+}
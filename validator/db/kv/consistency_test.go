@@ -0,0 +1,121 @@
+package kv
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Records the history of a validator which proposed and attested in a few epochs.
+func saveConsistentHistory(t *testing.T, db *Store, pubKey [48]byte) {
+	ctx := context.Background()
+	signingRoot := bytesutil.PadTo([]byte{1}, 32)
+	for _, slot := range []uint64{10, 40, 70} {
+		require.NoError(t, db.SaveProposalHistoryForSlot(ctx, pubKey, slot, signingRoot))
+	}
+	history := NewAttestationHistoryArray(0)
+	for _, target := range []uint64{2, 3, 5} {
+		var err error
+		history, err = MarkAllAsAttestedSinceLatestWrittenEpoch(ctx, history, target, &HistoryData{
+			Source:      target - 1,
+			SigningRoot: signingRoot,
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.SaveLowestSignedSourceEpoch(ctx, pubKey, target-1))
+		require.NoError(t, db.SaveLowestSignedTargetEpoch(ctx, pubKey, target))
+	}
+	require.NoError(t, db.SaveAttestationHistoryForPubKeyV2(ctx, pubKey, history))
+}
+
+func TestStore_SlashingProtectionInconsistencies_Consistent(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	db := setupDB(t, [][48]byte{pubKey})
+
+	inconsistencies, err := db.SlashingProtectionInconsistencies(ctx, pubKey)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(inconsistencies), "A validator without history is inconsistent: %v", inconsistencies)
+
+	saveConsistentHistory(t, db, pubKey)
+	inconsistencies, err = db.SlashingProtectionInconsistencies(ctx, pubKey)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(inconsistencies), "Consistent history is inconsistent: %v", inconsistencies)
+}
+
+func TestStore_SlashingProtectionInconsistencies_CorruptedProposals(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	db := setupDB(t, [][48]byte{pubKey})
+	saveConsistentHistory(t, db, pubKey)
+
+	// Lose the latest proposal, truncate the signing root of another one and
+	// record a lowest signed proposal after the earliest one.
+	require.NoError(t, db.update(func(tx *bolt.Tx) error {
+		valBucket := tx.Bucket(newHistoricProposalsBucket).Bucket(pubKey[:])
+		if err := valBucket.Delete(bytesutil.Uint64ToBytesBigEndian(70)); err != nil {
+			return err
+		}
+		if err := valBucket.Put(bytesutil.Uint64ToBytesBigEndian(40), []byte{1, 2, 3}); err != nil {
+			return err
+		}
+		return tx.Bucket(lowestSignedProposalsBucket).Put(pubKey[:], bytesutil.Uint64ToBytesBigEndian(20))
+	}))
+	inconsistencies, err := db.SlashingProtectionInconsistencies(ctx, pubKey)
+	require.NoError(t, err)
+	assert.DeepEqual(t, []string{
+		"proposal at slot 40 has a signing root of 3 bytes",
+		"lowest signed proposal slot 20 is after the proposal at slot 10",
+		"highest signed proposal slot 70 does not match the latest proposal at slot 40",
+	}, inconsistencies)
+
+	// Losing the whole proposal history leaves the signed proposal slots behind.
+	require.NoError(t, db.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(newHistoricProposalsBucket).DeleteBucket(pubKey[:])
+	}))
+	inconsistencies, err = db.SlashingProtectionInconsistencies(ctx, pubKey)
+	require.NoError(t, err)
+	assert.DeepEqual(t, []string{"signed proposal slots are recorded without any proposal history"}, inconsistencies)
+}
+
+func TestStore_SlashingProtectionInconsistencies_CorruptedAttestations(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	db := setupDB(t, [][48]byte{pubKey})
+	saveConsistentHistory(t, db, pubKey)
+
+	history, err := db.AttestationHistoryForPubKeysV2(ctx, [][48]byte{pubKey})
+	require.NoError(t, err)
+	corrupted := history[pubKey]
+	// A source after its target, a target before the lowest signed target and a gap at the
+	// latest written target epoch.
+	corrupted, err = corrupted.SetTargetData(ctx, 3, &HistoryData{Source: 4, SigningRoot: make([]byte, 32)})
+	require.NoError(t, err)
+	corrupted, err = corrupted.SetTargetData(ctx, 1, &HistoryData{Source: 0, SigningRoot: make([]byte, 32)})
+	require.NoError(t, err)
+	corrupted, err = corrupted.SetTargetData(ctx, 5, emptyHistoryData())
+	require.NoError(t, err)
+	require.NoError(t, db.SaveAttestationHistoryForPubKeyV2(ctx, pubKey, corrupted))
+
+	inconsistencies, err := db.SlashingProtectionInconsistencies(ctx, pubKey)
+	require.NoError(t, err)
+	assert.DeepEqual(t, []string{
+		"attestation for target epoch 1 has source epoch 0 before the lowest signed source epoch 1",
+		"attestation for target epoch 1 is before the lowest signed target epoch 2",
+		"attestation for target epoch 3 has a later source epoch 4",
+		"no attestation is recorded for the latest written target epoch 5",
+	}, inconsistencies)
+
+	// A truncated history can not be scanned at all.
+	require.NoError(t, db.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(newHistoricAttestationsBucket).Put(pubKey[:], []byte{1, 2, 3})
+	}))
+	inconsistencies, err = db.SlashingProtectionInconsistencies(ctx, pubKey)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(inconsistencies))
+	assert.Equal(t, true, strings.Contains(inconsistencies[0], "attestation history is malformed"), inconsistencies[0])
+}
@@ -145,7 +145,7 @@ func MarkAllAsAttestedSinceLatestWrittenEpoch(
 		// Limit the overwriting to one weak subjectivity period as further is not needed.
 		maxToWrite := latestEpochWritten + wsPeriod
 		for i := latestEpochWritten + 1; i < incomingTarget && i <= maxToWrite; i++ {
-			newHD, err := hist.SetTargetData(ctx, i%wsPeriod, &HistoryData{
+			newHD, err := currentHD.SetTargetData(ctx, i%wsPeriod, &HistoryData{
 				Source: params.BeaconConfig().FarFutureEpoch,
 			})
 			if err != nil {
@@ -125,3 +125,25 @@ func TestSetTargetData(t *testing.T) {
 	}
 
 }
+
+func TestMarkAllAsAttestedSinceLatestWrittenEpoch(t *testing.T) {
+	ctx := context.Background()
+	signingRoot := bytesutil.PadTo([]byte{1}, 32)
+	hd, err := MarkAllAsAttestedSinceLatestWrittenEpoch(
+		ctx, NewAttestationHistoryArray(0), 4, &HistoryData{Source: 2, SigningRoot: signingRoot},
+	)
+	require.NoError(t, err)
+	latestEpochWritten, err := hd.GetLatestEpochWritten(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), latestEpochWritten)
+
+	// Every skipped target is marked as not attested for.
+	for target := uint64(0); target < 4; target++ {
+		td, err := hd.GetTargetData(ctx, target)
+		require.NoError(t, err)
+		assert.Equal(t, true, td.IsEmpty(), "Target %d is not empty", target)
+	}
+	td, err := hd.GetTargetData(ctx, 4)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &HistoryData{Source: 2, SigningRoot: signingRoot}, td)
+}
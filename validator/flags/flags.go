@@ -16,6 +16,9 @@ const (
 	WalletDefaultDirName = "prysm-wallet-v2"
 	// DefaultGatewayHost for the validator client.
 	DefaultGatewayHost = "127.0.0.1"
+	// DefaultMaxWalletSize is the default maximum number of keys a wallet may hold
+	// when importing keystores via the validator RPC server.
+	DefaultMaxWalletSize = 10000
 )
 
 var (
@@ -26,12 +29,60 @@ var (
 			"of validating keys may wish to disable granular prometheus metrics as it increases " +
 			"the data cardinality.",
 	}
+	// SlashingWarningMarginFlag defines how close, in epochs, an attestation may come to a surround
+	// vote with the local slashing protection history before a warning is logged.
+	SlashingWarningMarginFlag = &cli.Uint64Flag{
+		Name: "slashing-warning-margin",
+		Usage: "Logs a warning and increments a metric when an attestation is within this many epochs " +
+			"of a surround vote with respect to the local slashing protection history. " +
+			"Signing is not blocked. 0 disables the warning.",
+		Value: 0,
+	}
 	// BeaconRPCProviderFlag defines a beacon node RPC endpoint.
 	BeaconRPCProviderFlag = &cli.StringFlag{
 		Name:  "beacon-rpc-provider",
 		Usage: "Beacon node RPC provider endpoint",
 		Value: "127.0.0.1:4000",
 	}
+	// VerificationBeaconRPCProviderFlag defines an independent beacon node RPC endpoint which
+	// attestation data is cross-checked against before it is signed.
+	VerificationBeaconRPCProviderFlag = &cli.StringFlag{
+		Name: "verification-beacon-rpc-provider",
+		Usage: "Optional RPC endpoint of an independent beacon node. Attestation data fetched from the " +
+			"beacon-rpc-provider is only signed when this beacon node agrees with it",
+	}
+	// VerificationBeaconCertFlag defines a flag for the verification beacon node's TLS certificate.
+	VerificationBeaconCertFlag = &cli.StringFlag{
+		Name:  "verification-beacon-tls-cert",
+		Usage: "Certificate for secure gRPC with the verification beacon node",
+	}
+	// VerificationHeadSlotToleranceFlag defines how many slots the head block of the beacon node may
+	// be behind the head of the verification beacon node when their head block roots differ.
+	VerificationHeadSlotToleranceFlag = &cli.Uint64Flag{
+		Name: "verification-head-slot-tolerance",
+		Usage: "When the beacon nodes disagree on the head block, the number of slots the head block being " +
+			"attested to may be behind the verification beacon node head before signing is refused",
+		Value: 1,
+	}
+	// ClockSkewTimeServerFlag defines an NTP server the local clock is periodically checked against.
+	ClockSkewTimeServerFlag = &cli.StringFlag{
+		Name: "clock-skew-time-server",
+		Usage: "Optional address of an NTP time server, such as pool.ntp.org:123, the local clock is checked " +
+			"against at startup and periodically. Duties are timed by the local clock, so a skewed clock can " +
+			"cause them to be missed",
+	}
+	// ClockSkewWarningThresholdFlag defines the skew of the local clock at which a warning is logged.
+	ClockSkewWarningThresholdFlag = &cli.DurationFlag{
+		Name:  "clock-skew-warning-threshold",
+		Usage: "Skew of the local clock from the clock-skew-time-server at which a warning is logged",
+		Value: 500 * time.Millisecond,
+	}
+	// ClockSkewRefusalThresholdFlag defines the skew of the local clock at which the validator refuses to sign.
+	ClockSkewRefusalThresholdFlag = &cli.DurationFlag{
+		Name:  "clock-skew-refusal-threshold",
+		Usage: "Skew of the local clock from the clock-skew-time-server at which the validator refuses to sign. 0 never refuses",
+		Value: 4 * time.Second,
+	}
 	// BeaconRPCGatewayProviderFlag defines a beacon node JSON-RPC endpoint.
 	BeaconRPCGatewayProviderFlag = &cli.StringFlag{
 		Name:  "beacon-rpc-gateway-provider",
@@ -61,6 +112,91 @@ var (
 		Usage: "RPC port exposed by a validator client",
 		Value: 7000,
 	}
+	// RPCUnixSocketFlag defines a unix socket the RPC server listens on instead of a TCP port.
+	RPCUnixSocketFlag = &cli.StringFlag{
+		Name: "rpc-unix-socket",
+		Usage: "Path of a unix socket the RPC server listens on instead of the rpc-host and rpc-port, so only " +
+			"the user running the validator can connect. The directory of the socket is created if needed, and " +
+			"must not be accessible by other users. The web UI gateway connects over TCP, so it can not be used together with this flag",
+	}
+	// RPCMaxConnectionIdleFlag defines how long an idle connection to the RPC server is kept open.
+	RPCMaxConnectionIdleFlag = &cli.DurationFlag{
+		Name:  "rpc-max-connection-idle",
+		Usage: "Closes connections to the RPC server which have had no active calls for this long. Disabled when 0",
+	}
+	// RPCShutdownTimeoutFlag defines how long the RPC server waits for in flight requests when shutting down.
+	RPCShutdownTimeoutFlag = &cli.DurationFlag{
+		Name:  "rpc-shutdown-timeout",
+		Usage: "How long the RPC server waits for in flight requests to complete when shutting down before closing them",
+		Value: 5 * time.Second,
+	}
+	// RPCMaxMsgSizeFlag defines the maximum size of a message sent or received by the RPC server.
+	RPCMaxMsgSizeFlag = &cli.IntFlag{
+		Name:  "rpc-max-msg-size",
+		Usage: "Maximum size in bytes of a message sent or received by the RPC server, such as a batch of imported keystores",
+		Value: 32 << 20,
+	}
+	// RPCKeepaliveTimeFlag defines how long a connection to the RPC server may be silent before it is pinged.
+	RPCKeepaliveTimeFlag = &cli.DurationFlag{
+		Name:  "rpc-keepalive-time",
+		Usage: "How long a connection to the RPC server may be silent before the server pings the client",
+		Value: time.Minute,
+	}
+	// RPCKeepaliveTimeoutFlag defines how long the RPC server waits for a ping to be acknowledged.
+	RPCKeepaliveTimeoutFlag = &cli.DurationFlag{
+		Name:  "rpc-keepalive-timeout",
+		Usage: "How long the RPC server waits for a ping to be acknowledged before closing the connection",
+		Value: 20 * time.Second,
+	}
+	// RPCRateLimitFlag defines how many requests per second a client may send to each RPC server method.
+	RPCRateLimitFlag = &cli.Float64Flag{
+		Name:  "rpc-rate-limit",
+		Usage: "Requests per second each client may send to each method of the RPC server",
+		Value: 20,
+	}
+	// RPCRateLimitBurstFlag defines how many requests a client may send to each RPC server method in a burst.
+	RPCRateLimitBurstFlag = &cli.Int64Flag{
+		Name:  "rpc-rate-limit-burst",
+		Usage: "Requests each client may send to each method of the RPC server in a burst",
+		Value: 40,
+	}
+	// RPCUnauthenticatedRateLimitFlag defines how many requests per second a client may send to
+	// the RPC server methods which do not require authentication.
+	RPCUnauthenticatedRateLimitFlag = &cli.Float64Flag{
+		Name:  "rpc-unauthenticated-rate-limit",
+		Usage: "Requests per second each client may send across the methods of the RPC server which do not require authentication, such as logging in",
+		Value: 1,
+	}
+	// RPCUnauthenticatedRateLimitBurstFlag defines how many requests a client may send to the
+	// RPC server methods which do not require authentication in a burst.
+	RPCUnauthenticatedRateLimitBurstFlag = &cli.Int64Flag{
+		Name:  "rpc-unauthenticated-rate-limit-burst",
+		Usage: "Requests each client may send across the methods of the RPC server which do not require authentication in a burst",
+		Value: 10,
+	}
+	// DisableRPCRateLimitFlag disables rate limiting the methods of the RPC server.
+	DisableRPCRateLimitFlag = &cli.BoolFlag{
+		Name: "disable-rpc-rate-limit",
+		Usage: "Disables rate limiting the methods of the RPC server, including logging in. " +
+			"Only use this when the RPC server is not reachable by untrusted clients",
+	}
+	// RPCCertFlag defines a flag for the TLS certificate served by the RPC server.
+	RPCCertFlag = &cli.StringFlag{
+		Name: "rpc-tls-cert",
+		Usage: "Certificate served by the RPC server. Pass this and the rpc-tls-key flag in order to serve gRPC securely. " +
+			"The web UI gateway does not connect over TLS, so it can not be used together with this flag",
+	}
+	// RPCKeyFlag defines a flag for the private key of the TLS certificate served by the RPC server.
+	RPCKeyFlag = &cli.StringFlag{
+		Name:  "rpc-tls-key",
+		Usage: "Private key of the certificate served by the RPC server",
+	}
+	// RPCClientCAFlag defines a flag for the CA certificate clients of the RPC server must be signed by.
+	RPCClientCAFlag = &cli.StringFlag{
+		Name: "rpc-client-ca-cert",
+		Usage: "CA certificate which clients of the RPC server must present a certificate signed by (mutual TLS). " +
+			"Requires the rpc-tls-cert and rpc-tls-key flags",
+	}
 	// SlasherRPCProviderFlag defines a slasher node RPC endpoint.
 	SlasherRPCProviderFlag = &cli.StringFlag{
 		Name:  "slasher-rpc-provider",
@@ -118,6 +254,13 @@ var (
 		Usage: "Comma separated list of domains from which to accept cross origin requests " +
 			"(browser enforced). This flag has no effect if not used with --grpc-gateway-port.",
 		Value: "http://localhost:4242,http://127.0.0.1:4242,http://localhost:4200,http://0.0.0.0:4242,http://0.0.0.0:4200"}
+	// MaxWalletSizeFlag defines the maximum number of keys a wallet may hold after
+	// importing keystores via the validator RPC server.
+	MaxWalletSizeFlag = &cli.IntFlag{
+		Name:  "max-wallet-size",
+		Usage: "Maximum number of validating keys a wallet may hold when importing keystores via RPC",
+		Value: DefaultMaxWalletSize,
+	}
 	// MonitoringPortFlag defines the http port used to serve prometheus metrics.
 	MonitoringPortFlag = &cli.IntFlag{
 		Name:  "monitoring-port",
@@ -282,6 +425,12 @@ var (
 		Usage: "Enables the web portal for the validator client (work in progress)",
 		Value: false,
 	}
+	// PersistJWTSecretFlag keeps the web portal sessions of the validator client valid across restarts.
+	PersistJWTSecretFlag = &cli.BoolFlag{
+		Name:  "persist-jwt-secret",
+		Usage: "Persists the key which signs web portal auth tokens to the wallet directory, so sessions remain valid across restarts",
+		Value: false,
+	}
 )
 
 // DefaultValidatorDir returns OS-specific default validator directory.
@@ -0,0 +1,85 @@
+package keymanager
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
+// The message signed during a signing benchmark. It is the hash of a fixed string
+// rather than the signing root of any beacon chain object, so signatures produced
+// while benchmarking can never be slashable.
+var benchmarkSigningRoot = hashutil.Hash([]byte("prysm-keymanager-signing-benchmark"))
+
+// SignBenchmarkResult reports the signing throughput and latency
+// achieved by a keymanager during a signing benchmark.
+type SignBenchmarkResult struct {
+	NumSignatures       uint64
+	Duration            time.Duration
+	SignaturesPerSecond float64
+	LatencyP50          time.Duration
+	LatencyP90          time.Duration
+	LatencyP99          time.Duration
+}
+
+// BenchmarkSign repeatedly signs a throwaway test message with the keymanager's first
+// validating key for the given duration and reports the signatures per second and
+// latency percentiles achieved, which is useful for sizing remote signers.
+func BenchmarkSign(ctx context.Context, km IKeymanager, duration time.Duration) (*SignBenchmarkResult, error) {
+	if duration <= 0 {
+		return nil, errors.New("benchmark duration must be greater than 0")
+	}
+	pubKeys, err := km.FetchValidatingPublicKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKeys) == 0 {
+		return nil, errors.New("no validating keys to benchmark signing with")
+	}
+	req := &validatorpb.SignRequest{
+		PublicKey:   pubKeys[0][:],
+		SigningRoot: benchmarkSigningRoot[:],
+	}
+	latencies := make([]time.Duration, 0)
+	start := time.Now()
+	deadline := start.Add(duration)
+	for time.Now().Before(deadline) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		signStart := time.Now()
+		if _, err := km.Sign(ctx, req); err != nil {
+			return nil, err
+		}
+		latencies = append(latencies, time.Since(signStart))
+	}
+	elapsed := time.Since(start)
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	return &SignBenchmarkResult{
+		NumSignatures:       uint64(len(latencies)),
+		Duration:            elapsed,
+		SignaturesPerSecond: float64(len(latencies)) / elapsed.Seconds(),
+		LatencyP50:          percentile(latencies, 50),
+		LatencyP90:          percentile(latencies, 90),
+		LatencyP99:          percentile(latencies, 99),
+	}, nil
+}
+
+// Returns the nearest-rank percentile of a sorted list of latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
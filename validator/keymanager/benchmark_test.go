@@ -0,0 +1,56 @@
+package keymanager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+type localKeymanager struct {
+	secretKey bls.SecretKey
+}
+
+func (m *localKeymanager) FetchValidatingPublicKeys(_ context.Context) ([][48]byte, error) {
+	return [][48]byte{bytesutil.ToBytes48(m.secretKey.PublicKey().Marshal())}, nil
+}
+
+func (m *localKeymanager) FetchAllValidatingPublicKeys(ctx context.Context) ([][48]byte, error) {
+	return m.FetchValidatingPublicKeys(ctx)
+}
+
+func (m *localKeymanager) Sign(_ context.Context, req *validatorpb.SignRequest) (bls.Signature, error) {
+	return m.secretKey.Sign(req.SigningRoot), nil
+}
+
+func TestBenchmarkSign(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	km := &localKeymanager{secretKey: secretKey}
+	duration := 200 * time.Millisecond
+	start := time.Now()
+	res, err := keymanager.BenchmarkSign(context.Background(), km, duration)
+	require.NoError(t, err)
+	elapsed := time.Since(start)
+	assert.Equal(t, true, elapsed >= duration, "Benchmark finished too early")
+	assert.Equal(t, true, elapsed < 2*duration, "Benchmark ran for too long")
+	assert.Equal(t, true, res.NumSignatures > 0)
+	assert.Equal(t, true, res.SignaturesPerSecond > 0)
+	assert.Equal(t, true, res.LatencyP50 > 0)
+	assert.Equal(t, true, res.LatencyP50 <= res.LatencyP90)
+	assert.Equal(t, true, res.LatencyP90 <= res.LatencyP99)
+}
+
+func TestBenchmarkSign_InvalidDuration(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	km := &localKeymanager{secretKey: secretKey}
+	_, err = keymanager.BenchmarkSign(context.Background(), km, 0)
+	assert.ErrorContains(t, "benchmark duration must be greater than 0", err)
+}
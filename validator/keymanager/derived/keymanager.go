@@ -7,11 +7,12 @@ import (
 	"github.com/pkg/errors"
 	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
 	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/validator/accounts/iface"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/prysmaticlabs/prysm/validator/keymanager/imported"
 	"github.com/sirupsen/logrus"
-	util "github.com/wealdtech/go-eth2-util"
 )
 
 var (
@@ -25,6 +26,9 @@ const (
 	// keys for Prysm eth2 validators. According to EIP-2334, the format is as follows:
 	// m / purpose / coin_type / account_index / withdrawal_key / validating_key
 	ValidatingKeyDerivationPathTemplate = "m/12381/3600/%d/0/0"
+	// Number of consecutive account indices without a key in the wallet after which the
+	// search for the wallet's highest account index stops.
+	accountIndexGapLimit = 100
 )
 
 // SetupConfig includes configuration values for initializing
@@ -64,19 +68,177 @@ func (dr *Keymanager) RecoverAccountsFromMnemonic(
 	if err != nil {
 		return errors.Wrap(err, "could not initialize new wallet seed file")
 	}
+	privKeys, pubKeys, err := deriveKeypairs(seed, 0, numAccounts)
+	if err != nil {
+		return err
+	}
+	return dr.importedKM.ImportKeypairs(ctx, privKeys, pubKeys)
+}
+
+// DeriveNextAccounts derives the next N validating keys along the EIP-2334 path from
+// a mnemonic phrase, continuing after the highest account index in the keymanager, and
+// imports them. As with recovery, the mnemonic is never stored by the validator.
+// The account index of the first derived account is returned along with the public keys
+// of the newly derived accounts, which follow it in order.
+func (dr *Keymanager) DeriveNextAccounts(
+	ctx context.Context, mnemonic, mnemonicPassphrase string, numAccounts int,
+) (int, [][]byte, error) {
+	if numAccounts <= 0 {
+		return 0, nil, errors.New("number of accounts to derive must be greater than 0")
+	}
+	masterSK, err := masterKeyFromMnemonic(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return 0, nil, err
+	}
+	startIndex, err := dr.nextAccountIndex(ctx, masterSK)
+	if err != nil {
+		return 0, nil, err
+	}
+	pubKeys, err := dr.importAccounts(ctx, masterSK, startIndex, numAccounts)
+	if err != nil {
+		return 0, nil, err
+	}
+	return startIndex, pubKeys, nil
+}
+
+// NextAccountIndex returns the account index DeriveNextAccounts would derive the next
+// account at from a mnemonic phrase, which follows the highest account index in the
+// keymanager.
+func (dr *Keymanager) NextAccountIndex(ctx context.Context, mnemonic, mnemonicPassphrase string) (int, error) {
+	masterSK, err := masterKeyFromMnemonic(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return 0, err
+	}
+	return dr.nextAccountIndex(ctx, masterSK)
+}
+
+// DeriveAccountsFrom derives N validating keys along the EIP-2334 path from a mnemonic
+// phrase, starting at the given account index, and imports them. The public keys of the
+// newly derived accounts are returned in the order of their account indices.
+func (dr *Keymanager) DeriveAccountsFrom(
+	ctx context.Context, mnemonic, mnemonicPassphrase string, startIndex, numAccounts int,
+) ([][]byte, error) {
+	if numAccounts <= 0 {
+		return nil, errors.New("number of accounts to derive must be greater than 0")
+	}
+	masterSK, err := masterKeyFromMnemonic(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	return dr.importAccounts(ctx, masterSK, startIndex, numAccounts)
+}
+
+// Derives the master key of a wallet from its mnemonic phrase.
+func masterKeyFromMnemonic(mnemonic, mnemonicPassphrase string) (bls.SecretKey, error) {
+	seed, err := seedFromMnemonic(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not initialize wallet seed")
+	}
+	masterSK, err := bls.DeriveMasterSK(seed)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not derive master key from seed")
+	}
+	return masterSK, nil
+}
+
+// Returns the account index after the highest one whose key is in the keymanager.
+func (dr *Keymanager) nextAccountIndex(ctx context.Context, masterSK bls.SecretKey) (int, error) {
+	existingKeys, err := dr.FetchAllValidatingPublicKeys(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not fetch existing public keys")
+	}
+	return nextAccountIndex(masterSK, existingKeys)
+}
+
+// Derives the validating keys of numAccounts accounts starting at the given account
+// index, and imports them into the keymanager.
+func (dr *Keymanager) importAccounts(
+	ctx context.Context, masterSK bls.SecretKey, startIndex, numAccounts int,
+) ([][]byte, error) {
 	privKeys := make([][]byte, numAccounts)
 	pubKeys := make([][]byte, numAccounts)
+	var err error
 	for i := 0; i < numAccounts; i++ {
-		privKey, err := util.PrivateKeyFromSeedAndPath(
-			seed, fmt.Sprintf(ValidatingKeyDerivationPathTemplate, i),
-		)
+		privKeys[i], pubKeys[i], err = deriveKeypair(masterSK, startIndex+i)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		privKeys[i] = privKey.Marshal()
-		pubKeys[i] = privKey.PublicKey().Marshal()
 	}
-	return dr.importedKM.ImportKeypairs(ctx, privKeys, pubKeys)
+	if err := dr.importedKM.ImportKeypairs(ctx, privKeys, pubKeys); err != nil {
+		return nil, err
+	}
+	return pubKeys, nil
+}
+
+// Returns the account index after the highest one whose key is in the wallet, so that
+// accounts deleted from the middle of the wallet are not derived again. Accounts may have
+// been deleted, so account indices are searched until every key of the wallet is found,
+// or until none is found for accountIndexGapLimit consecutive indices. Deriving from a
+// different mnemonic would silently add unrelated keys to the wallet, so a mnemonic which
+// reproduces none of the wallet's keys is rejected.
+func nextAccountIndex(masterSK bls.SecretKey, existingKeys [][48]byte) (int, error) {
+	if len(existingKeys) == 0 {
+		return 0, nil
+	}
+	remaining := make(map[[48]byte]bool, len(existingKeys))
+	for _, pubKey := range existingKeys {
+		remaining[pubKey] = true
+	}
+	next := 0
+	for index := 0; len(remaining) > 0 && index-next < accountIndexGapLimit; index++ {
+		_, pubKey, err := deriveKeypair(masterSK, index)
+		if err != nil {
+			return 0, err
+		}
+		if key := bytesutil.ToBytes48(pubKey); remaining[key] {
+			delete(remaining, key)
+			next = index + 1
+		}
+	}
+	if next == 0 {
+		return 0, errors.New("mnemonic does not match the accounts in the wallet")
+	}
+	return next, nil
+}
+
+// KeypairsFromMnemonic derives numAccounts validating keypairs from a mnemonic phrase
+// along the EIP-2334 path, starting at the given account index. It is used to
+// restore a range of HD wallet keys into wallets which store keys individually.
+func KeypairsFromMnemonic(
+	mnemonic, mnemonicPassphrase string, startIndex, numAccounts int,
+) (privKeys, pubKeys [][]byte, err error) {
+	seed, err := seedFromMnemonic(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not initialize wallet seed")
+	}
+	return deriveKeypairs(seed, startIndex, numAccounts)
+}
+
+// Derives numAccounts keypairs from a seed along the EIP-2334 validating key path,
+// starting at the given account index.
+func deriveKeypairs(seed []byte, startIndex, numAccounts int) ([][]byte, [][]byte, error) {
+	masterSK, err := bls.DeriveMasterSK(seed)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not derive master key from seed")
+	}
+	privKeys := make([][]byte, numAccounts)
+	pubKeys := make([][]byte, numAccounts)
+	for i := 0; i < numAccounts; i++ {
+		privKeys[i], pubKeys[i], err = deriveKeypair(masterSK, startIndex+i)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return privKeys, pubKeys, nil
+}
+
+// Derives the keypair of an account index along the EIP-2334 validating key path.
+func deriveKeypair(masterSK bls.SecretKey, index int) ([]byte, []byte, error) {
+	privKey, err := bls.DeriveKeyFromPath(masterSK, fmt.Sprintf(ValidatingKeyDerivationPathTemplate, index))
+	if err != nil {
+		return nil, nil, err
+	}
+	return privKey.Marshal(), privKey.PublicKey().Marshal(), nil
 }
 
 // ExtractKeystores retrieves the secret keys for specified public keys
@@ -88,6 +250,12 @@ func (dr *Keymanager) ExtractKeystores(
 	return dr.importedKM.ExtractKeystores(ctx, publicKeys, password)
 }
 
+// SubscribeAccountChanges creates an event subscription for a channel
+// to listen for public key changes at runtime, such as when new accounts are derived.
+func (dr *Keymanager) SubscribeAccountChanges(pubKeysChan chan [][48]byte) event.Subscription {
+	return dr.importedKM.SubscribeAccountChanges(pubKeysChan)
+}
+
 // ValidatingAccountNames for the derived keymanager.
 func (dr *Keymanager) ValidatingAccountNames(_ context.Context) ([]string, error) {
 	return dr.importedKM.ValidatingAccountNames()
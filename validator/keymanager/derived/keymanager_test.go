@@ -108,6 +108,133 @@ func TestDerivedKeymanager_FetchValidatingPublicKeys(t *testing.T) {
 	}
 }
 
+func TestDerivedKeymanager_DeriveNextAccounts(t *testing.T) {
+	sampleMnemonic := "tumble turn jewel sudden social great water general cabin jacket bounce dry flip monster advance problem social half flee inform century chicken hard reason"
+	derivedSeed, err := seedFromMnemonic(sampleMnemonic, "")
+	require.NoError(t, err)
+	wallet := &mock.Wallet{
+		Files:            make(map[string]map[string][]byte),
+		AccountPasswords: make(map[string]string),
+		WalletPassword:   "secretPassw0rd$1999",
+	}
+	ctx := context.Background()
+	dr, err := NewKeymanager(ctx, &SetupConfig{
+		Wallet: wallet,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, sampleMnemonic, "", 3))
+
+	pubKeysChan := make(chan [][48]byte, 1)
+	sub := dr.SubscribeAccountChanges(pubKeysChan)
+	defer sub.Unsubscribe()
+
+	numAccounts := 2
+	startIndex, newPubKeys, err := dr.DeriveNextAccounts(ctx, sampleMnemonic, "", numAccounts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, startIndex)
+	require.Equal(t, numAccounts, len(newPubKeys))
+	for i, pubKey := range newPubKeys {
+		// New accounts continue along the derivation path after the existing 3 accounts.
+		privKey, err := util.PrivateKeyFromSeedAndPath(derivedSeed, fmt.Sprintf(ValidatingKeyDerivationPathTemplate, 3+i))
+		require.NoError(t, err)
+		assert.DeepEqual(t, privKey.PublicKey().Marshal(), pubKey)
+	}
+	changedKeys := <-pubKeysChan
+	assert.Equal(t, 5, len(changedKeys))
+
+	publicKeys, err := dr.FetchValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(publicKeys))
+	assert.DeepEqual(t, newPubKeys[1], publicKeys[4][:])
+}
+
+func TestDerivedKeymanager_DeriveNextAccounts_DeletedAccounts(t *testing.T) {
+	sampleMnemonic := "tumble turn jewel sudden social great water general cabin jacket bounce dry flip monster advance problem social half flee inform century chicken hard reason"
+	derivedSeed, err := seedFromMnemonic(sampleMnemonic, "")
+	require.NoError(t, err)
+	wallet := &mock.Wallet{
+		Files:            make(map[string]map[string][]byte),
+		AccountPasswords: make(map[string]string),
+		WalletPassword:   "secretPassw0rd$1999",
+	}
+	ctx := context.Background()
+	dr, err := NewKeymanager(ctx, &SetupConfig{
+		Wallet: wallet,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, sampleMnemonic, "", 4))
+
+	// Delete the first and a middle account, so neither the first key nor the
+	// number of keys in the wallet identify the next account index.
+	pubKeys, err := dr.FetchAllValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	require.NoError(t, dr.DeleteAccounts(ctx, [][]byte{pubKeys[0][:], pubKeys[2][:]}))
+
+	startIndex, newPubKeys, err := dr.DeriveNextAccounts(ctx, sampleMnemonic, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, startIndex)
+	require.Equal(t, 1, len(newPubKeys))
+	privKey, err := util.PrivateKeyFromSeedAndPath(derivedSeed, fmt.Sprintf(ValidatingKeyDerivationPathTemplate, 4))
+	require.NoError(t, err)
+	assert.DeepEqual(t, privKey.PublicKey().Marshal(), newPubKeys[0])
+}
+
+func TestDerivedKeymanager_DeriveAccountsFrom(t *testing.T) {
+	sampleMnemonic := "tumble turn jewel sudden social great water general cabin jacket bounce dry flip monster advance problem social half flee inform century chicken hard reason"
+	derivedSeed, err := seedFromMnemonic(sampleMnemonic, "")
+	require.NoError(t, err)
+	wallet := &mock.Wallet{
+		Files:            make(map[string]map[string][]byte),
+		AccountPasswords: make(map[string]string),
+		WalletPassword:   "secretPassw0rd$1999",
+	}
+	ctx := context.Background()
+	dr, err := NewKeymanager(ctx, &SetupConfig{
+		Wallet: wallet,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, sampleMnemonic, "", 1))
+	nextIndex, err := dr.NextAccountIndex(ctx, sampleMnemonic, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, nextIndex)
+
+	// Accounts may be derived past a gap, after which the next account follows the highest one.
+	newPubKeys, err := dr.DeriveAccountsFrom(ctx, sampleMnemonic, "", 2, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(newPubKeys))
+	for i, pubKey := range newPubKeys {
+		privKey, err := util.PrivateKeyFromSeedAndPath(derivedSeed, fmt.Sprintf(ValidatingKeyDerivationPathTemplate, 2+i))
+		require.NoError(t, err)
+		assert.DeepEqual(t, privKey.PublicKey().Marshal(), pubKey)
+	}
+	nextIndex, err = dr.NextAccountIndex(ctx, sampleMnemonic, "")
+	require.NoError(t, err)
+	assert.Equal(t, 4, nextIndex)
+
+	_, err = dr.DeriveAccountsFrom(ctx, sampleMnemonic, "", 4, 0)
+	assert.ErrorContains(t, "must be greater than 0", err)
+}
+
+func TestDerivedKeymanager_DeriveNextAccounts_WrongMnemonic(t *testing.T) {
+	sampleMnemonic := "tumble turn jewel sudden social great water general cabin jacket bounce dry flip monster advance problem social half flee inform century chicken hard reason"
+	wallet := &mock.Wallet{
+		Files:            make(map[string]map[string][]byte),
+		AccountPasswords: make(map[string]string),
+		WalletPassword:   "secretPassw0rd$1999",
+	}
+	ctx := context.Background()
+	dr, err := NewKeymanager(ctx, &SetupConfig{
+		Wallet: wallet,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, sampleMnemonic, "", 1))
+
+	_, _, err = dr.DeriveNextAccounts(ctx, sampleMnemonic, "different passphrase", 1)
+	assert.ErrorContains(t, "mnemonic does not match", err)
+	_, _, err = dr.DeriveNextAccounts(ctx, sampleMnemonic, "", 0)
+	assert.ErrorContains(t, "must be greater than 0", err)
+}
+
 func TestDerivedKeymanager_FetchValidatingPrivateKeys(t *testing.T) {
 	sampleMnemonic := "tumble turn jewel sudden social great water general cabin jacket bounce dry flip monster advance problem social half flee inform century chicken hard reason"
 	derivedSeed, err := seedFromMnemonic(sampleMnemonic, "")
@@ -72,7 +72,18 @@ func (dr *Keymanager) ImportKeypairs(ctx context.Context, privKeys, pubKeys [][]
 	if err != nil {
 		return errors.Wrap(err, "could not marshal accounts keystore into JSON")
 	}
-	return dr.wallet.WriteFileAtPath(ctx, AccountsPath, accountsKeystoreFileName, encodedAccounts)
+	if err := dr.wallet.WriteFileAtPath(ctx, AccountsPath, accountsKeystoreFileName, encodedAccounts); err != nil {
+		return err
+	}
+	// Notify subscribers right away rather than waiting on the keystore file watcher.
+	if dr.accountsChangedFeed != nil {
+		lock.RLock()
+		pubKeysCopy := make([][48]byte, len(orderedPublicKeys))
+		copy(pubKeysCopy, orderedPublicKeys)
+		lock.RUnlock()
+		dr.accountsChangedFeed.Send(pubKeysCopy)
+	}
+	return nil
 }
 
 // Retrieves the private key and public key from an EIP-2335 keystore file
@@ -70,9 +70,10 @@ type accountsKeystoreRepresentation struct {
 	DisabledPublicKeys []string               `json:"disabled_public_keys"`
 }
 
-// ResetCaches for the keymanager.
+// ResetCaches for the keymanager, clearing the key material of the cached secret keys from memory.
 func ResetCaches() {
 	lock.Lock()
+	destroySecretKeysCache()
 	orderedPublicKeys = make([][48]byte, 0)
 	secretKeysCache = make(map[[48]byte]bls.SecretKey)
 	lock.Unlock()
@@ -156,6 +157,7 @@ func (dr *Keymanager) initializeKeysCachesFromKeystore() error {
 	lock.Lock()
 	defer lock.Unlock()
 	count := len(dr.accountsStore.PrivateKeys)
+	destroySecretKeysCache()
 	orderedPublicKeys = make([][48]byte, count)
 	secretKeysCache = make(map[[48]byte]bls.SecretKey, count)
 	for i, publicKey := range dr.accountsStore.PublicKeys {
@@ -170,6 +172,15 @@ func (dr *Keymanager) initializeKeysCachesFromKeystore() error {
 	return nil
 }
 
+// Destroys the cached secret keys before they are dropped from the cache, so keys which
+// are no longer used neither linger in memory until garbage collection nor remain usable.
+// Must be called with the lock held.
+func destroySecretKeysCache() {
+	for _, secretKey := range secretKeysCache {
+		secretKey.Destroy()
+	}
+}
+
 // DeleteAccounts takes in public keys and removes the accounts entirely. This includes their disk keystore and cached keystore.
 func (dr *Keymanager) DeleteAccounts(ctx context.Context, publicKeys [][]byte) error {
 	for _, publicKey := range publicKeys {
@@ -276,9 +287,10 @@ func (dr *Keymanager) Sign(ctx context.Context, req *validatorpb.SignRequest) (b
 	if publicKey == nil {
 		return nil, errors.New("nil public key in request")
 	}
+	// The lock is held while signing, as the key is zeroized once it is dropped from the cache.
 	lock.RLock()
+	defer lock.RUnlock()
 	secretKey, ok := secretKeysCache[bytesutil.ToBytes48(publicKey)]
-	lock.RUnlock()
 	if !ok {
 		return nil, errors.New("no signing key found in keys cache")
 	}
@@ -262,3 +262,18 @@ func TestImportedKeymanager_Sign_NoPublicKeyInCache(t *testing.T) {
 	_, err := dr.Sign(context.Background(), req)
 	assert.ErrorContains(t, "no signing key found in keys cache", err)
 }
+
+func TestImportedKeymanager_ResetCaches_ZeroizesSecretKeys(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	lock.Lock()
+	secretKeysCache = map[[48]byte]bls.SecretKey{
+		bytesutil.ToBytes48(secretKey.PublicKey().Marshal()): secretKey,
+	}
+	lock.Unlock()
+
+	ResetCaches()
+	assert.Equal(t, true, secretKey.IsZero(), "Secret key dropped from the cache was not zeroized")
+	assert.Equal(t, true, secretKey.Marshal() == nil, "Secret key dropped from the cache was not destroyed")
+	assert.Equal(t, 0, len(secretKeysCache))
+}
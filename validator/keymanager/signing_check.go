@@ -0,0 +1,66 @@
+package keymanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
+// The object root signed during a signing check. It is the hash of a fixed string
+// rather than the root of any beacon chain object, so although it is signed with a
+// real domain, the resulting signature is never a valid consensus message and can
+// never be slashable.
+var signingCheckObjectRoot = hashutil.Hash([]byte("prysm-keymanager-signing-check"))
+
+// SigningCheckResult reports the outcome of an end to end signing check.
+type SigningCheckResult struct {
+	SigningRoot   [32]byte
+	Signature     bls.Signature
+	Verified      bool
+	SignLatency   time.Duration
+	VerifyLatency time.Duration
+}
+
+// CheckSigning signs a fixed, non-consensus test message under the given domain with a
+// validating key of the keymanager, and verifies the signature against the public key.
+// It exercises the full signing path, including remote signers, which is useful for
+// troubleshooting a key without waiting for one of its duties.
+func CheckSigning(ctx context.Context, km IKeymanager, pubKey [48]byte, domain []byte) (*SigningCheckResult, error) {
+	if len(domain) != 32 {
+		return nil, errors.Errorf("domain must be 32 bytes, received %d", len(domain))
+	}
+	pub, err := bls.PublicKeyFromBytes(pubKey[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse public key")
+	}
+	signingRoot, err := (&pb.SigningData{
+		ObjectRoot: signingCheckObjectRoot[:],
+		Domain:     domain,
+	}).HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	signStart := time.Now()
+	sig, err := km.Sign(ctx, &validatorpb.SignRequest{
+		PublicKey:       pubKey[:],
+		SigningRoot:     signingRoot[:],
+		SignatureDomain: domain,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign")
+	}
+	res := &SigningCheckResult{
+		SigningRoot: signingRoot,
+		Signature:   sig,
+		SignLatency: time.Since(signStart),
+	}
+	verifyStart := time.Now()
+	res.Verified = sig.Verify(pub, signingRoot[:])
+	res.VerifyLatency = time.Since(verifyStart)
+	return res, nil
+}
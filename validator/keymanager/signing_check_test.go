@@ -0,0 +1,62 @@
+package keymanager_test
+
+import (
+	"context"
+	"testing"
+
+	validatorpb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+type wrongKeyKeymanager struct {
+	localKeymanager
+}
+
+func (m *wrongKeyKeymanager) Sign(_ context.Context, req *validatorpb.SignRequest) (bls.Signature, error) {
+	secretKey, err := bls.RandKey()
+	if err != nil {
+		return nil, err
+	}
+	return secretKey.Sign(req.SigningRoot), nil
+}
+
+func TestCheckSigning(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	km := &localKeymanager{secretKey: secretKey}
+	pubKey := bytesutil.ToBytes48(km.secretKey.PublicKey().Marshal())
+	domain := bytesutil.PadTo([]byte{1, 0, 0, 0}, 32)
+	res, err := keymanager.CheckSigning(context.Background(), km, pubKey, domain)
+	require.NoError(t, err)
+	assert.Equal(t, true, res.Verified)
+	assert.Equal(t, true, res.SignLatency > 0)
+
+	// The signing root commits to the domain.
+	otherDomain := bytesutil.PadTo([]byte{0, 0, 0, 0}, 32)
+	other, err := keymanager.CheckSigning(context.Background(), km, pubKey, otherDomain)
+	require.NoError(t, err)
+	assert.NotEqual(t, res.SigningRoot, other.SigningRoot)
+}
+
+func TestCheckSigning_WrongKey(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	km := &wrongKeyKeymanager{localKeymanager{secretKey: secretKey}}
+	pubKey := bytesutil.ToBytes48(km.secretKey.PublicKey().Marshal())
+	res, err := keymanager.CheckSigning(context.Background(), km, pubKey, make([]byte, 32))
+	require.NoError(t, err)
+	assert.Equal(t, false, res.Verified)
+}
+
+func TestCheckSigning_InvalidDomain(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	require.NoError(t, err)
+	km := &localKeymanager{secretKey: secretKey}
+	pubKey := bytesutil.ToBytes48(km.secretKey.PublicKey().Marshal())
+	_, err = keymanager.CheckSigning(context.Background(), km, pubKey, []byte{1, 2, 3})
+	assert.ErrorContains(t, "domain must be 32 bytes", err)
+}
@@ -44,6 +44,12 @@ func startNode(ctx *cli.Context) error {
 var appFlags = []cli.Flag{
 	flags.BeaconRPCProviderFlag,
 	flags.BeaconRPCGatewayProviderFlag,
+	flags.VerificationBeaconRPCProviderFlag,
+	flags.VerificationBeaconCertFlag,
+	flags.VerificationHeadSlotToleranceFlag,
+	flags.ClockSkewTimeServerFlag,
+	flags.ClockSkewWarningThresholdFlag,
+	flags.ClockSkewRefusalThresholdFlag,
 	flags.CertFlag,
 	flags.GraffitiFlag,
 	flags.DisablePenaltyRewardLogFlag,
@@ -52,13 +58,29 @@ var appFlags = []cli.Flag{
 	flags.EnableRPCFlag,
 	flags.RPCHost,
 	flags.RPCPort,
+	flags.RPCUnixSocketFlag,
+	flags.RPCMaxConnectionIdleFlag,
+	flags.RPCShutdownTimeoutFlag,
+	flags.RPCMaxMsgSizeFlag,
+	flags.RPCKeepaliveTimeFlag,
+	flags.RPCKeepaliveTimeoutFlag,
+	flags.RPCRateLimitFlag,
+	flags.RPCRateLimitBurstFlag,
+	flags.RPCUnauthenticatedRateLimitFlag,
+	flags.RPCUnauthenticatedRateLimitBurstFlag,
+	flags.DisableRPCRateLimitFlag,
+	flags.RPCCertFlag,
+	flags.RPCKeyFlag,
+	flags.RPCClientCAFlag,
 	flags.GRPCGatewayPort,
 	flags.GRPCGatewayHost,
 	flags.GrpcRetriesFlag,
 	flags.GrpcRetryDelayFlag,
 	flags.GrpcHeadersFlag,
 	flags.GPRCGatewayCorsDomain,
+	flags.MaxWalletSizeFlag,
 	flags.DisableAccountMetricsFlag,
+	flags.SlashingWarningMarginFlag,
 	cmd.MonitoringHostFlag,
 	flags.MonitoringPortFlag,
 	cmd.DisableMonitoringFlag,
@@ -67,6 +89,7 @@ var appFlags = []cli.Flag{
 	flags.WalletPasswordFileFlag,
 	flags.WalletDirFlag,
 	flags.EnableWebFlag,
+	flags.PersistJWTSecretFlag,
 	cmd.MinimalConfigFlag,
 	cmd.E2EConfigFlag,
 	cmd.VerbosityFlag,
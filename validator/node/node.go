@@ -14,6 +14,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/event"
@@ -88,6 +89,9 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 		chainConfigFileName := cliCtx.String(cmd.ChainConfigFileFlag.Name)
 		params.LoadChainConfigFile(chainConfigFileName)
 	}
+	if err := bls.CheckDomainSeparationTag(); err != nil {
+		return nil, err
+	}
 
 	// If the --web flag is enabled to administer the validator
 	// client via a web portal, we start the validator client in a different way.
@@ -158,6 +162,11 @@ func (s *ValidatorClient) initializeFromCLI(cliCtx *cli.Context) error {
 		if err != nil {
 			return errors.Wrap(err, "could not generate interop keys")
 		}
+		// Interop keys only validate on development chains, which do not have the
+		// genesis validators root of the configured network.
+		cfg := params.BeaconConfig().Copy()
+		cfg.GenesisValidatorsRoot = [32]byte{}
+		params.OverrideBeaconConfig(cfg)
 	} else {
 		// Read the wallet from the specified path.
 		w, err := wallet.OpenWalletOrElseCli(cliCtx, func(cliCtx *cli.Context) (*wallet.Wallet, error) {
@@ -352,21 +361,29 @@ func (s *ValidatorClient) registerClientService(
 		protector = sp
 	}
 	v, err := client.NewValidatorService(s.cliCtx.Context, &client.Config{
-		Endpoint:                   endpoint,
-		DataDir:                    dataDir,
-		KeyManager:                 keyManager,
-		LogValidatorBalances:       logValidatorBalances,
-		EmitAccountMetrics:         emitAccountMetrics,
-		CertFlag:                   cert,
-		GraffitiFlag:               graffiti,
-		GrpcMaxCallRecvMsgSizeFlag: maxCallRecvMsgSize,
-		GrpcRetriesFlag:            grpcRetries,
-		GrpcRetryDelay:             grpcRetryDelay,
-		GrpcHeadersFlag:            s.cliCtx.String(flags.GrpcHeadersFlag.Name),
-		Protector:                  protector,
-		ValDB:                      s.db,
-		UseWeb:                     s.cliCtx.Bool(flags.EnableWebFlag.Name),
-		WalletInitializedFeed:      s.walletInitialized,
+		Endpoint:                      endpoint,
+		VerificationEndpoint:          s.cliCtx.String(flags.VerificationBeaconRPCProviderFlag.Name),
+		VerificationCertFlag:          s.cliCtx.String(flags.VerificationBeaconCertFlag.Name),
+		VerificationHeadSlotTolerance: s.cliCtx.Uint64(flags.VerificationHeadSlotToleranceFlag.Name),
+		ClockSkewTimeServer:           s.cliCtx.String(flags.ClockSkewTimeServerFlag.Name),
+		ClockSkewWarningThreshold:     s.cliCtx.Duration(flags.ClockSkewWarningThresholdFlag.Name),
+		ClockSkewRefusalThreshold:     s.cliCtx.Duration(flags.ClockSkewRefusalThresholdFlag.Name),
+		DataDir:                       dataDir,
+		KeyManager:                    keyManager,
+		LogValidatorBalances:          logValidatorBalances,
+		EmitAccountMetrics:            emitAccountMetrics,
+		VerifyKeymanagerSignatures:    s.wallet != nil && s.wallet.KeymanagerKind() == keymanager.Remote,
+		SlashingWarningMargin:         s.cliCtx.Uint64(flags.SlashingWarningMarginFlag.Name),
+		CertFlag:                      cert,
+		GraffitiFlag:                  graffiti,
+		GrpcMaxCallRecvMsgSizeFlag:    maxCallRecvMsgSize,
+		GrpcRetriesFlag:               grpcRetries,
+		GrpcRetryDelay:                grpcRetryDelay,
+		GrpcHeadersFlag:               s.cliCtx.String(flags.GrpcHeadersFlag.Name),
+		Protector:                     protector,
+		ValDB:                         s.db,
+		UseWeb:                        s.cliCtx.Bool(flags.EnableWebFlag.Name),
+		WalletInitializedFeed:         s.walletInitialized,
 	})
 
 	if err != nil {
@@ -411,19 +428,45 @@ func (s *ValidatorClient) registerRPCService(cliCtx *cli.Context, km keymanager.
 	rpcPort := cliCtx.Int(flags.RPCPort.Name)
 	nodeGatewayEndpoint := cliCtx.String(flags.BeaconRPCGatewayProviderFlag.Name)
 	walletDir := cliCtx.String(flags.WalletDirFlag.Name)
+	maxWalletSize := cliCtx.Int(flags.MaxWalletSizeFlag.Name)
+	var jwtSecretFile string
+	if cliCtx.Bool(flags.PersistJWTSecretFlag.Name) {
+		jwtSecretFile = filepath.Join(walletDir, rpc.JWTSecretFileName)
+	}
 	server := rpc.NewServer(cliCtx.Context, &rpc.Config{
 		ValDB:                   s.db,
 		Host:                    rpcHost,
 		Port:                    fmt.Sprintf("%d", rpcPort),
+		UnixSocketPath:          cliCtx.String(flags.RPCUnixSocketFlag.Name),
+		MaxConnectionIdle:       cliCtx.Duration(flags.RPCMaxConnectionIdleFlag.Name),
+		ShutdownTimeout:         cliCtx.Duration(flags.RPCShutdownTimeoutFlag.Name),
+		MaxMsgSize:              cliCtx.Int(flags.RPCMaxMsgSizeFlag.Name),
+		KeepaliveTime:           cliCtx.Duration(flags.RPCKeepaliveTimeFlag.Name),
+		KeepaliveTimeout:        cliCtx.Duration(flags.RPCKeepaliveTimeoutFlag.Name),
+		RateLimit:               cliCtx.Float64(flags.RPCRateLimitFlag.Name),
+		RateLimitBurst:          cliCtx.Int64(flags.RPCRateLimitBurstFlag.Name),
+		NoAuthRateLimit:         cliCtx.Float64(flags.RPCUnauthenticatedRateLimitFlag.Name),
+		NoAuthRateLimitBurst:    cliCtx.Int64(flags.RPCUnauthenticatedRateLimitBurstFlag.Name),
+		DisableRateLimit:        cliCtx.Bool(flags.DisableRPCRateLimitFlag.Name),
+		CertFlag:                cliCtx.String(flags.RPCCertFlag.Name),
+		KeyFlag:                 cliCtx.String(flags.RPCKeyFlag.Name),
+		ClientCAFlag:            cliCtx.String(flags.RPCClientCAFlag.Name),
 		WalletInitializedFeed:   s.walletInitialized,
 		ValidatorService:        vs,
 		SyncChecker:             vs,
 		GenesisFetcher:          vs,
 		BeaconNodeInfoFetcher:   vs,
+		DutyCountdownFetcher:    vs,
+		DutyCountFetcher:        vs,
+		InclusionRateFetcher:    vs,
+		MissedDutyFetcher:       vs,
+		SigningDomainFetcher:    vs,
 		NodeGatewayEndpoint:     nodeGatewayEndpoint,
 		WalletDir:               walletDir,
+		JWTSecretFile:           jwtSecretFile,
 		Wallet:                  s.wallet,
 		Keymanager:              km,
+		MaxWalletSize:           maxWalletSize,
 		ValidatorGatewayHost:    validatorGatewayHost,
 		ValidatorGatewayPort:    validatorGatewayPort,
 		ValidatorMonitoringHost: validatorMonitoringHost,
@@ -2,18 +2,45 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
 
+	ptypes "github.com/gogo/protobuf/types"
 	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/pagination"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/petnames"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/prysmaticlabs/prysm/validator/keymanager/derived"
+	"github.com/prysmaticlabs/prysm/validator/keymanager/imported"
+	"github.com/skip2/go-qrcode"
+	"github.com/tyler-smith/go-bip39"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// The longest signing benchmark which can be requested via RPC.
+const maxSignBenchmarkDuration = time.Minute
+
+// The largest window of epochs an inclusion rate can be requested over via RPC,
+// bounding the number of beacon node requests made to compute it.
+const maxInclusionRateEpochs = 225
+
+// The number of accounts derived between progress updates when recovering accounts from a mnemonic.
+const recoverAccountsBatchSize = 10
+
+// The number of public keys encoded in each QR code, which keeps the codes
+// small enough to be scanned reliably by phone cameras.
+const publicKeysPerQRCode = 10
+
+// The width and height of QR code images, in pixels.
+const qrCodeImageSize = 512
+
 // ListAccounts allows retrieval of validating keys and their petnames
 // for a user's wallet via RPC.
 func (s *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
@@ -59,3 +86,385 @@ func (s *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest)
 		NextPageToken: nextPageToken,
 	}, nil
 }
+
+// DeriveAccounts derives the next accounts of an HD wallet along the EIP-2334 path
+// from the wallet's mnemonic and imports them, returning the newly derived accounts.
+func (s *Server) DeriveAccounts(ctx context.Context, req *pb.DeriveAccountsRequest) (*pb.DeriveAccountsResponse, error) {
+	if !s.walletInitialized {
+		return nil, status.Error(codes.FailedPrecondition, "Wallet not yet initialized")
+	}
+	km, ok := s.keymanager.(*derived.Keymanager)
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "Only HD wallets can derive new accounts")
+	}
+	if req.Mnemonic == "" {
+		return nil, status.Error(codes.InvalidArgument, "Mnemonic required to derive new accounts")
+	}
+	if req.NumAccounts == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Number of accounts to derive must be greater than 0")
+	}
+	if err := s.checkWalletSize(ctx, km, int(req.NumAccounts)); err != nil {
+		return nil, err
+	}
+	startIndex, pubKeys, err := km.DeriveNextAccounts(ctx, req.Mnemonic, req.MnemonicPassphrase, int(req.NumAccounts))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not derive new accounts: %v", err)
+	}
+	accs := make([]*pb.Account, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		accs[i] = &pb.Account{
+			ValidatingPublicKey: pubKey,
+			AccountName:         petnames.DeterministicName(pubKey, "-"),
+			DerivationPath:      fmt.Sprintf(derived.ValidatingKeyDerivationPathTemplate, startIndex+i),
+		}
+	}
+	return &pb.DeriveAccountsResponse{
+		Accounts: accs,
+	}, nil
+}
+
+// BenchmarkSign measures the signing throughput and latency of the wallet's keymanager
+// over the requested duration by signing a throwaway, non-slashable test message. Only
+// one benchmark runs at a time, so benchmarks cannot pile up on a signer which is also
+// signing duties.
+func (s *Server) BenchmarkSign(ctx context.Context, req *pb.BenchmarkSignRequest) (*pb.BenchmarkSignResponse, error) {
+	if !s.walletInitialized {
+		return nil, status.Error(codes.FailedPrecondition, "Wallet not yet initialized")
+	}
+	duration := time.Duration(req.DurationMs) * time.Millisecond
+	if duration <= 0 || duration > maxSignBenchmarkDuration {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Benchmark duration must be greater than 0 and at most %v",
+			maxSignBenchmarkDuration,
+		)
+	}
+	if !atomic.CompareAndSwapUint32(&s.signBenchmarkRunning, 0, 1) {
+		return nil, status.Error(codes.Unavailable, "A signing benchmark is already running")
+	}
+	defer atomic.StoreUint32(&s.signBenchmarkRunning, 0)
+	res, err := keymanager.BenchmarkSign(ctx, s.keymanager, duration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not benchmark signing: %v", err)
+	}
+	return &pb.BenchmarkSignResponse{
+		NumSignatures:       res.NumSignatures,
+		SignaturesPerSecond: res.SignaturesPerSecond,
+		LatencyP50Micros:    uint64(res.LatencyP50.Microseconds()),
+		LatencyP90Micros:    uint64(res.LatencyP90.Microseconds()),
+		LatencyP99Micros:    uint64(res.LatencyP99.Microseconds()),
+	}, nil
+}
+
+// CheckSigning troubleshoots a validating key end to end by fetching the current attester
+// domain, signing a fixed, non-consensus test message with the key and verifying the
+// signature. No consensus message is ever produced, so the check is safe to run at any time.
+func (s *Server) CheckSigning(ctx context.Context, req *pb.CheckSigningRequest) (*pb.CheckSigningResponse, error) {
+	if !s.walletInitialized {
+		return nil, status.Error(codes.FailedPrecondition, "Wallet not yet initialized")
+	}
+	if len(req.PublicKey) != params.BeaconConfig().BLSPubkeyLength {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Public key must be %d bytes, received %d",
+			params.BeaconConfig().BLSPubkeyLength,
+			len(req.PublicKey),
+		)
+	}
+	pubKey := bytesutil.ToBytes48(req.PublicKey)
+	pubKeys, err := s.keymanager.FetchValidatingPublicKeys(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not fetch validating public keys: %v", err)
+	}
+	found := false
+	for _, k := range pubKeys {
+		if k == pubKey {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "Public key %#x is not in the wallet", pubKey)
+	}
+
+	domainStart := time.Now()
+	domain, err := s.signingDomainFetcher.CurrentSigningDomain(ctx, params.BeaconConfig().DomainBeaconAttester)
+	resp := &pb.CheckSigningResponse{
+		DomainLatencyMicros: uint64(time.Since(domainStart).Microseconds()),
+	}
+	if err != nil {
+		resp.Error = fmt.Sprintf("could not fetch signing domain: %v", err)
+		return resp, nil
+	}
+	res, err := keymanager.CheckSigning(ctx, s.keymanager, pubKey, domain)
+	if err != nil {
+		resp.Error = err.Error()
+		return resp, nil
+	}
+	resp.SigningRoot = res.SigningRoot[:]
+	resp.Signature = res.Signature.Marshal()
+	resp.SignLatencyMicros = uint64(res.SignLatency.Microseconds())
+	resp.VerifyLatencyMicros = uint64(res.VerifyLatency.Microseconds())
+	resp.Success = res.Verified
+	if !res.Verified {
+		resp.Error = "signature does not verify against the public key"
+	}
+	return resp, nil
+}
+
+// GetDutyCounts counts the attestation, proposal, aggregation and sync committee
+// duties the validators managed by the validator client have in the current epoch.
+func (s *Server) GetDutyCounts(ctx context.Context, _ *ptypes.Empty) (*pb.DutyCountsResponse, error) {
+	counts, err := s.dutyCountFetcher.DutyCounts(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Could not count duties: %v", err)
+	}
+	return &pb.DutyCountsResponse{
+		Epoch:         counts.Epoch,
+		Attestations:  counts.Attestations,
+		Proposals:     counts.Proposals,
+		Aggregations:  counts.Aggregations,
+		SyncCommittee: counts.SyncCommittee,
+	}, nil
+}
+
+// GetDutyCountdowns reports the time until the next attestation, proposal and
+// aggregation duties of each validator managed by the validator client.
+func (s *Server) GetDutyCountdowns(ctx context.Context, _ *ptypes.Empty) (*pb.DutyCountdownsResponse, error) {
+	countdowns, err := s.dutyCountdownFetcher.DutyCountdowns(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Could not compute duty countdowns: %v", err)
+	}
+	resp := &pb.DutyCountdownsResponse{
+		Countdowns: make([]*pb.DutyCountdown, len(countdowns)),
+	}
+	for i, c := range countdowns {
+		pubKey := c.PublicKey
+		resp.Countdowns[i] = &pb.DutyCountdown{
+			PublicKey:           pubKey[:],
+			HasAttestation:      c.HasAttestation,
+			AttestationSlot:     c.AttestationSlot,
+			TimeToAttestationMs: uint64(c.TimeToAttestation.Milliseconds()),
+			HasProposal:         c.HasProposal,
+			ProposalSlot:        c.ProposalSlot,
+			TimeToProposalMs:    uint64(c.TimeToProposal.Milliseconds()),
+			HasAggregation:      c.HasAggregation,
+			AggregationSlot:     c.AggregationSlot,
+			TimeToAggregationMs: uint64(c.TimeToAggregation.Milliseconds()),
+		}
+	}
+	return resp, nil
+}
+
+// RecoverAccountsFromMnemonic derives a range of validator keys from a mnemonic along
+// the EIP-2334 path and adds them to the wallet, encrypted under the wallet password.
+// HD wallets can only recover accounts contiguously after the highest account index of
+// their existing accounts, which is where they derive their next accounts.
+func (s *Server) RecoverAccountsFromMnemonic(
+	ctx context.Context, req *pb.RecoverAccountsFromMnemonicRequest,
+) (*pb.RecoverAccountsFromMnemonicResponse, error) {
+	if !s.walletInitialized {
+		return nil, status.Error(codes.FailedPrecondition, "Wallet not yet initialized")
+	}
+	if !bip39.IsMnemonicValid(req.Mnemonic) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid mnemonic")
+	}
+	if req.NumAccounts == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Number of accounts to recover must be greater than 0")
+	}
+	if req.StartIndex > math.MaxUint32 || req.NumAccounts-1 > math.MaxUint32-req.StartIndex {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Account indices %d to %d are out of range, derivation path indices must fit in 32 bits",
+			req.StartIndex,
+			req.StartIndex+req.NumAccounts-1,
+		)
+	}
+	if err := s.checkWalletSize(ctx, s.keymanager, int(req.NumAccounts)); err != nil {
+		return nil, err
+	}
+	ctx, j := s.jobs.start(
+		ctx, fmt.Sprintf("Recover %d accounts from mnemonic", req.NumAccounts), req.NumAccounts,
+	)
+	pubKeys, err := s.recoverAccounts(ctx, j, req)
+	s.jobs.finish(j, err)
+	if err != nil {
+		return nil, err
+	}
+	accs := make([]*pb.Account, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		accs[i] = &pb.Account{
+			ValidatingPublicKey: pubKey,
+			AccountName:         petnames.DeterministicName(pubKey, "-"),
+			DerivationPath:      fmt.Sprintf(derived.ValidatingKeyDerivationPathTemplate, req.StartIndex+uint64(i)),
+		}
+	}
+	return &pb.RecoverAccountsFromMnemonicResponse{
+		Accounts: accs,
+	}, nil
+}
+
+// Derives the accounts requested for recovery and adds them to the wallet, reporting
+// its progress to the job tracking the recovery.
+func (s *Server) recoverAccounts(
+	ctx context.Context, j *job, req *pb.RecoverAccountsFromMnemonicRequest,
+) ([][]byte, error) {
+	var pubKeys [][]byte
+	switch km := s.keymanager.(type) {
+	case *imported.Keymanager:
+		// Keys are derived in batches so the job reports its progress and stops promptly when cancelled.
+		var privKeys [][]byte
+		for derivedCount := uint64(0); derivedCount < req.NumAccounts; {
+			if ctx.Err() != nil {
+				return nil, status.Error(codes.Canceled, "Account recovery was cancelled")
+			}
+			batchSize := req.NumAccounts - derivedCount
+			if batchSize > recoverAccountsBatchSize {
+				batchSize = recoverAccountsBatchSize
+			}
+			batchPrivKeys, batchPubKeys, err := derived.KeypairsFromMnemonic(
+				req.Mnemonic, req.MnemonicPassphrase, int(req.StartIndex+derivedCount), int(batchSize),
+			)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "Could not derive accounts: %v", err)
+			}
+			privKeys = append(privKeys, batchPrivKeys...)
+			pubKeys = append(pubKeys, batchPubKeys...)
+			derivedCount += batchSize
+			j.setProgress(derivedCount)
+		}
+		if err := km.ImportKeypairs(ctx, privKeys, pubKeys); err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not import recovered accounts: %v", err)
+		}
+	case *derived.Keymanager:
+		nextIndex, err := km.NextAccountIndex(ctx, req.Mnemonic, req.MnemonicPassphrase)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Could not find the next account index: %v", err)
+		}
+		if req.StartIndex != uint64(nextIndex) {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"HD wallets must recover accounts contiguously, starting at account index %d",
+				nextIndex,
+			)
+		}
+		pubKeys, err = km.DeriveAccountsFrom(
+			ctx, req.Mnemonic, req.MnemonicPassphrase, int(req.StartIndex), int(req.NumAccounts),
+		)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not recover accounts: %v", err)
+		}
+		j.setProgress(uint64(len(pubKeys)))
+	default:
+		return nil, status.Error(codes.FailedPrecondition, "Only imported and HD wallets can recover accounts")
+	}
+	return pubKeys, nil
+}
+
+// GetInclusionRate reports the fraction of attestations of each validator managed by the
+// validator client which were included on chain over a window of recently finished epochs.
+func (s *Server) GetInclusionRate(ctx context.Context, req *pb.InclusionRateRequest) (*pb.InclusionRateResponse, error) {
+	if req.NumEpochs == 0 || req.NumEpochs > maxInclusionRateEpochs {
+		return nil, status.Errorf(
+			codes.InvalidArgument, "Number of epochs must be between 1 and %d", maxInclusionRateEpochs,
+		)
+	}
+	window, err := s.inclusionRateFetcher.InclusionRates(ctx, req.NumEpochs)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Could not compute inclusion rates: %v", err)
+	}
+	resp := &pb.InclusionRateResponse{
+		StartEpoch:     window.StartEpoch,
+		EndEpoch:       window.EndEpoch,
+		InclusionRates: make([]*pb.ValidatorInclusionRate, len(window.Rates)),
+	}
+	for i, rate := range window.Rates {
+		pubKey := rate.PublicKey
+		resp.InclusionRates[i] = &pb.ValidatorInclusionRate{
+			PublicKey:      pubKey[:],
+			ActiveEpochs:   rate.ActiveEpochs,
+			IncludedEpochs: rate.IncludedEpochs,
+			InclusionRate:  rate.Rate,
+		}
+	}
+	return resp, nil
+}
+
+// GetMissedDuties returns the attestation, proposal and aggregation duties the
+// validator client most recently failed to complete, most recent first.
+func (s *Server) GetMissedDuties(ctx context.Context, _ *ptypes.Empty) (*pb.MissedDutiesResponse, error) {
+	missed, err := s.missedDutyFetcher.MissedDuties(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Could not fetch missed duties: %v", err)
+	}
+	resp := &pb.MissedDutiesResponse{
+		MissedDuties: make([]*pb.MissedDuty, len(missed)),
+	}
+	for i, m := range missed {
+		pubKey := m.PublicKey
+		resp.MissedDuties[i] = &pb.MissedDuty{
+			PublicKey: pubKey[:],
+			Slot:      m.Slot,
+			Duty:      m.Duty,
+			Reason:    m.Reason,
+			Timestamp: uint64(m.Time.Unix()),
+		}
+	}
+	return resp, nil
+}
+
+// GetPublicKeysQR encodes the validating public keys of the wallet as QR code images, so
+// they can be scanned into air-gapped or mobile tools. Large wallets are split into pages
+// of keys, one QR code each, to keep every code small enough to scan reliably.
+func (s *Server) GetPublicKeysQR(ctx context.Context, _ *ptypes.Empty) (*pb.PublicKeysQRResponse, error) {
+	if !s.walletInitialized {
+		return nil, status.Error(codes.FailedPrecondition, "Wallet not yet initialized")
+	}
+	pubKeys, err := s.keymanager.FetchValidatingPublicKeys(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not fetch validating public keys: %v", err)
+	}
+	qrCodes, err := publicKeysQRCodes(pubKeys)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not encode public keys as QR codes: %v", err)
+	}
+	return &pb.PublicKeysQRResponse{
+		QrCodes: qrCodes,
+	}, nil
+}
+
+// The JSON encoded in each QR code of a wallet's public keys.
+type publicKeysQRPage struct {
+	Page       int      `json:"page"`
+	Pages      int      `json:"pages"`
+	PublicKeys []string `json:"public_keys"`
+}
+
+// Encodes the public keys as PNG images of QR codes, each holding a page of the keys.
+func publicKeysQRCodes(pubKeys [][48]byte) ([][]byte, error) {
+	pages := (len(pubKeys) + publicKeysPerQRCode - 1) / publicKeysPerQRCode
+	qrCodes := make([][]byte, pages)
+	for i := range qrCodes {
+		end := (i + 1) * publicKeysPerQRCode
+		if end > len(pubKeys) {
+			end = len(pubKeys)
+		}
+		page := &publicKeysQRPage{
+			Page:       i + 1,
+			Pages:      pages,
+			PublicKeys: make([]string, 0, end-i*publicKeysPerQRCode),
+		}
+		for _, pubKey := range pubKeys[i*publicKeysPerQRCode : end] {
+			page.PublicKeys = append(page.PublicKeys, fmt.Sprintf("%#x", pubKey))
+		}
+		enc, err := json.Marshal(page)
+		if err != nil {
+			return nil, err
+		}
+		qrCodes[i], err = qrcode.Encode(string(enc), qrcode.Medium, qrCodeImageSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return qrCodes, nil
+}
@@ -1,18 +1,34 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"math"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/makiuchi-d/gozxing"
+	gozxingqrcode "github.com/makiuchi-d/gozxing/qrcode"
 	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 	"github.com/prysmaticlabs/prysm/validator/accounts"
 	"github.com/prysmaticlabs/prysm/validator/accounts/wallet"
+	"github.com/prysmaticlabs/prysm/validator/client"
 	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/prysmaticlabs/prysm/validator/keymanager/derived"
+	"github.com/prysmaticlabs/prysm/validator/keymanager/imported"
+	"github.com/tyler-smith/go-bip39"
+	util "github.com/wealdtech/go-eth2-util"
 )
 
 var (
@@ -85,3 +101,480 @@ func TestServer_ListAccounts(t *testing.T) {
 		assert.DeepEqual(t, res, test.res)
 	}
 }
+
+func TestServer_DeriveAccounts(t *testing.T) {
+	ctx := context.Background()
+	localWalletDir := setupWalletDir(t)
+	defaultWalletPath = localWalletDir
+	strongPass := "29384283xasjasd32%%&*@*#*"
+	w, err := accounts.CreateWalletWithKeymanager(ctx, &accounts.CreateWalletConfig{
+		WalletCfg: &wallet.Config{
+			WalletDir:      defaultWalletPath,
+			KeymanagerKind: keymanager.Derived,
+			WalletPassword: strongPass,
+		},
+		SkipMnemonicConfirm: true,
+	})
+	require.NoError(t, err)
+	km, err := w.InitializeKeymanager(ctx)
+	require.NoError(t, err)
+	s := &Server{
+		keymanager:        km,
+		walletInitialized: true,
+		wallet:            w,
+	}
+	numAccounts := 5
+	dr, ok := km.(*derived.Keymanager)
+	require.Equal(t, true, ok)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, testMnemonic, "", numAccounts))
+
+	_, err = s.DeriveAccounts(ctx, &pb.DeriveAccountsRequest{
+		Mnemonic: testMnemonic,
+	})
+	assert.ErrorContains(t, "Number of accounts to derive must be greater than 0", err)
+
+	// Derivation continues after the highest account index, not after the number of
+	// accounts, once an account is deleted from the middle of the wallet.
+	pubKeys, err := dr.FetchAllValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	require.NoError(t, dr.DeleteAccounts(ctx, [][]byte{pubKeys[1][:]}))
+	resp, err := s.DeriveAccounts(ctx, &pb.DeriveAccountsRequest{
+		Mnemonic:    testMnemonic,
+		NumAccounts: 3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(resp.Accounts))
+
+	listResp, err := s.ListAccounts(ctx, &pb.ListAccountsRequest{
+		All: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, numAccounts-1+3, len(listResp.Accounts))
+	for i, acc := range resp.Accounts {
+		assert.Equal(t, fmt.Sprintf(derived.ValidatingKeyDerivationPathTemplate, numAccounts+i), acc.DerivationPath)
+		assert.DeepEqual(t, listResp.Accounts[numAccounts-1+i].ValidatingPublicKey, acc.ValidatingPublicKey)
+	}
+
+	s.maxWalletSize = numAccounts - 1 + 3 + 1
+	_, err = s.DeriveAccounts(ctx, &pb.DeriveAccountsRequest{
+		Mnemonic:    testMnemonic,
+		NumAccounts: 2,
+	})
+	assert.ErrorContains(t, "would exceed the maximum wallet size", err)
+}
+
+func TestServer_BenchmarkSign(t *testing.T) {
+	ctx := context.Background()
+	localWalletDir := setupWalletDir(t)
+	defaultWalletPath = localWalletDir
+	strongPass := "29384283xasjasd32%%&*@*#*"
+	w, err := accounts.CreateWalletWithKeymanager(ctx, &accounts.CreateWalletConfig{
+		WalletCfg: &wallet.Config{
+			WalletDir:      defaultWalletPath,
+			KeymanagerKind: keymanager.Derived,
+			WalletPassword: strongPass,
+		},
+		SkipMnemonicConfirm: true,
+	})
+	require.NoError(t, err)
+	km, err := w.InitializeKeymanager(ctx)
+	require.NoError(t, err)
+	s := &Server{
+		keymanager:        km,
+		walletInitialized: true,
+		wallet:            w,
+	}
+	dr, ok := km.(*derived.Keymanager)
+	require.Equal(t, true, ok)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, testMnemonic, "", 1))
+
+	_, err = s.BenchmarkSign(ctx, &pb.BenchmarkSignRequest{
+		DurationMs: uint64((2 * maxSignBenchmarkDuration).Milliseconds()),
+	})
+	assert.ErrorContains(t, "Benchmark duration must be greater than 0", err)
+
+	resp, err := s.BenchmarkSign(ctx, &pb.BenchmarkSignRequest{
+		DurationMs: 100,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, resp.NumSignatures > 0)
+	assert.Equal(t, true, resp.SignaturesPerSecond > 0)
+
+	// Only one benchmark runs at a time.
+	s.signBenchmarkRunning = 1
+	_, err = s.BenchmarkSign(ctx, &pb.BenchmarkSignRequest{
+		DurationMs: 100,
+	})
+	assert.ErrorContains(t, "A signing benchmark is already running", err)
+}
+
+type mockSigningDomainFetcher struct {
+	domain []byte
+	err    error
+}
+
+func (m *mockSigningDomainFetcher) CurrentSigningDomain(_ context.Context, _ [4]byte) ([]byte, error) {
+	return m.domain, m.err
+}
+
+func TestServer_CheckSigning(t *testing.T) {
+	ctx := context.Background()
+	localWalletDir := setupWalletDir(t)
+	defaultWalletPath = localWalletDir
+	strongPass := "29384283xasjasd32%%&*@*#*"
+	w, err := accounts.CreateWalletWithKeymanager(ctx, &accounts.CreateWalletConfig{
+		WalletCfg: &wallet.Config{
+			WalletDir:      defaultWalletPath,
+			KeymanagerKind: keymanager.Derived,
+			WalletPassword: strongPass,
+		},
+		SkipMnemonicConfirm: true,
+	})
+	require.NoError(t, err)
+	km, err := w.InitializeKeymanager(ctx)
+	require.NoError(t, err)
+	dr, ok := km.(*derived.Keymanager)
+	require.Equal(t, true, ok)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, testMnemonic, "", 1))
+	pubKeys, err := km.FetchValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	fetcher := &mockSigningDomainFetcher{domain: make([]byte, 32)}
+	s := &Server{
+		keymanager:           km,
+		walletInitialized:    true,
+		wallet:               w,
+		signingDomainFetcher: fetcher,
+	}
+
+	resp, err := s.CheckSigning(ctx, &pb.CheckSigningRequest{PublicKey: pubKeys[0][:]})
+	require.NoError(t, err)
+	assert.Equal(t, true, resp.Success, resp.Error)
+	sig, err := bls.SignatureFromBytes(resp.Signature)
+	require.NoError(t, err)
+	pub, err := bls.PublicKeyFromBytes(pubKeys[0][:])
+	require.NoError(t, err)
+	assert.Equal(t, true, sig.Verify(pub, resp.SigningRoot))
+
+	// A domain which can not be fetched fails the check rather than the request.
+	fetcher.err = errors.New("beacon node unavailable")
+	resp, err = s.CheckSigning(ctx, &pb.CheckSigningRequest{PublicKey: pubKeys[0][:]})
+	require.NoError(t, err)
+	assert.Equal(t, false, resp.Success)
+	assert.Equal(t, true, strings.Contains(resp.Error, "beacon node unavailable"), resp.Error)
+
+	_, err = s.CheckSigning(ctx, &pb.CheckSigningRequest{PublicKey: []byte{1, 2, 3}})
+	assert.ErrorContains(t, "Public key must be 48 bytes", err)
+	_, err = s.CheckSigning(ctx, &pb.CheckSigningRequest{PublicKey: make([]byte, 48)})
+	assert.ErrorContains(t, "is not in the wallet", err)
+}
+
+type mockDutyCountdownFetcher struct {
+	countdowns []*client.DutyCountdown
+}
+
+func (m *mockDutyCountdownFetcher) DutyCountdowns(_ context.Context) ([]*client.DutyCountdown, error) {
+	return m.countdowns, nil
+}
+
+func TestServer_GetDutyCountdowns(t *testing.T) {
+	pubKey := [48]byte{1, 2, 3}
+	s := &Server{
+		dutyCountdownFetcher: &mockDutyCountdownFetcher{
+			countdowns: []*client.DutyCountdown{
+				{
+					PublicKey:         pubKey,
+					HasAttestation:    true,
+					AttestationSlot:   12,
+					TimeToAttestation: 28 * time.Second,
+					HasAggregation:    true,
+					AggregationSlot:   12,
+					TimeToAggregation: 32 * time.Second,
+				},
+			},
+		},
+	}
+	resp, err := s.GetDutyCountdowns(context.Background(), &ptypes.Empty{})
+	require.NoError(t, err)
+	assert.DeepEqual(t, &pb.DutyCountdownsResponse{
+		Countdowns: []*pb.DutyCountdown{
+			{
+				PublicKey:           pubKey[:],
+				HasAttestation:      true,
+				AttestationSlot:     12,
+				TimeToAttestationMs: 28000,
+				HasAggregation:      true,
+				AggregationSlot:     12,
+				TimeToAggregationMs: 32000,
+			},
+		},
+	}, resp)
+}
+
+type mockDutyCountFetcher struct {
+	counts *client.DutyCounts
+	err    error
+}
+
+func (m *mockDutyCountFetcher) DutyCounts(_ context.Context) (*client.DutyCounts, error) {
+	return m.counts, m.err
+}
+
+func TestServer_GetDutyCounts(t *testing.T) {
+	fetcher := &mockDutyCountFetcher{
+		counts: &client.DutyCounts{
+			Epoch:        3,
+			Attestations: 4,
+			Proposals:    1,
+			Aggregations: 2,
+		},
+	}
+	s := &Server{dutyCountFetcher: fetcher}
+	resp, err := s.GetDutyCounts(context.Background(), &ptypes.Empty{})
+	require.NoError(t, err)
+	assert.DeepEqual(t, &pb.DutyCountsResponse{
+		Epoch:        3,
+		Attestations: 4,
+		Proposals:    1,
+		Aggregations: 2,
+	}, resp)
+
+	fetcher.err = errors.New("validator duties are not yet available")
+	_, err = s.GetDutyCounts(context.Background(), &ptypes.Empty{})
+	assert.ErrorContains(t, "Could not count duties", err)
+}
+
+func TestServer_RecoverAccountsFromMnemonic(t *testing.T) {
+	ctx := context.Background()
+	seed := bip39.NewSeed(testMnemonic, "")
+	wantPubKey := func(index uint64) []byte {
+		privKey, err := util.PrivateKeyFromSeedAndPath(
+			seed, fmt.Sprintf(derived.ValidatingKeyDerivationPathTemplate, index),
+		)
+		require.NoError(t, err)
+		return privKey.PublicKey().Marshal()
+	}
+	for _, kind := range []keymanager.Kind{keymanager.Imported, keymanager.Derived} {
+		t.Run(kind.String(), func(t *testing.T) {
+			imported.ResetCaches()
+			localWalletDir := setupWalletDir(t)
+			defaultWalletPath = localWalletDir
+			w, err := accounts.CreateWalletWithKeymanager(ctx, &accounts.CreateWalletConfig{
+				WalletCfg: &wallet.Config{
+					WalletDir:      defaultWalletPath,
+					KeymanagerKind: kind,
+					WalletPassword: "29384283xasjasd32%%&*@*#*",
+				},
+				SkipMnemonicConfirm: true,
+			})
+			require.NoError(t, err)
+			km, err := w.InitializeKeymanager(ctx)
+			require.NoError(t, err)
+			s := &Server{
+				keymanager:        km,
+				walletInitialized: true,
+				wallet:            w,
+			}
+
+			_, err = s.RecoverAccountsFromMnemonic(ctx, &pb.RecoverAccountsFromMnemonicRequest{
+				Mnemonic:    "not a valid mnemonic",
+				NumAccounts: 1,
+			})
+			assert.ErrorContains(t, "Invalid mnemonic", err)
+			_, err = s.RecoverAccountsFromMnemonic(ctx, &pb.RecoverAccountsFromMnemonicRequest{
+				Mnemonic:    testMnemonic,
+				StartIndex:  math.MaxUint32,
+				NumAccounts: 2,
+			})
+			assert.ErrorContains(t, "out of range", err)
+
+			// HD wallets can only recover accounts following their existing ones.
+			startIndex := uint64(5)
+			if kind == keymanager.Derived {
+				_, err = s.RecoverAccountsFromMnemonic(ctx, &pb.RecoverAccountsFromMnemonicRequest{
+					Mnemonic:    testMnemonic,
+					StartIndex:  startIndex,
+					NumAccounts: 2,
+				})
+				assert.ErrorContains(t, "must recover accounts contiguously", err)
+				startIndex = 0
+			}
+
+			resp, err := s.RecoverAccountsFromMnemonic(ctx, &pb.RecoverAccountsFromMnemonicRequest{
+				Mnemonic:    testMnemonic,
+				StartIndex:  startIndex,
+				NumAccounts: 3,
+			})
+			require.NoError(t, err)
+			require.Equal(t, 3, len(resp.Accounts))
+			for i, acc := range resp.Accounts {
+				index := startIndex + uint64(i)
+				assert.DeepEqual(t, wantPubKey(index), acc.ValidatingPublicKey)
+				assert.Equal(t, fmt.Sprintf(derived.ValidatingKeyDerivationPathTemplate, index), acc.DerivationPath)
+			}
+			keys, err := km.FetchAllValidatingPublicKeys(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 3, len(keys))
+			if kind != keymanager.Derived {
+				return
+			}
+
+			// Once an account is deleted, HD wallets continue after the highest account index.
+			dr, ok := km.(*derived.Keymanager)
+			require.Equal(t, true, ok)
+			require.NoError(t, dr.DeleteAccounts(ctx, [][]byte{keys[1][:]}))
+			_, err = s.RecoverAccountsFromMnemonic(ctx, &pb.RecoverAccountsFromMnemonicRequest{
+				Mnemonic:    testMnemonic,
+				StartIndex:  2,
+				NumAccounts: 1,
+			})
+			assert.ErrorContains(t, "starting at account index 3", err)
+			resp, err = s.RecoverAccountsFromMnemonic(ctx, &pb.RecoverAccountsFromMnemonicRequest{
+				Mnemonic:    testMnemonic,
+				StartIndex:  3,
+				NumAccounts: 1,
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, len(resp.Accounts))
+			assert.DeepEqual(t, wantPubKey(3), resp.Accounts[0].ValidatingPublicKey)
+			assert.Equal(t, fmt.Sprintf(derived.ValidatingKeyDerivationPathTemplate, 3), resp.Accounts[0].DerivationPath)
+		})
+	}
+}
+
+type mockInclusionRateFetcher struct {
+	window *client.InclusionRateWindow
+}
+
+func (m *mockInclusionRateFetcher) InclusionRates(_ context.Context, _ uint64) (*client.InclusionRateWindow, error) {
+	return m.window, nil
+}
+
+func TestServer_GetInclusionRate(t *testing.T) {
+	pubKey := [48]byte{1, 2, 3}
+	s := &Server{
+		inclusionRateFetcher: &mockInclusionRateFetcher{
+			window: &client.InclusionRateWindow{
+				StartEpoch: 8,
+				EndEpoch:   11,
+				Rates: []*client.InclusionRate{
+					{
+						PublicKey:      pubKey,
+						ActiveEpochs:   4,
+						IncludedEpochs: 3,
+						Rate:           0.75,
+					},
+				},
+			},
+		},
+	}
+	_, err := s.GetInclusionRate(context.Background(), &pb.InclusionRateRequest{})
+	assert.ErrorContains(t, "Number of epochs must be between 1 and", err)
+	_, err = s.GetInclusionRate(context.Background(), &pb.InclusionRateRequest{NumEpochs: maxInclusionRateEpochs + 1})
+	assert.ErrorContains(t, "Number of epochs must be between 1 and", err)
+
+	resp, err := s.GetInclusionRate(context.Background(), &pb.InclusionRateRequest{NumEpochs: 4})
+	require.NoError(t, err)
+	assert.DeepEqual(t, &pb.InclusionRateResponse{
+		StartEpoch: 8,
+		EndEpoch:   11,
+		InclusionRates: []*pb.ValidatorInclusionRate{
+			{
+				PublicKey:      pubKey[:],
+				ActiveEpochs:   4,
+				IncludedEpochs: 3,
+				InclusionRate:  0.75,
+			},
+		},
+	}, resp)
+}
+
+type mockMissedDutyFetcher struct {
+	missed []*client.MissedDuty
+}
+
+func (m *mockMissedDutyFetcher) MissedDuties(_ context.Context) ([]*client.MissedDuty, error) {
+	return m.missed, nil
+}
+
+func TestServer_GetMissedDuties(t *testing.T) {
+	pubKey := [48]byte{1, 2, 3}
+	missedAt := time.Unix(1600000000, 0)
+	s := &Server{
+		missedDutyFetcher: &mockMissedDutyFetcher{
+			missed: []*client.MissedDuty{
+				{
+					PublicKey: pubKey,
+					Slot:      10,
+					Duty:      "aggregation",
+					Reason:    "could not submit signed aggregate and proof to beacon node: bad",
+					Time:      missedAt,
+				},
+			},
+		},
+	}
+	resp, err := s.GetMissedDuties(context.Background(), &ptypes.Empty{})
+	require.NoError(t, err)
+	assert.DeepEqual(t, &pb.MissedDutiesResponse{
+		MissedDuties: []*pb.MissedDuty{
+			{
+				PublicKey: pubKey[:],
+				Slot:      10,
+				Duty:      "aggregation",
+				Reason:    "could not submit signed aggregate and proof to beacon node: bad",
+				Timestamp: 1600000000,
+			},
+		},
+	}, resp)
+}
+
+func TestServer_GetPublicKeysQR(t *testing.T) {
+	ctx := context.Background()
+	localWalletDir := setupWalletDir(t)
+	defaultWalletPath = localWalletDir
+	strongPass := "29384283xasjasd32%%&*@*#*"
+	w, err := accounts.CreateWalletWithKeymanager(ctx, &accounts.CreateWalletConfig{
+		WalletCfg: &wallet.Config{
+			WalletDir:      defaultWalletPath,
+			KeymanagerKind: keymanager.Derived,
+			WalletPassword: strongPass,
+		},
+		SkipMnemonicConfirm: true,
+	})
+	require.NoError(t, err)
+	km, err := w.InitializeKeymanager(ctx)
+	require.NoError(t, err)
+	s := &Server{
+		keymanager:        km,
+		walletInitialized: true,
+		wallet:            w,
+	}
+	// Enough accounts to need a second page of keys.
+	numAccounts := publicKeysPerQRCode + 2
+	dr, ok := km.(*derived.Keymanager)
+	require.Equal(t, true, ok)
+	require.NoError(t, dr.RecoverAccountsFromMnemonic(ctx, testMnemonic, "", numAccounts))
+	pubKeys, err := km.FetchValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+
+	resp, err := s.GetPublicKeysQR(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resp.QrCodes))
+	var decoded []string
+	for i, qrCode := range resp.QrCodes {
+		img, err := png.Decode(bytes.NewReader(qrCode))
+		require.NoError(t, err)
+		bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+		require.NoError(t, err)
+		res, err := gozxingqrcode.NewQRCodeReader().Decode(bmp, nil)
+		require.NoError(t, err)
+		page := &publicKeysQRPage{}
+		require.NoError(t, json.Unmarshal([]byte(res.GetText()), page))
+		assert.Equal(t, i+1, page.Page)
+		assert.Equal(t, 2, page.Pages)
+		decoded = append(decoded, page.PublicKeys...)
+	}
+	require.Equal(t, numAccounts, len(decoded))
+	for i, pubKey := range pubKeys {
+		assert.Equal(t, fmt.Sprintf("%#x", pubKey), decoded[i])
+	}
+}
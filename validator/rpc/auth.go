@@ -25,8 +25,11 @@ var (
 
 const (
 	// HashedRPCPassword for the validator RPC access.
-	HashedRPCPassword       = "rpc-password-hash"
+	HashedRPCPassword = "rpc-password-hash"
+	// JWTSecretFileName in the wallet directory, when the JWT key of the validator RPC is persisted.
+	JWTSecretFileName       = "rpc-jwt-secret"
 	checkUserSignupInterval = time.Second * 30
+	jwtKeyLength            = 32
 )
 
 // Signup to authenticate access to the validator RPC API using bcrypt and
@@ -100,13 +103,37 @@ func (s *Server) HasUsedWeb(ctx context.Context, _ *ptypes.Empty) (*pb.HasUsedWe
 
 // Logout a user by invalidating their JWT key.
 func (s *Server) Logout(ctx context.Context, _ *ptypes.Empty) (*ptypes.Empty, error) {
-	// Invalidate the old JWT key, making all requests done with its token fail.
+	if err := s.rotateJWTKey(); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not invalidate JWT key: %v", err)
+	}
+	return &ptypes.Empty{}, nil
+}
+
+// RegenerateJWTKey deliberately rotates the JWT key, invalidating every auth token issued
+// so far, and returns a new auth token so the caller remains logged in.
+func (s *Server) RegenerateJWTKey(ctx context.Context, _ *ptypes.Empty) (*pb.AuthResponse, error) {
+	if err := s.rotateJWTKey(); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not regenerate JWT key: %v", err)
+	}
+	return s.sendAuthResponse()
+}
+
+// Replaces the JWT key with a new random key, making all requests done with tokens signed
+// by the old key fail. The new key is persisted too, or the old tokens would become valid
+// again on restart. The lock is held while persisting, so that concurrent rotations
+// cannot leave a different key in the file than in memory.
+func (s *Server) rotateJWTKey() error {
 	jwtKey, err := createRandomJWTKey()
 	if err != nil {
-		return nil, status.Error(codes.Internal, "Could not invalidate JWT key")
+		return err
+	}
+	s.jwtKeyLock.Lock()
+	defer s.jwtKeyLock.Unlock()
+	if err := s.saveJWTKey(jwtKey); err != nil {
+		return err
 	}
 	s.jwtKey = jwtKey
-	return &ptypes.Empty{}, nil
+	return nil
 }
 
 // Sends an auth response via gRPC containing a new JWT token.
@@ -196,7 +223,9 @@ func (s *Server) createTokenString() (string, uint64, error) {
 		ExpiresAt: expirationTime.Unix(),
 	})
 	// Sign and get the complete encoded token as a string using the secret
+	s.jwtKeyLock.RLock()
 	tokenString, err := token.SignedString(s.jwtKey)
+	s.jwtKeyLock.RUnlock()
 	if err != nil {
 		return "", 0, err
 	}
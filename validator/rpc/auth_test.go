@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/dgrijalva/jwt-go"
@@ -96,6 +97,66 @@ func TestServer_Logout(t *testing.T) {
 	assert.ErrorContains(t, "signature is invalid", err)
 }
 
+func TestServer_RegenerateJWTKey(t *testing.T) {
+	jwtSecretFile := filepath.Join(t.TempDir(), "wallet", JWTSecretFileName)
+	ss := &Server{jwtSecretFile: jwtSecretFile}
+	key, err := ss.initializeJWTKey()
+	require.NoError(t, err)
+	ss.jwtKey = key
+	tokenString, _, err := ss.createTokenString()
+	require.NoError(t, err)
+	checkParsedKey := func(*jwt.Token) (interface{}, error) {
+		return ss.jwtKey, nil
+	}
+
+	resp, err := ss.RegenerateJWTKey(context.Background(), &ptypes.Empty{})
+	require.NoError(t, err)
+
+	// Tokens signed with the old key are invalid, while the returned token is signed with the new key.
+	_, err = jwt.Parse(tokenString, checkParsedKey)
+	assert.ErrorContains(t, "signature is invalid", err)
+	_, err = jwt.Parse(resp.Token, checkParsedKey)
+	assert.NoError(t, err)
+
+	// The new key is persisted, so the rotation survives a restart.
+	persisted, err := fileutil.ReadFileAsBytes(jwtSecretFile)
+	require.NoError(t, err)
+	assert.DeepEqual(t, ss.jwtKey, persisted)
+	assert.NotEqual(t, string(key), string(persisted))
+}
+
+func TestServer_RegenerateJWTKey_Concurrent(t *testing.T) {
+	jwtSecretFile := filepath.Join(t.TempDir(), "wallet", JWTSecretFileName)
+	ss := &Server{jwtSecretFile: jwtSecretFile}
+	key, err := ss.initializeJWTKey()
+	require.NoError(t, err)
+	ss.jwtKey = key
+
+	// Tokens are issued and validated while the key is rotated.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := ss.RegenerateJWTKey(context.Background(), &ptypes.Empty{})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			tokenString, _, err := ss.createTokenString()
+			assert.NoError(t, err)
+			// The key may have been rotated in between, so the token is not necessarily valid.
+			_, _ = jwt.Parse(tokenString, ss.validateJWT)
+		}()
+	}
+	wg.Wait()
+
+	// The last rotation wins both in memory and on disk.
+	persisted, err := fileutil.ReadFileAsBytes(jwtSecretFile)
+	require.NoError(t, err)
+	assert.DeepEqual(t, ss.jwtKey, persisted)
+}
+
 func TestServer_ChangePassword_Preconditions(t *testing.T) {
 	localWalletDir := setupWalletDir(t)
 	defaultWalletPath = localWalletDir
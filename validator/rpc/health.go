@@ -2,11 +2,16 @@ package rpc
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	ptypes "github.com/gogo/protobuf/types"
 	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetBeaconNodeConnection retrieves the current beacon node connection
@@ -34,6 +39,25 @@ func (s *Server) GetBeaconNodeConnection(ctx context.Context, _ *ptypes.Empty) (
 	}, nil
 }
 
+// GetChainTiming returns the genesis time the validator client computes slot timing from,
+// along with the current slot and the slot duration, which helps to diagnose timing issues
+// such as a validator client configured for the wrong network.
+func (s *Server) GetChainTiming(ctx context.Context, _ *ptypes.Empty) (*pb.ChainTimingResponse, error) {
+	if s.genesisFetcher == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Validator client not yet started")
+	}
+	genesis, err := s.genesisFetcher.GenesisInfo(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Could not retrieve genesis information: %v", err)
+	}
+	genesisTime := time.Unix(genesis.GenesisTime.Seconds, 0)
+	return &pb.ChainTimingResponse{
+		GenesisTime:    uint64(genesisTime.Unix()),
+		CurrentSlot:    slotutil.SlotsSinceGenesis(genesisTime),
+		SecondsPerSlot: params.BeaconConfig().SecondsPerSlot,
+	}, nil
+}
+
 // GetLogsEndpoints for the beacon and validator client.
 func (s *Server) GetLogsEndpoints(ctx context.Context, _ *ptypes.Empty) (*pb.LogsEndpointResponse, error) {
 	beaconLogsEndpoint, err := s.beaconNodeInfoFetcher.BeaconLogsEndpoint(ctx)
@@ -45,3 +69,19 @@ func (s *Server) GetLogsEndpoints(ctx context.Context, _ *ptypes.Empty) (*pb.Log
 		ValidatorLogsEndpoint: fmt.Sprintf("%s:%d/logs", s.validatorMonitoringHost, s.validatorMonitoringPort),
 	}, nil
 }
+
+// GetCertificateFingerprint returns the SHA-256 fingerprint of the TLS certificate served
+// by the validator RPC server, so clients pinning the certificate can detect a change.
+func (s *Server) GetCertificateFingerprint(_ context.Context, _ *ptypes.Empty) (*pb.CertificateFingerprintResponse, error) {
+	s.certLock.RLock()
+	defer s.certLock.RUnlock()
+	if s.certFingerprint == nil {
+		return &pb.CertificateFingerprintResponse{
+			TlsEnabled: false,
+		}, nil
+	}
+	return &pb.CertificateFingerprintResponse{
+		TlsEnabled:        true,
+		Sha256Fingerprint: hex.EncodeToString(s.certFingerprint),
+	}, nil
+}
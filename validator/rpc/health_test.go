@@ -2,13 +2,18 @@ package rpc
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"testing"
 	"time"
 
 	ptypes "github.com/gogo/protobuf/types"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
 	"github.com/prysmaticlabs/prysm/validator/client"
 )
 
@@ -20,10 +25,12 @@ func (m *mockSyncChecker) Syncing(_ context.Context) (bool, error) {
 	return m.syncing, nil
 }
 
-type mockGenesisFetcher struct{}
+type mockGenesisFetcher struct {
+	genesisTime int64
+}
 
 func (m *mockGenesisFetcher) GenesisInfo(_ context.Context) (*ethpb.Genesis, error) {
-	genesis, err := ptypes.TimestampProto(time.Unix(0, 0))
+	genesis, err := ptypes.TimestampProto(time.Unix(m.genesisTime, 0))
 	if err != nil {
 		log.Info(err)
 		return nil, err
@@ -35,12 +42,18 @@ func (m *mockGenesisFetcher) GenesisInfo(_ context.Context) (*ethpb.Genesis, err
 
 type mockBeaconInfoFetcher struct {
 	endpoint string
+	statuses map[[48]byte]ethpb.ValidatorStatus
+	err      error
 }
 
 func (m *mockBeaconInfoFetcher) BeaconLogsEndpoint(_ context.Context) (string, error) {
 	return m.endpoint, nil
 }
 
+func (m *mockBeaconInfoFetcher) ValidatorStatuses(_ context.Context, _ [][48]byte) (map[[48]byte]ethpb.ValidatorStatus, error) {
+	return m.statuses, m.err
+}
+
 func TestServer_GetBeaconNodeConnection(t *testing.T) {
 	ctx := context.Background()
 	endpoint := "localhost:90210"
@@ -64,6 +77,34 @@ func TestServer_GetBeaconNodeConnection(t *testing.T) {
 	require.DeepEqual(t, want, got)
 }
 
+func TestServer_GetChainTiming(t *testing.T) {
+	ctx := context.Background()
+	secondsPerSlot := params.BeaconConfig().SecondsPerSlot
+	// Genesis was 10 slots and a second ago.
+	genesisTime := timeutils.Now().Unix() - int64(10*secondsPerSlot+1)
+	s := &Server{
+		genesisFetcher: &mockGenesisFetcher{genesisTime: genesisTime},
+	}
+	got, err := s.GetChainTiming(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	want := &pb.ChainTimingResponse{
+		GenesisTime:    uint64(genesisTime),
+		CurrentSlot:    10,
+		SecondsPerSlot: secondsPerSlot,
+	}
+	require.DeepEqual(t, want, got)
+
+	// Before genesis, the chain is at its first slot.
+	s.genesisFetcher = &mockGenesisFetcher{genesisTime: timeutils.Now().Unix() + 100}
+	got, err = s.GetChainTiming(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), got.CurrentSlot)
+
+	s.genesisFetcher = nil
+	_, err = s.GetChainTiming(ctx, &ptypes.Empty{})
+	require.ErrorContains(t, "Validator client not yet started", err)
+}
+
 func TestServer_GetLogsEndpoints(t *testing.T) {
 	ctx := context.Background()
 	s := &Server{
@@ -79,3 +120,23 @@ func TestServer_GetLogsEndpoints(t *testing.T) {
 	}
 	require.DeepEqual(t, want, got)
 }
+
+func TestServer_GetCertificateFingerprint(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{}
+	got, err := s.GetCertificateFingerprint(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, false, got.TlsEnabled)
+	require.Equal(t, "", got.Sha256Fingerprint)
+
+	err = s.setCertificate(&tls.Certificate{})
+	require.ErrorContains(t, "no certificate found", err)
+
+	leaf := []byte("der encoded certificate")
+	require.NoError(t, s.setCertificate(&tls.Certificate{Certificate: [][]byte{leaf, []byte("issuer")}}))
+	got, err = s.GetCertificateFingerprint(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	fingerprint := sha256.Sum256(leaf)
+	require.Equal(t, true, got.TlsEnabled)
+	require.Equal(t, hex.EncodeToString(fingerprint[:]), got.Sha256Fingerprint)
+}
@@ -3,25 +3,48 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/kevinms/leakybucket-go"
+	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// Response header carrying the ID which identifies a request in the access log.
+	requestIDHeader = "x-request-id"
+	// Requests per second allowed to each peer per gRPC method, unless overridden in the config.
+	defaultRateLimit = 20
+	// Requests allowed to each peer per gRPC method in a burst, unless overridden in the config.
+	defaultRateLimitBurst = 40
+	// Requests per second allowed to each peer across the methods which do not require
+	// authentication, unless overridden in the config. It is low enough to make guessing
+	// the wallet password impractical.
+	defaultUnauthenticatedRateLimit = 1
+	// Requests allowed to each peer across the methods which do not require authentication
+	// in a burst, unless overridden in the config.
+	defaultUnauthenticatedRateLimitBurst = 10
+)
+
 // noAuthPaths keeps track of the paths which do not require
 // authentication from our API.
 var (
 	noAuthPaths = map[string]bool{
-		"/ethereum.validator.accounts.v2.Auth/Signup":             true,
-		"/ethereum.validator.accounts.v2.Auth/Login":              true,
-		"/ethereum.validator.accounts.v2.Auth/HasUsedWeb":         true,
-		"/ethereum.validator.accounts.v2.Wallet/HasWallet":        true,
-		"/ethereum.validator.accounts.v2.Wallet/GenerateMnemonic": true,
+		"/ethereum.validator.accounts.v2.Auth/Signup":                      true,
+		"/ethereum.validator.accounts.v2.Auth/Login":                       true,
+		"/ethereum.validator.accounts.v2.Auth/HasUsedWeb":                  true,
+		"/ethereum.validator.accounts.v2.Wallet/HasWallet":                 true,
+		"/ethereum.validator.accounts.v2.Wallet/GenerateMnemonic":          true,
+		"/ethereum.validator.accounts.v2.Health/GetCertificateFingerprint": true,
 	}
 	authLock sync.RWMutex
 )
@@ -51,6 +74,125 @@ func (s *Server) JWTInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// AccessLogInterceptor is a gRPC unary interceptor which logs the method, duration, status
+// code and peer address of every request under a generated request ID. The ID is returned
+// in the x-request-id response header, so a failed request can be found in the log.
+func (s *Server) AccessLogInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		requestID := uuid.New().String()
+		if err := grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID)); err != nil {
+			log.WithError(err).Debug("Could not set request ID header")
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		fields := logrus.Fields{
+			"requestID": requestID,
+			"method":    info.FullMethod,
+			"duration":  time.Since(start),
+			"code":      status.Code(err).String(),
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			fields["peer"] = p.Addr.String()
+		}
+		log.WithFields(fields).Info("Handled RPC request")
+		return resp, err
+	}
+}
+
+// RateLimitInterceptor is a gRPC unary interceptor which rejects requests once they exceed
+// the token bucket of their peer, so a misbehaving client cannot tie up the keymanager.
+// Authenticated requests have a bucket per peer and method, while the methods which do not
+// require authentication share a smaller bucket per peer. It is chained right after the
+// recovery interceptor, so requests are throttled before any work is done on them,
+// including checking their authentication token.
+func (s *Server) RateLimitInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		authLock.RLock()
+		unauthenticated := noAuthPaths[info.FullMethod]
+		authLock.RUnlock()
+		limiter, key := s.rateLimiter, peerHost(ctx)+" "+info.FullMethod
+		if unauthenticated {
+			limiter, key = s.noAuthRateLimiter, peerHost(ctx)
+		}
+		if limiter != nil && limiter.Add(key, 1) < 1 {
+			return nil, status.Errorf(codes.ResourceExhausted, "Rate limit exceeded for %s", info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Creates the token buckets of the rate limit interceptor. Buckets are created per peer,
+// so empty ones are deleted to not accumulate a bucket for every peer ever seen.
+func newRateLimiter(rateLimit float64, burst int64) *leakybucket.Collector {
+	if rateLimit <= 0 {
+		rateLimit = defaultRateLimit
+	}
+	if burst <= 0 {
+		burst = defaultRateLimitBurst
+	}
+	return leakybucket.NewCollector(rateLimit, burst, true /* deleteEmptyBuckets */)
+}
+
+// Creates the token buckets shared by the methods which do not require authentication,
+// falling back to their own, lower defaults.
+func newNoAuthRateLimiter(rateLimit float64, burst int64) *leakybucket.Collector {
+	if rateLimit <= 0 {
+		rateLimit = defaultUnauthenticatedRateLimit
+	}
+	if burst <= 0 {
+		burst = defaultUnauthenticatedRateLimitBurst
+	}
+	return newRateLimiter(rateLimit, burst)
+}
+
+// Identifies the client of a request by the host of its address, as each of its
+// connections comes from a different port. Requests over a unix socket share one key.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// JWTStreamInterceptor is a gRPC stream interceptor to authorize incoming streams
+// for methods that are NOT in the noAuthPaths configuration map.
+func (s *Server) JWTStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		authLock.RLock()
+		shouldAuthenticate := !noAuthPaths[info.FullMethod]
+		authLock.RUnlock()
+		if shouldAuthenticate {
+			if err := s.authorize(stream.Context()); err != nil {
+				return err
+			}
+		}
+
+		err := handler(srv, stream)
+		log.Debugf("Stream - Method: %s, Error: %v\n", info.FullMethod, err)
+		return err
+	}
+}
+
 // Authorize the token received is valid.
 func (s *Server) authorize(ctx context.Context) error {
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -77,5 +219,7 @@ func (s *Server) validateJWT(token *jwt.Token) (interface{}, error) {
 	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 		return nil, fmt.Errorf("unexpected JWT signing method: %v", token.Header["alg"])
 	}
+	s.jwtKeyLock.RLock()
+	defer s.jwtKeyLock.RUnlock()
 	return s.jwtKey, nil
 }
@@ -2,13 +2,24 @@ package rpc
 
 import (
 	"context"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	ptypes "github.com/gogo/protobuf/types"
+	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 	"github.com/prysmaticlabs/prysm/shared/timeutils"
+	"github.com/sirupsen/logrus"
+	logTest "github.com/sirupsen/logrus/hooks/test"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 func TestServer_JWTInterceptor_Verify(t *testing.T) {
@@ -71,3 +82,207 @@ func TestServer_JWTInterceptor_InvalidSigningType(t *testing.T) {
 	_, err := ss.validateJWT(token)
 	require.ErrorContains(t, "unexpected JWT signing method", err)
 }
+
+func TestServer_AccessLogInterceptor(t *testing.T) {
+	hook := logTest.NewGlobal()
+	s := NewServer(context.Background(), &Config{
+		Host: "127.0.0.1",
+		Port: "0",
+	})
+	s.Start()
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, s.listener.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	// Returns the access log entry of the request with the ID in the response header.
+	accessLog := func(header metadata.MD) *logrus.Entry {
+		requestIDs := header.Get(requestIDHeader)
+		require.Equal(t, 1, len(requestIDs), "Response has no request ID header")
+		for _, entry := range hook.AllEntries() {
+			if entry.Data["requestID"] == requestIDs[0] {
+				return entry
+			}
+		}
+		t.Fatalf("No access log for request %s", requestIDs[0])
+		return nil
+	}
+
+	var header metadata.MD
+	_, err = pb.NewHealthClient(conn).GetCertificateFingerprint(ctx, &ptypes.Empty{}, grpc.Header(&header))
+	require.NoError(t, err)
+	entry := accessLog(header)
+	assert.Equal(t, "Handled RPC request", entry.Message)
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "/ethereum.validator.accounts.v2.Health/GetCertificateFingerprint", entry.Data["method"])
+	assert.Equal(t, codes.OK.String(), entry.Data["code"])
+	peerAddr, ok := entry.Data["peer"].(string)
+	require.Equal(t, true, ok, "Access log has no peer address")
+	assert.Equal(t, true, strings.HasPrefix(peerAddr, "127.0.0.1:"), "Unexpected peer address %s", peerAddr)
+	_, ok = entry.Data["duration"].(time.Duration)
+	assert.Equal(t, true, ok, "Access log has no duration")
+
+	// Requests rejected by the interceptors further down the chain are logged too.
+	header = nil
+	_, err = pb.NewAccountsClient(conn).ListAccounts(ctx, &pb.ListAccountsRequest{}, grpc.Header(&header))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, codes.Unauthenticated.String(), accessLog(header).Data["code"])
+}
+
+func TestServer_RateLimitInterceptor(t *testing.T) {
+	s := &Server{
+		rateLimiter:       newRateLimiter(1, 3),
+		noAuthRateLimiter: newRateLimiter(1, 2),
+	}
+	interceptor := s.RateLimitInterceptor()
+	unaryHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	peerContext := func(addr string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 4000}})
+	}
+	createWallet := &grpc.UnaryServerInfo{FullMethod: "Proto.CreateWallet"}
+
+	throttled := 0
+	for i := 0; i < 10; i++ {
+		_, err := interceptor(peerContext("10.0.0.1"), "xyz", createWallet, unaryHandler)
+		if err != nil {
+			require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			throttled++
+		}
+	}
+	require.Equal(t, 7, throttled, "Requests beyond the burst were not throttled")
+
+	// Every peer has a token bucket of its own for every method.
+	_, err := interceptor(peerContext("10.0.0.1"), "xyz", &grpc.UnaryServerInfo{FullMethod: "Proto.ListAccounts"}, unaryHandler)
+	require.NoError(t, err)
+	_, err = interceptor(peerContext("10.0.0.2"), "xyz", createWallet, unaryHandler)
+	require.NoError(t, err)
+
+	// The methods which do not require authentication share a smaller bucket per peer.
+	login := &grpc.UnaryServerInfo{FullMethod: "/ethereum.validator.accounts.v2.Auth/Login"}
+	signup := &grpc.UnaryServerInfo{FullMethod: "/ethereum.validator.accounts.v2.Auth/Signup"}
+	_, err = interceptor(peerContext("10.0.0.1"), "xyz", login, unaryHandler)
+	require.NoError(t, err)
+	_, err = interceptor(peerContext("10.0.0.1"), "xyz", signup, unaryHandler)
+	require.NoError(t, err)
+	_, err = interceptor(peerContext("10.0.0.1"), "xyz", login, unaryHandler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = interceptor(peerContext("10.0.0.2"), "xyz", login, unaryHandler)
+	require.NoError(t, err)
+}
+
+func TestServer_RateLimitInterceptor_Disabled(t *testing.T) {
+	s := NewServer(context.Background(), &Config{DisableRateLimit: true})
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	interceptor := s.RateLimitInterceptor()
+	unaryHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4000}})
+	for i := 0; i < 2*defaultRateLimitBurst; i++ {
+		_, err := interceptor(ctx, "xyz", &grpc.UnaryServerInfo{FullMethod: "Proto.CreateWallet"}, unaryHandler)
+		require.NoError(t, err)
+	}
+
+	// Logging in is not rate limited either.
+	login := &grpc.UnaryServerInfo{FullMethod: "/ethereum.validator.accounts.v2.Auth/Login"}
+	for i := 0; i < 2*defaultUnauthenticatedRateLimitBurst; i++ {
+		_, err := interceptor(ctx, "xyz", login, unaryHandler)
+		require.NoError(t, err)
+	}
+}
+
+func TestServer_RateLimitInterceptor_Config(t *testing.T) {
+	s := NewServer(context.Background(), &Config{NoAuthRateLimit: 1, NoAuthRateLimitBurst: 2})
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	interceptor := s.RateLimitInterceptor()
+	unaryHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4000}})
+	login := &grpc.UnaryServerInfo{FullMethod: "/ethereum.validator.accounts.v2.Auth/Login"}
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(ctx, "xyz", login, unaryHandler)
+		require.NoError(t, err)
+	}
+	_, err := interceptor(ctx, "xyz", login, unaryHandler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestServer_RateLimitInterceptor_BeforeAuthentication(t *testing.T) {
+	s := NewServer(context.Background(), &Config{
+		Host:           "127.0.0.1",
+		Port:           "0",
+		RateLimit:      1,
+		RateLimitBurst: 1,
+	})
+	s.Start()
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, s.listener.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+
+	// Requests are throttled before their authentication token is checked, so guessing
+	// tokens is rate limited too.
+	_, err = pb.NewAccountsClient(conn).ListAccounts(ctx, &pb.ListAccountsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = pb.NewAccountsClient(conn).ListAccounts(ctx, &pb.ListAccountsRequest{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (m *mockServerStream) Context() context.Context {
+	return m.ctx
+}
+
+func TestServer_JWTStreamInterceptor(t *testing.T) {
+	s := Server{
+		jwtKey: []byte("testKey"),
+	}
+	interceptor := s.JWTStreamInterceptor()
+
+	streamInfo := &grpc.StreamServerInfo{
+		FullMethod: "Proto.StreamValidatorStatusChanges",
+	}
+	streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+	badServer := Server{
+		jwtKey: []byte("badTestKey"),
+	}
+	badToken, _, err := badServer.createTokenString()
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(), map[string][]string{
+		"authorization": {"Bearer " + badToken},
+	})
+	err = interceptor("xyz", &mockServerStream{ctx: ctx}, streamInfo, streamHandler)
+	require.ErrorContains(t, "signature is invalid", err)
+
+	token, _, err := s.createTokenString()
+	require.NoError(t, err)
+	ctx = metadata.NewIncomingContext(context.Background(), map[string][]string{
+		"authorization": {"Bearer " + token},
+	})
+	err = interceptor("xyz", &mockServerStream{ctx: ctx}, streamInfo, streamHandler)
+	require.NoError(t, err)
+}
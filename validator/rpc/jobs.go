@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/rand"
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The number of finished jobs kept so the outcome of recent operations can still be listed.
+const maxFinishedJobs = 16
+
+// A long running operation started through the RPC server, which reports its progress
+// and can be cancelled while it runs.
+type job struct {
+	id          string
+	description string
+	startedAt   time.Time
+	cancelFunc  context.CancelFunc
+
+	lock      sync.RWMutex
+	state     pb.Job_State
+	progress  uint64
+	total     uint64
+	cancelled bool
+	err       error
+}
+
+// Records the number of steps of the operation completed so far.
+func (j *job) setProgress(progress uint64) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.progress = progress
+}
+
+func (j *job) running() bool {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	return j.state == pb.Job_RUNNING
+}
+
+func (j *job) toProto() *pb.Job {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	pbJob := &pb.Job{
+		Id:          j.id,
+		Description: j.description,
+		State:       j.state,
+		Progress:    j.progress,
+		Total:       j.total,
+		StartedAt:   uint64(j.startedAt.Unix()),
+	}
+	if j.err != nil {
+		pbJob.Error = j.err.Error()
+	}
+	return pbJob
+}
+
+// Tracks the running and recently finished jobs of the RPC server.
+type jobTracker struct {
+	lock sync.RWMutex
+	jobs []*job
+}
+
+// Registers a long running operation with the given total number of steps as a running job.
+// The operation must run with the returned context, which is canceled if the job is cancelled,
+// and report its outcome with finish.
+func (t *jobTracker) start(ctx context.Context, description string, total uint64) (context.Context, *job) {
+	ctx, cancel := context.WithCancel(ctx)
+	j := &job{
+		id:          fmt.Sprintf("%016x", rand.NewGenerator().Uint64()),
+		description: description,
+		startedAt:   timeutils.Now(),
+		cancelFunc:  cancel,
+		state:       pb.Job_RUNNING,
+		total:       total,
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.jobs = append(t.jobs, j)
+	return ctx, j
+}
+
+// Records the outcome of a job once its operation returns, evicting the oldest
+// finished jobs beyond the number kept.
+func (t *jobTracker) finish(j *job, err error) {
+	j.lock.Lock()
+	switch {
+	case err == nil:
+		j.state = pb.Job_COMPLETED
+		j.progress = j.total
+	case j.cancelled:
+		j.state = pb.Job_CANCELLED
+		j.err = err
+	default:
+		j.state = pb.Job_FAILED
+		j.err = err
+	}
+	j.lock.Unlock()
+	j.cancelFunc()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	finished := 0
+	for i := len(t.jobs) - 1; i >= 0; i-- {
+		if t.jobs[i].running() {
+			continue
+		}
+		finished++
+		if finished > maxFinishedJobs {
+			t.jobs = append(t.jobs[:i], t.jobs[i+1:]...)
+		}
+	}
+}
+
+// Cancels the running job with the given id, which stops its operation.
+func (t *jobTracker) cancel(id string) error {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	for _, j := range t.jobs {
+		if j.id != id {
+			continue
+		}
+		j.lock.Lock()
+		defer j.lock.Unlock()
+		if j.state != pb.Job_RUNNING {
+			return status.Errorf(codes.FailedPrecondition, "Job %s is no longer running", id)
+		}
+		j.cancelled = true
+		j.cancelFunc()
+		return nil
+	}
+	return status.Errorf(codes.NotFound, "No job with id %s", id)
+}
+
+// Returns the tracked jobs, most recently started first.
+func (t *jobTracker) list() []*pb.Job {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	jobs := make([]*pb.Job, len(t.jobs))
+	for i, j := range t.jobs {
+		jobs[len(t.jobs)-1-i] = j.toProto()
+	}
+	return jobs
+}
+
+// ListJobs lists the long running operations started through the validator RPC
+// server which are still running or finished recently, along with their progress.
+func (s *Server) ListJobs(_ context.Context, _ *ptypes.Empty) (*pb.ListJobsResponse, error) {
+	return &pb.ListJobsResponse{
+		Jobs: s.jobs.list(),
+	}, nil
+}
+
+// CancelJob aborts a running long running operation started through the validator RPC server.
+func (s *Server) CancelJob(_ context.Context, req *pb.CancelJobRequest) (*ptypes.Empty, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "Job id is required")
+	}
+	if err := s.jobs.cancel(req.Id); err != nil {
+		return nil, err
+	}
+	return &ptypes.Empty{}, nil
+}
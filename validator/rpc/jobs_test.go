@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestServer_ListJobs_ReportsProgress(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{}
+
+	_, j := s.jobs.start(ctx, "Simulated job", 4)
+	j.setProgress(1)
+	resp, err := s.ListJobs(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(resp.Jobs))
+	assert.Equal(t, "Simulated job", resp.Jobs[0].Description)
+	assert.Equal(t, pb.Job_RUNNING, resp.Jobs[0].State)
+	assert.Equal(t, uint64(1), resp.Jobs[0].Progress)
+	assert.Equal(t, uint64(4), resp.Jobs[0].Total)
+
+	s.jobs.finish(j, nil)
+	_, failed := s.jobs.start(ctx, "Failing job", 1)
+	s.jobs.finish(failed, errors.New("something went wrong"))
+
+	// The most recently started job is listed first.
+	resp, err = s.ListJobs(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resp.Jobs))
+	assert.Equal(t, pb.Job_FAILED, resp.Jobs[0].State)
+	assert.Equal(t, "something went wrong", resp.Jobs[0].Error)
+	assert.Equal(t, pb.Job_COMPLETED, resp.Jobs[1].State)
+	assert.Equal(t, uint64(4), resp.Jobs[1].Progress)
+}
+
+func TestServer_ListJobs_EvictsOldFinishedJobs(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{}
+
+	_, running := s.jobs.start(ctx, "Running job", 1)
+	for i := 0; i < maxFinishedJobs+5; i++ {
+		_, j := s.jobs.start(ctx, "Finished job", 1)
+		s.jobs.finish(j, nil)
+	}
+	resp, err := s.ListJobs(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, maxFinishedJobs+1, len(resp.Jobs))
+	assert.Equal(t, running.id, resp.Jobs[len(resp.Jobs)-1].Id)
+}
+
+func TestServer_CancelJob(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{}
+
+	// Simulate a long running job which stops once its context is canceled.
+	jobCtx, j := s.jobs.start(ctx, "Simulated job", 1000)
+	stopped := make(chan struct{})
+	go func() {
+		var progress uint64
+		for jobCtx.Err() == nil {
+			progress++
+			j.setProgress(progress)
+			time.Sleep(time.Millisecond)
+		}
+		s.jobs.finish(j, jobCtx.Err())
+		close(stopped)
+	}()
+
+	_, err := s.CancelJob(ctx, &pb.CancelJobRequest{Id: j.id})
+	require.NoError(t, err)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Job did not stop after it was cancelled")
+	}
+	resp, err := s.ListJobs(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(resp.Jobs))
+	assert.Equal(t, pb.Job_CANCELLED, resp.Jobs[0].State)
+
+	_, err = s.CancelJob(ctx, &pb.CancelJobRequest{Id: j.id})
+	assert.ErrorContains(t, "no longer running", err)
+	_, err = s.CancelJob(ctx, &pb.CancelJobRequest{Id: "unknown"})
+	assert.ErrorContains(t, "No job with id", err)
+	_, err = s.CancelJob(ctx, &pb.CancelJobRequest{})
+	assert.ErrorContains(t, "Job id is required", err)
+}
@@ -2,16 +2,27 @@ package rpc
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
 
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/kevinms/leakybucket-go"
+	"github.com/pkg/errors"
 	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
 	"github.com/prysmaticlabs/prysm/shared/event"
+	"github.com/prysmaticlabs/prysm/shared/fileutil"
 	"github.com/prysmaticlabs/prysm/shared/rand"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"github.com/prysmaticlabs/prysm/validator/accounts/wallet"
@@ -22,11 +33,28 @@ import (
 	"go.opencensus.io/plugin/ocgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
 var log logrus.FieldLogger
 
+const (
+	// How long Stop waits for in flight RPCs to complete before forcing the server to stop,
+	// unless overridden in the config.
+	defaultShutdownTimeout = 5 * time.Second
+	// Maximum size of a message sent or received by the server, unless overridden in the
+	// config. It is well above the 4MB gRPC default, so that large batches of keystores
+	// can be imported in a single request.
+	defaultMaxMsgSize = 32 << 20
+	// How long a connection may be silent before the server pings the client, unless
+	// overridden in the config. Regular pings keep intermediaries from dropping idle connections.
+	defaultKeepaliveTime = time.Minute
+	// How long the server waits for a ping to be acknowledged before closing the connection,
+	// unless overridden in the config.
+	defaultKeepaliveTimeout = 20 * time.Second
+)
+
 func init() {
 	log = logrus.WithField("prefix", "rpc")
 }
@@ -39,18 +67,37 @@ type Config struct {
 	ValidatorMonitoringPort int
 	Host                    string
 	Port                    string
+	UnixSocketPath          string
+	MaxConnectionIdle       time.Duration
+	ShutdownTimeout         time.Duration
+	MaxMsgSize              int
+	KeepaliveTime           time.Duration
+	KeepaliveTimeout        time.Duration
+	RateLimit               float64
+	RateLimitBurst          int64
+	NoAuthRateLimit         float64
+	NoAuthRateLimitBurst    int64
+	DisableRateLimit        bool
 	CertFlag                string
 	KeyFlag                 string
+	ClientCAFlag            string
+	JWTSecretFile           string
 	ValDB                   db.Database
 	WalletDir               string
 	ValidatorService        *client.ValidatorService
 	SyncChecker             client.SyncChecker
 	GenesisFetcher          client.GenesisFetcher
 	BeaconNodeInfoFetcher   client.BeaconNodeInfoFetcher
+	DutyCountdownFetcher    client.DutyCountdownFetcher
+	DutyCountFetcher        client.DutyCountFetcher
+	InclusionRateFetcher    client.InclusionRateFetcher
+	MissedDutyFetcher       client.MissedDutyFetcher
+	SigningDomainFetcher    client.SigningDomainFetcher
 	WalletInitializedFeed   *event.Feed
 	NodeGatewayEndpoint     string
 	Wallet                  *wallet.Wallet
 	Keymanager              keymanager.IKeymanager
+	MaxWalletSize           int
 }
 
 // Server defining a gRPC server for the remote signer API.
@@ -60,17 +107,35 @@ type Server struct {
 	cancel                  context.CancelFunc
 	host                    string
 	port                    string
+	unixSocketPath          string
+	maxConnectionIdle       time.Duration
+	shutdownTimeout         time.Duration
+	maxMsgSize              int
+	keepaliveTime           time.Duration
+	keepaliveTimeout        time.Duration
+	rateLimiter             *leakybucket.Collector
+	noAuthRateLimiter       *leakybucket.Collector
 	listener                net.Listener
 	keymanager              keymanager.IKeymanager
 	withCert                string
 	withKey                 string
+	withClientCA            string
+	certLock                sync.RWMutex
+	certFingerprint         []byte
 	credentialError         error
 	grpcServer              *grpc.Server
+	jwtKeyLock              sync.RWMutex
 	jwtKey                  []byte
+	jwtSecretFile           string
 	validatorService        *client.ValidatorService
 	syncChecker             client.SyncChecker
 	genesisFetcher          client.GenesisFetcher
 	beaconNodeInfoFetcher   client.BeaconNodeInfoFetcher
+	dutyCountdownFetcher    client.DutyCountdownFetcher
+	dutyCountFetcher        client.DutyCountFetcher
+	inclusionRateFetcher    client.InclusionRateFetcher
+	missedDutyFetcher       client.MissedDutyFetcher
+	signingDomainFetcher    client.SigningDomainFetcher
 	walletDir               string
 	wallet                  *wallet.Wallet
 	walletInitializedFeed   *event.Feed
@@ -80,23 +145,47 @@ type Server struct {
 	validatorMonitoringPort int
 	validatorGatewayHost    string
 	validatorGatewayPort    int
+	maxWalletSize           int
+	jobs                    jobTracker
+	validatorStatuses       validatorStatusTracker
+	signBenchmarkRunning    uint32
 }
 
 // NewServer instantiates a new gRPC server.
 func NewServer(ctx context.Context, cfg *Config) *Server {
 	ctx, cancel := context.WithCancel(ctx)
+	var rateLimiter, noAuthRateLimiter *leakybucket.Collector
+	if !cfg.DisableRateLimit {
+		rateLimiter = newRateLimiter(cfg.RateLimit, cfg.RateLimitBurst)
+		noAuthRateLimiter = newNoAuthRateLimiter(cfg.NoAuthRateLimit, cfg.NoAuthRateLimitBurst)
+	}
 	return &Server{
 		ctx:                     ctx,
 		cancel:                  cancel,
 		host:                    cfg.Host,
 		port:                    cfg.Port,
+		unixSocketPath:          cfg.UnixSocketPath,
+		maxConnectionIdle:       cfg.MaxConnectionIdle,
+		shutdownTimeout:         cfg.ShutdownTimeout,
+		maxMsgSize:              cfg.MaxMsgSize,
+		keepaliveTime:           cfg.KeepaliveTime,
+		keepaliveTimeout:        cfg.KeepaliveTimeout,
+		rateLimiter:             rateLimiter,
+		noAuthRateLimiter:       noAuthRateLimiter,
 		withCert:                cfg.CertFlag,
 		withKey:                 cfg.KeyFlag,
+		withClientCA:            cfg.ClientCAFlag,
+		jwtSecretFile:           cfg.JWTSecretFile,
 		valDB:                   cfg.ValDB,
 		validatorService:        cfg.ValidatorService,
 		syncChecker:             cfg.SyncChecker,
 		beaconNodeInfoFetcher:   cfg.BeaconNodeInfoFetcher,
 		genesisFetcher:          cfg.GenesisFetcher,
+		dutyCountdownFetcher:    cfg.DutyCountdownFetcher,
+		dutyCountFetcher:        cfg.DutyCountFetcher,
+		inclusionRateFetcher:    cfg.InclusionRateFetcher,
+		missedDutyFetcher:       cfg.MissedDutyFetcher,
+		signingDomainFetcher:    cfg.SigningDomainFetcher,
 		walletDir:               cfg.WalletDir,
 		walletInitializedFeed:   cfg.WalletInitializedFeed,
 		walletInitialized:       cfg.Wallet != nil,
@@ -107,6 +196,7 @@ func NewServer(ctx context.Context, cfg *Config) *Server {
 		validatorMonitoringPort: cfg.ValidatorMonitoringPort,
 		validatorGatewayHost:    cfg.ValidatorGatewayHost,
 		validatorGatewayPort:    cfg.ValidatorGatewayPort,
+		maxWalletSize:           cfg.MaxWalletSize,
 	}
 }
 
@@ -114,46 +204,92 @@ func NewServer(ctx context.Context, cfg *Config) *Server {
 func (s *Server) Start() {
 	// Setup the gRPC server options and TLS configuration.
 	address := fmt.Sprintf("%s:%s", s.host, s.port)
-	lis, err := net.Listen("tcp", address)
+	var lis net.Listener
+	var err error
+	if s.unixSocketPath != "" {
+		// Serve over a unix socket only, so filesystem permissions control access.
+		address = s.unixSocketPath
+		lis, err = listenUnixSocket(address)
+	} else {
+		lis, err = net.Listen("tcp", address)
+	}
 	if err != nil {
 		log.Errorf("Could not listen to port in Start() %s: %v", address, err)
 	}
 	s.listener = lis
 
-	// Register interceptors for metrics gathering as well as our
-	// own, custom JWT unary interceptor.
+	// Register interceptors for access logging, rate limiting and metrics gathering
+	// as well as our own, custom JWT unary interceptor.
 	opts := []grpc.ServerOption{
 		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
 		grpc.UnaryInterceptor(middleware.ChainUnaryServer(
 			recovery.UnaryServerInterceptor(
 				recovery.WithRecoveryHandlerContext(traceutil.RecoveryHandlerFunc),
 			),
+			s.RateLimitInterceptor(),
+			s.AccessLogInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
 			grpc_opentracing.UnaryServerInterceptor(),
 			s.JWTInterceptor(),
 		)),
+		grpc.StreamInterceptor(middleware.ChainStreamServer(
+			recovery.StreamServerInterceptor(
+				recovery.WithRecoveryHandlerContext(traceutil.RecoveryHandlerFunc),
+			),
+			grpc_prometheus.StreamServerInterceptor,
+			grpc_opentracing.StreamServerInterceptor(),
+			s.JWTStreamInterceptor(),
+		)),
 	}
 	grpc_prometheus.EnableHandlingTimeHistogram()
+	maxMsgSize := s.maxMsgSize
+	if maxMsgSize <= 0 {
+		maxMsgSize = defaultMaxMsgSize
+	}
+	keepaliveTime := s.keepaliveTime
+	if keepaliveTime <= 0 {
+		keepaliveTime = defaultKeepaliveTime
+	}
+	keepaliveTimeout := s.keepaliveTimeout
+	if keepaliveTimeout <= 0 {
+		keepaliveTimeout = defaultKeepaliveTimeout
+	}
+	opts = append(opts,
+		grpc.MaxRecvMsgSize(maxMsgSize),
+		grpc.MaxSendMsgSize(maxMsgSize),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			// Close connections left open by idle clients, such as an abandoned web UI
+			// session. A zero duration never closes idle connections.
+			MaxConnectionIdle: s.maxConnectionIdle,
+			Time:              keepaliveTime,
+			Timeout:           keepaliveTimeout,
+		}),
+	)
 
 	if s.withCert != "" && s.withKey != "" {
-		creds, err := credentials.NewServerTLSFromFile(s.withCert, s.withKey)
+		creds, err := s.transportCredentials()
 		if err != nil {
 			log.WithError(err).Fatal("Could not load TLS keys")
 		}
 		opts = append(opts, grpc.Creds(creds))
 		log.WithFields(logrus.Fields{
-			"crt-path": s.withCert,
-			"key-path": s.withKey,
+			"crt-path":       s.withCert,
+			"key-path":       s.withKey,
+			"client-ca-path": s.withClientCA,
 		}).Info("Loaded TLS certificates")
+	} else if s.withClientCA != "" {
+		log.Fatal("Client certificate authentication requires a TLS certificate and key")
 	}
 	s.grpcServer = grpc.NewServer(opts...)
 
-	// We create a new, random JWT key upon validator startup.
-	jwtKey, err := createRandomJWTKey()
+	// We create a new, random JWT key upon validator startup, unless one was persisted.
+	jwtKey, err := s.initializeJWTKey()
 	if err != nil {
 		log.WithError(err).Fatal("Could not initialize validator jwt key")
 	}
+	s.jwtKeyLock.Lock()
 	s.jwtKey = jwtKey
+	s.jwtKeyLock.Unlock()
 
 	// Register services available for the gRPC server.
 	reflection.Register(s.grpcServer)
@@ -161,6 +297,7 @@ func (s *Server) Start() {
 	pb.RegisterWalletServer(s.grpcServer, s)
 	pb.RegisterHealthServer(s.grpcServer, s)
 	pb.RegisterAccountsServer(s.grpcServer, s)
+	pb.RegisterJobsServer(s.grpcServer, s)
 
 	go func() {
 		if s.listener != nil {
@@ -170,15 +307,45 @@ func (s *Server) Start() {
 		}
 	}()
 	go s.checkUserSignup(s.ctx)
+	if s.walletInitialized && s.keymanager != nil && s.valDB != nil {
+		go func() {
+			if err := s.checkSlashingProtectionKeys(s.ctx); err != nil {
+				log.WithError(err).Error("Could not check slashing protection history of validating keys")
+			}
+		}()
+	}
+	go s.pollValidatorStatuses(s.ctx)
 	log.WithField("address", address).Info("gRPC server listening on address")
 }
 
-// Stop the gRPC server.
+// Stop the gRPC server. It waits for in flight RPCs to complete, but forces the server to
+// stop once the shutdown timeout elapses, so a stuck stream cannot hang validator shutdown.
 func (s *Server) Stop() error {
 	s.cancel()
 	if s.listener != nil {
-		s.grpcServer.GracefulStop()
-		log.Debug("Initiated graceful stop of server")
+		timeout := s.shutdownTimeout
+		if timeout <= 0 {
+			timeout = defaultShutdownTimeout
+		}
+		stopped := make(chan struct{})
+		go func() {
+			s.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			log.Debug("Gracefully stopped server")
+		case <-time.After(timeout):
+			s.grpcServer.Stop()
+			log.WithField("timeout", timeout).Warn("Forced server to stop as RPCs did not complete in time")
+		}
+	}
+	// Stop pruning the empty token buckets.
+	if s.rateLimiter != nil {
+		s.rateLimiter.Free()
+	}
+	if s.noAuthRateLimiter != nil {
+		s.noAuthRateLimiter.Free()
 	}
 	return nil
 }
@@ -188,9 +355,79 @@ func (s *Server) Status() error {
 	return s.credentialError
 }
 
+// transportCredentials loads the TLS certificate served by the server. When a client CA
+// is configured, clients must also present a certificate signed by it (mutual TLS).
+func (s *Server) transportCredentials() (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(s.withCert, s.withKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setCertificate(&cert); err != nil {
+		return nil, err
+	}
+	tlsCfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if s.withClientCA != "" {
+		caCert, err := ioutil.ReadFile(s.withClientCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read client CA certificate")
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("no PEM encoded certificate found in client CA file %s", s.withClientCA)
+		}
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsCfg.ClientCAs = clientCAs
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// setCertificate records the fingerprint of the TLS certificate served by the server,
+// which clients pinning the certificate can retrieve via GetCertificateFingerprint.
+func (s *Server) setCertificate(cert *tls.Certificate) error {
+	if len(cert.Certificate) == 0 {
+		return errors.New("no certificate found in TLS certificate chain")
+	}
+	fingerprint := sha256.Sum256(cert.Certificate[0])
+	s.certLock.Lock()
+	s.certFingerprint = fingerprint[:]
+	s.certLock.Unlock()
+	return nil
+}
+
+// Listens on a unix socket which only its owner can use, replacing the socket file left
+// behind by a process which did not shut down cleanly. The socket is created with the
+// permissions of the process umask, so its directory must not be accessible by other
+// users either, or they could connect before the socket permissions are restricted.
+func listenUnixSocket(path string) (net.Listener, error) {
+	if err := fileutil.MkdirAll(filepath.Dir(path)); err != nil {
+		return nil, errors.Wrap(err, "could not create unix socket directory")
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, errors.Errorf("%s exists and is not a unix socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, errors.Wrap(err, "could not remove stale unix socket")
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0700); err != nil {
+		if closeErr := lis.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close unix socket")
+		}
+		return nil, errors.Wrap(err, "could not restrict unix socket permissions")
+	}
+	return lis, nil
+}
+
 func createRandomJWTKey() ([]byte, error) {
 	r := rand.NewGenerator()
-	jwtKey := make([]byte, 32)
+	jwtKey := make([]byte, jwtKeyLength)
 	n, err := r.Read(jwtKey)
 	if err != nil {
 		return nil, err
@@ -200,3 +437,50 @@ func createRandomJWTKey() ([]byte, error) {
 	}
 	return jwtKey, nil
 }
+
+// Loads the JWT key from the JWT secret file, so that auth tokens issued before a restart
+// remain valid. Without a secret file, or when it does not exist yet, a random key is
+// created, and written to the secret file if there is one.
+func (s *Server) initializeJWTKey() ([]byte, error) {
+	if s.jwtSecretFile != "" && fileutil.FileExists(s.jwtSecretFile) {
+		info, err := os.Stat(s.jwtSecretFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read JWT secret file")
+		}
+		// Anyone who can read the key can authenticate to the validator RPC. Windows does
+		// not report unix permissions, so the check only applies elsewhere.
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+			return nil, errors.Errorf("JWT secret file %s is accessible by other users, expected 0600 permissions", s.jwtSecretFile)
+		}
+		jwtKey, err := fileutil.ReadFileAsBytes(s.jwtSecretFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read JWT secret file")
+		}
+		if len(jwtKey) != jwtKeyLength {
+			return nil, errors.Errorf("JWT secret file %s must contain a %d byte key", s.jwtSecretFile, jwtKeyLength)
+		}
+		return jwtKey, nil
+	}
+	jwtKey, err := createRandomJWTKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveJWTKey(jwtKey); err != nil {
+		return nil, err
+	}
+	return jwtKey, nil
+}
+
+// Writes the JWT key to the JWT secret file, readable by the owner only, if there is one.
+func (s *Server) saveJWTKey(jwtKey []byte) error {
+	if s.jwtSecretFile == "" {
+		return nil
+	}
+	if err := fileutil.MkdirAll(filepath.Dir(s.jwtSecretFile)); err != nil {
+		return errors.Wrap(err, "could not create JWT secret file directory")
+	}
+	if err := fileutil.WriteFile(s.jwtSecretFile, jwtKey); err != nil {
+		return errors.Wrap(err, "could not write JWT secret file")
+	}
+	return nil
+}
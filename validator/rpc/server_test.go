@@ -1,7 +1,411 @@
 package rpc
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
 	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/fileutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 var _ pb.AuthServer = (*Server)(nil)
+
+// Creates a certificate for 127.0.0.1 signed by the parent certificate, or self-signed if
+// there is no parent, and writes it and its key as PEM files to dir.
+func writeTestCertificate(
+	t *testing.T, dir, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, name+".crt")
+	keyPath := filepath.Join(dir, name+".key")
+	require.NoError(t, ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return cert, key, certPath, keyPath
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caPath, _ := writeTestCertificate(t, dir, "ca", true, nil, nil)
+	_, _, serverCertPath, serverKeyPath := writeTestCertificate(t, dir, "server", false, ca, caKey)
+	_, _, clientCertPath, clientKeyPath := writeTestCertificate(t, dir, "client", false, ca, caKey)
+	_, _, rogueCertPath, rogueKeyPath := writeTestCertificate(t, dir, "rogue", false, nil, nil)
+
+	s := NewServer(context.Background(), &Config{
+		Host:         "127.0.0.1",
+		Port:         "0",
+		CertFlag:     serverCertPath,
+		KeyFlag:      serverKeyPath,
+		ClientCAFlag: caPath,
+	})
+	s.Start()
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	address := s.listener.Addr().String()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	dial := func(certificates []tls.Certificate) (*pb.CertificateFingerprintResponse, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:      roots,
+			Certificates: certificates,
+		})))
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+		return pb.NewHealthClient(conn).GetCertificateFingerprint(ctx, &ptypes.Empty{})
+	}
+
+	clientCert, err := tls.LoadX509KeyPair(clientCertPath, clientKeyPath)
+	require.NoError(t, err)
+	resp, err := dial([]tls.Certificate{clientCert})
+	require.NoError(t, err)
+	assert.Equal(t, true, resp.TlsEnabled)
+
+	_, err = dial(nil)
+	assert.NotNil(t, err, "Client without a certificate was accepted")
+
+	rogueCert, err := tls.LoadX509KeyPair(rogueCertPath, rogueKeyPath)
+	require.NoError(t, err)
+	_, err = dial([]tls.Certificate{rogueCert})
+	assert.NotNil(t, err, "Client with a certificate not signed by the client CA was accepted")
+}
+
+func TestServer_TransportCredentials_ClientCA(t *testing.T) {
+	dir := t.TempDir()
+	_, _, certPath, keyPath := writeTestCertificate(t, dir, "server", false, nil, nil)
+	s := &Server{
+		withCert:     certPath,
+		withKey:      keyPath,
+		withClientCA: filepath.Join(dir, "missing.crt"),
+	}
+	_, err := s.transportCredentials()
+	assert.ErrorContains(t, "could not read client CA certificate", err)
+
+	s.withClientCA = keyPath
+	_, err = s.transportCredentials()
+	assert.ErrorContains(t, "no PEM encoded certificate found in client CA file", err)
+
+	s.withClientCA = certPath
+	_, err = s.transportCredentials()
+	require.NoError(t, err)
+	assert.NotNil(t, s.certFingerprint)
+}
+
+func TestServer_JWTSecretFile_PersistsAcrossRestarts(t *testing.T) {
+	walletDir := filepath.Join(t.TempDir(), "wallet")
+	jwtSecretFile := filepath.Join(walletDir, JWTSecretFileName)
+	startServer := func(jwtSecretFile string) *Server {
+		s := NewServer(context.Background(), &Config{
+			Host:          "127.0.0.1",
+			Port:          "0",
+			WalletDir:     walletDir,
+			JWTSecretFile: jwtSecretFile,
+		})
+		s.Start()
+		return s
+	}
+	authorize := func(s *Server, token string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		return s.authorize(ctx)
+	}
+
+	s := startServer(jwtSecretFile)
+	token, _, err := s.createTokenString()
+	require.NoError(t, err)
+	require.NoError(t, s.Stop())
+	info, err := os.Stat(jwtSecretFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "JWT secret file is not private")
+
+	// A restarted server reloads the key, so the token issued before the restart is still valid.
+	s = startServer(jwtSecretFile)
+	require.NoError(t, authorize(s, token))
+
+	// Logging out replaces the persisted key, so the token stays invalid after another restart.
+	_, err = s.Logout(context.Background(), &ptypes.Empty{})
+	require.NoError(t, err)
+	require.NoError(t, s.Stop())
+	s = startServer(jwtSecretFile)
+	assert.ErrorContains(t, "Could not parse JWT token", authorize(s, token))
+	newToken, _, err := s.createTokenString()
+	require.NoError(t, err)
+	require.NoError(t, s.Stop())
+
+	// Without a JWT secret file, every start creates a new key.
+	s = startServer("")
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	assert.ErrorContains(t, "Could not parse JWT token", authorize(s, newToken))
+}
+
+func TestServer_InitializeJWTKey_InvalidSecretFile(t *testing.T) {
+	jwtSecretFile := filepath.Join(t.TempDir(), JWTSecretFileName)
+	require.NoError(t, ioutil.WriteFile(jwtSecretFile, []byte("short"), 0600))
+	s := &Server{jwtSecretFile: jwtSecretFile}
+	_, err := s.initializeJWTKey()
+	assert.ErrorContains(t, "must contain a 32 byte key", err)
+}
+
+func TestServer_InitializeJWTKey_PermissiveSecretFile(t *testing.T) {
+	jwtSecretFile := filepath.Join(t.TempDir(), JWTSecretFileName)
+	require.NoError(t, ioutil.WriteFile(jwtSecretFile, make([]byte, 32), 0644))
+	s := &Server{jwtSecretFile: jwtSecretFile}
+	_, err := s.initializeJWTKey()
+	assert.ErrorContains(t, "is accessible by other users", err)
+}
+
+func TestServer_InitializeJWTKey_ReadOnlySecretFile(t *testing.T) {
+	jwtSecretFile := filepath.Join(t.TempDir(), JWTSecretFileName)
+	key := make([]byte, 32)
+	key[0] = 1
+	require.NoError(t, ioutil.WriteFile(jwtSecretFile, key, 0400))
+	s := &Server{jwtSecretFile: jwtSecretFile}
+	jwtKey, err := s.initializeJWTKey()
+	require.NoError(t, err)
+	assert.DeepEqual(t, key, jwtKey)
+}
+
+func TestServer_UnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "rpc", "rpc.sock")
+	require.NoError(t, fileutil.MkdirAll(filepath.Dir(socketPath)))
+	// Leave behind a socket file, as a process which did not shut down cleanly would.
+	stale, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	s := NewServer(context.Background(), &Config{
+		Host:           "127.0.0.1",
+		Port:           "0",
+		UnixSocketPath: socketPath,
+	})
+	s.Start()
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm(), "Unix socket is not private")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, socketPath, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", addr)
+		}),
+	)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	resp, err := pb.NewHealthClient(conn).GetCertificateFingerprint(ctx, &ptypes.Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, false, resp.TlsEnabled)
+}
+
+func TestListenUnixSocket_RefusesToReplaceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rpc", "rpc.sock")
+	require.NoError(t, fileutil.MkdirAll(filepath.Dir(path)))
+	require.NoError(t, ioutil.WriteFile(path, []byte("data"), 0600))
+	_, err := listenUnixSocket(path)
+	assert.ErrorContains(t, "exists and is not a unix socket", err)
+}
+
+func TestListenUnixSocket_RefusesAccessibleDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "rpc")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	require.NoError(t, os.Chmod(dir, 0755))
+	_, err := listenUnixSocket(filepath.Join(dir, "rpc.sock"))
+	assert.ErrorContains(t, "could not create unix socket directory", err)
+}
+
+func TestServer_MaxConnectionIdle(t *testing.T) {
+	s := NewServer(context.Background(), &Config{
+		Host:              "127.0.0.1",
+		Port:              "0",
+		MaxConnectionIdle: 500 * time.Millisecond,
+	})
+	s.Start()
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+	address := s.listener.Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// Counts the connections made by a client, which reconnects once the server closes its connection.
+	dial := func(dials *int32) *grpc.ClientConn {
+		conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock(),
+			grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+				atomic.AddInt32(dials, 1)
+				var d net.Dialer
+				return d.DialContext(ctx, "tcp", addr)
+			}),
+		)
+		require.NoError(t, err)
+		return conn
+	}
+	var idleDials, activeDials int32
+	idleConn, activeConn := dial(&idleDials), dial(&activeDials)
+	defer func() {
+		require.NoError(t, idleConn.Close())
+		require.NoError(t, activeConn.Close())
+	}()
+
+	closed := make(chan bool, 1)
+	go func() {
+		closed <- idleConn.WaitForStateChange(ctx, connectivity.Ready)
+	}()
+	// Calls made more often than the idle timeout keep the active connection open.
+	client := pb.NewHealthClient(activeConn)
+	for i := 0; i < 10; i++ {
+		_, err := client.GetCertificateFingerprint(ctx, &ptypes.Empty{})
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, true, <-closed, "Idle connection was not closed")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&activeDials), "Active connection was closed")
+	assert.Equal(t, connectivity.Ready, activeConn.GetState())
+}
+
+func TestServer_Stop_ForcedAfterShutdownTimeout(t *testing.T) {
+	hook := logTest.NewGlobal()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	// A stream whose handler never returns, which would block a graceful stop forever.
+	stuckService := &grpc.ServiceDesc{
+		ServiceName: "test.Stuck",
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "Stream",
+			ServerStreams: true,
+			Handler: func(interface{}, grpc.ServerStream) error {
+				close(started)
+				<-release
+				return nil
+			},
+		}},
+	}
+	grpcServer := grpc.NewServer()
+	grpcServer.RegisterService(stuckService, struct{}{})
+	served := make(chan error, 1)
+	go func() {
+		served <- grpcServer.Serve(lis)
+	}()
+	_, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		cancel:          cancel,
+		listener:        lis,
+		grpcServer:      grpcServer,
+		shutdownTimeout: 100 * time.Millisecond,
+	}
+
+	ctx, cancelDial := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelDial()
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	_, err = conn.NewStream(ctx, &stuckService.Streams[0], "/test.Stuck/Stream")
+	require.NoError(t, err)
+	<-started
+
+	start := time.Now()
+	require.NoError(t, s.Stop())
+	assert.Equal(t, true, time.Since(start) < time.Second, "Stop did not return within the shutdown timeout")
+	require.LogsContain(t, hook, "Forced server to stop")
+	require.NoError(t, <-served)
+}
+
+func TestServer_MaxMsgSize(t *testing.T) {
+	// Larger than the 4MB gRPC default, like a large batch of keystores.
+	req := &pb.ImportKeystoresRequest{
+		KeystoresPassword: "password",
+		KeystoresImported: []string{strings.Repeat("a", 5<<20)},
+	}
+	importKeystores := func(maxMsgSize int) error {
+		s := NewServer(context.Background(), &Config{
+			Host:       "127.0.0.1",
+			Port:       "0",
+			MaxMsgSize: maxMsgSize,
+		})
+		s.Start()
+		defer func() {
+			require.NoError(t, s.Stop())
+		}()
+		token, _, err := s.createTokenString()
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, s.listener.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		_, err = pb.NewWalletClient(conn).ImportKeystores(ctx, req)
+		return err
+	}
+
+	// With the default size, the request reaches the handler, which has no wallet to import into.
+	assert.ErrorContains(t, "No wallet initialized", importKeystores(0))
+	err := importKeystores(4 << 20)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "Request larger than the maximum message size was received")
+}
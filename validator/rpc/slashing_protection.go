@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CheckSlashingProtectionHistory scans the slashing protection history of validating keys
+// for records which are inconsistent with each other, such as an attestation whose source
+// epoch is after its target epoch. Inconsistencies indicate a corrupted database, which is
+// better found by an audit than by a missed or slashable signature. Every key of the wallet
+// is checked unless specific keys are requested.
+func (s *Server) CheckSlashingProtectionHistory(
+	ctx context.Context, req *pb.SlashingProtectionHistoryRequest,
+) (*pb.SlashingProtectionHistoryResponse, error) {
+	if s.valDB == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Validator database not yet initialized")
+	}
+	var pubKeys [][48]byte
+	if len(req.PublicKeys) == 0 {
+		if !s.walletInitialized {
+			return nil, status.Error(codes.FailedPrecondition, "Wallet not yet initialized")
+		}
+		var err error
+		pubKeys, err = s.keymanager.FetchValidatingPublicKeys(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not fetch validating public keys: %v", err)
+		}
+	} else {
+		pubKeys = make([][48]byte, len(req.PublicKeys))
+		for i, pubKey := range req.PublicKeys {
+			if len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+				return nil, status.Errorf(
+					codes.InvalidArgument,
+					"Public key must be %d bytes, received %d",
+					params.BeaconConfig().BLSPubkeyLength,
+					len(pubKey),
+				)
+			}
+			pubKeys[i] = bytesutil.ToBytes48(pubKey)
+		}
+	}
+
+	reports := make([]*pb.SlashingProtectionHistoryReport, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		inconsistencies, err := s.valDB.SlashingProtectionInconsistencies(ctx, pubKey)
+		if err != nil {
+			return nil, status.Errorf(
+				codes.Internal, "Could not check slashing protection history of public key %#x: %v", pubKey, err,
+			)
+		}
+		if len(inconsistencies) > 0 {
+			log.WithFields(logrus.Fields{
+				"publicKey":       fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:])),
+				"inconsistencies": inconsistencies,
+			}).Warn("Slashing protection history is inconsistent, the database may be corrupted")
+		}
+		reports[i] = &pb.SlashingProtectionHistoryReport{
+			PublicKey:       bytesutil.FromBytes48(pubKey),
+			Inconsistencies: inconsistencies,
+		}
+	}
+	return &pb.SlashingProtectionHistoryResponse{Reports: reports}, nil
+}
+
+// Compares the validating keys with the keys which have slashing protection history, warning
+// about keys in only one of them. A validating key without history is expected the first time
+// it is used, but could also mean it is being used with the wrong database. History for a key
+// which is not in the wallet suggests the wallet or database is misconfigured.
+func (s *Server) checkSlashingProtectionKeys(ctx context.Context) error {
+	validatingKeys, err := s.keymanager.FetchValidatingPublicKeys(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch validating public keys")
+	}
+	protectedKeys, err := s.slashingProtectionPublicKeys(ctx)
+	if err != nil {
+		return err
+	}
+	validating := make(map[[48]byte]bool, len(validatingKeys))
+	for _, pubKey := range validatingKeys {
+		validating[pubKey] = true
+	}
+	protected := make(map[[48]byte]bool, len(protectedKeys))
+	for _, pubKey := range protectedKeys {
+		protected[pubKey] = true
+		if !validating[pubKey] {
+			log.WithField("publicKey", fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:]))).Warn(
+				"Slashing protection history exists for a key which is not in the wallet, " +
+					"the wallet or database may be misconfigured",
+			)
+		}
+	}
+	for _, pubKey := range validatingKeys {
+		if !protected[pubKey] {
+			log.WithField("publicKey", fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:]))).Warn(
+				"Validating key has no slashing protection history, which is only expected " +
+					"if it has never signed with this validator client",
+			)
+		}
+	}
+	return nil
+}
+
+// Returns the keys with a signed attestation or proposal in the slashing protection database.
+// Keys with an empty proposal history bucket are skipped, as the validator client creates
+// those for every validating key before it signs anything.
+func (s *Server) slashingProtectionPublicKeys(ctx context.Context) ([][48]byte, error) {
+	attestedKeys, err := s.valDB.AttestedPublicKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch attested public keys")
+	}
+	proposedKeys, err := s.valDB.ProposedPublicKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch proposed public keys")
+	}
+	seen := make(map[[48]byte]bool, len(attestedKeys))
+	pubKeys := make([][48]byte, 0, len(attestedKeys))
+	for _, pubKey := range attestedKeys {
+		seen[pubKey] = true
+		pubKeys = append(pubKeys, pubKey)
+	}
+	for _, pubKey := range proposedKeys {
+		if seen[pubKey] {
+			continue
+		}
+		highestProposal, err := s.valDB.HighestSignedProposal(ctx, pubKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not fetch highest signed proposal of public key %#x", pubKey)
+		}
+		if highestProposal > 0 {
+			seen[pubKey] = true
+			pubKeys = append(pubKeys, pubKey)
+		}
+	}
+	return pubKeys, nil
+}
@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/prysmaticlabs/prysm/validator/db/kv"
+	dbtest "github.com/prysmaticlabs/prysm/validator/db/testing"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestServer_CheckSlashingProtectionHistory(t *testing.T) {
+	ctx := context.Background()
+	consistent := [48]byte{1}
+	corrupted := [48]byte{2}
+	valDB := dbtest.SetupDB(t, [][48]byte{consistent, corrupted})
+	s := &Server{
+		valDB:             valDB,
+		keymanager:        &mockPubKeysKeymanager{pubKeys: [][48]byte{consistent, corrupted}},
+		walletInitialized: true,
+	}
+
+	signingRoot := make([]byte, 32)
+	history, err := kv.MarkAllAsAttestedSinceLatestWrittenEpoch(
+		ctx, kv.NewAttestationHistoryArray(0), 3, &kv.HistoryData{Source: 2, SigningRoot: signingRoot},
+	)
+	require.NoError(t, err)
+	require.NoError(t, valDB.SaveAttestationHistoryForPubKeyV2(ctx, consistent, history))
+	// An attestation whose source is after its target can only have been written by a corrupted database.
+	history, err = kv.MarkAllAsAttestedSinceLatestWrittenEpoch(
+		ctx, kv.NewAttestationHistoryArray(0), 3, &kv.HistoryData{Source: 5, SigningRoot: signingRoot},
+	)
+	require.NoError(t, err)
+	require.NoError(t, valDB.SaveAttestationHistoryForPubKeyV2(ctx, corrupted, history))
+
+	resp, err := s.CheckSlashingProtectionHistory(ctx, &pb.SlashingProtectionHistoryRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resp.Reports))
+	assert.DeepEqual(t, consistent[:], resp.Reports[0].PublicKey)
+	assert.Equal(t, 0, len(resp.Reports[0].Inconsistencies))
+	assert.DeepEqual(t, corrupted[:], resp.Reports[1].PublicKey)
+	assert.DeepEqual(t, []string{"attestation for target epoch 3 has a later source epoch 5"}, resp.Reports[1].Inconsistencies)
+
+	resp, err = s.CheckSlashingProtectionHistory(ctx, &pb.SlashingProtectionHistoryRequest{
+		PublicKeys: [][]byte{corrupted[:]},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(resp.Reports))
+	assert.DeepEqual(t, corrupted[:], resp.Reports[0].PublicKey)
+	assert.Equal(t, 1, len(resp.Reports[0].Inconsistencies))
+
+	_, err = s.CheckSlashingProtectionHistory(ctx, &pb.SlashingProtectionHistoryRequest{
+		PublicKeys: [][]byte{{1, 2, 3}},
+	})
+	assert.ErrorContains(t, "Public key must be 48 bytes", err)
+
+	s.walletInitialized = false
+	_, err = s.CheckSlashingProtectionHistory(ctx, &pb.SlashingProtectionHistoryRequest{})
+	assert.ErrorContains(t, "Wallet not yet initialized", err)
+}
+
+func TestServer_CheckSlashingProtectionKeys(t *testing.T) {
+	hook := logTest.NewGlobal()
+	ctx := context.Background()
+	attested := [48]byte{1}
+	unprotected := [48]byte{2}
+	notInWallet := [48]byte{3}
+	// The validator client creates empty proposal history buckets for its validating keys.
+	valDB := dbtest.SetupDB(t, [][48]byte{attested, unprotected})
+	s := &Server{
+		valDB:             valDB,
+		keymanager:        &mockPubKeysKeymanager{pubKeys: [][48]byte{attested, unprotected}},
+		walletInitialized: true,
+	}
+
+	history, err := kv.MarkAllAsAttestedSinceLatestWrittenEpoch(
+		ctx, kv.NewAttestationHistoryArray(0), 3, &kv.HistoryData{Source: 2, SigningRoot: make([]byte, 32)},
+	)
+	require.NoError(t, err)
+	require.NoError(t, valDB.SaveAttestationHistoryForPubKeyV2(ctx, attested, history))
+	require.NoError(t, valDB.SaveProposalHistoryForSlot(ctx, notInWallet, 10, make([]byte, 32)))
+
+	protectedKeys, err := s.slashingProtectionPublicKeys(ctx)
+	require.NoError(t, err)
+	assert.DeepEqual(t, [][48]byte{attested, notInWallet}, protectedKeys)
+
+	require.NoError(t, s.checkSlashingProtectionKeys(ctx))
+	warnings := make(map[string]string)
+	for _, entry := range hook.AllEntries() {
+		if pubKey, ok := entry.Data["publicKey"].(string); ok {
+			warnings[pubKey] = entry.Message
+		}
+	}
+	require.Equal(t, 2, len(warnings))
+	assert.Equal(
+		t,
+		"Validating key has no slashing protection history, which is only expected if it has never signed with this validator client",
+		warnings[fmt.Sprintf("%#x", unprotected[:6])],
+	)
+	assert.Equal(
+		t,
+		"Slashing protection history exists for a key which is not in the wallet, the wallet or database may be misconfigured",
+		warnings[fmt.Sprintf("%#x", notInWallet[:6])],
+	)
+}
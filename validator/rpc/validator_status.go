@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/event"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/timeutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The longest time between attempts to poll validator statuses while the beacon node is unreachable.
+const maxValidatorStatusPollBackoff = time.Minute
+
+// The number of status changes buffered for each stream before broadcasting blocks.
+const validatorStatusChangeBuffer = 64
+
+// Tracks the last known status of each validator in the wallet, and broadcasts
+// every change in status to the subscribed streams.
+type validatorStatusTracker struct {
+	lock     sync.RWMutex
+	statuses map[[48]byte]ethpb.ValidatorStatus
+	feed     event.Feed
+}
+
+// Records the polled statuses, replacing those of validators no longer in the wallet,
+// and broadcasts a change for every validator whose status differs from the last known one.
+func (t *validatorStatusTracker) update(statuses map[[48]byte]ethpb.ValidatorStatus, now time.Time) {
+	t.lock.Lock()
+	changes := diffValidatorStatuses(t.statuses, statuses, now)
+	t.statuses = statuses
+	t.lock.Unlock()
+	for _, change := range changes {
+		t.feed.Send(change)
+	}
+}
+
+// Returns the last known status of every validator, as changes from an unknown status.
+func (t *validatorStatusTracker) snapshot(now time.Time) []*pb.ValidatorStatusChange {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return diffValidatorStatuses(nil, t.statuses, now)
+}
+
+// Returns a change for every validator whose current status differs from its previous
+// status, including validators whose previous status is not known. Changes are sorted
+// by public key, so they are broadcast in a deterministic order.
+func diffValidatorStatuses(prev, curr map[[48]byte]ethpb.ValidatorStatus, now time.Time) []*pb.ValidatorStatusChange {
+	changes := make([]*pb.ValidatorStatusChange, 0)
+	for pubKey, st := range curr {
+		prevStatus, known := prev[pubKey]
+		if known && prevStatus == st {
+			continue
+		}
+		key := pubKey
+		change := &pb.ValidatorStatusChange{
+			PublicKey: key[:],
+			Status:    st.String(),
+			Timestamp: uint64(now.Unix()),
+		}
+		if known {
+			change.PreviousStatus = prevStatus.String()
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].PublicKey, changes[j].PublicKey) < 0
+	})
+	return changes
+}
+
+// StreamValidatorStatusChanges streams changes to the statuses of the wallet's validators,
+// such as a validator becoming active, exiting or being slashed, as soon as they are observed
+// on the beacon node. The last known status of every validator is sent first.
+func (s *Server) StreamValidatorStatusChanges(_ *ptypes.Empty, stream pb.Accounts_StreamValidatorStatusChangesServer) error {
+	changes := make(chan *pb.ValidatorStatusChange, validatorStatusChangeBuffer)
+	// Subscribe before taking the snapshot, so no change can be missed in between.
+	sub := s.validatorStatuses.feed.Subscribe(changes)
+	defer sub.Unsubscribe()
+	for _, change := range s.validatorStatuses.snapshot(timeutils.Now()) {
+		if err := stream.Send(change); err != nil {
+			return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+		}
+	}
+	for {
+		select {
+		case change := <-changes:
+			if err := stream.Send(change); err != nil {
+				return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+			}
+		case <-sub.Err():
+			return status.Error(codes.Aborted, "Subscriber closed, exiting goroutine")
+		case <-s.ctx.Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		}
+	}
+}
+
+// Polls the statuses of the wallet's validators from the beacon node once per slot until the
+// context is canceled. While the beacon node is unreachable, polling is retried with an
+// increasing backoff, and once it is reachable again the first successful poll broadcasts
+// every change missed in the meantime.
+func (s *Server) pollValidatorStatuses(ctx context.Context) {
+	interval := time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+	backoff := interval
+	failing := false
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if err := s.pollValidatorStatusesOnce(ctx); err != nil {
+			if !failing {
+				log.WithError(err).Warn("Could not poll validator statuses from the beacon node, retrying")
+			}
+			failing = true
+			timer.Reset(backoff)
+			backoff *= 2
+			if backoff > maxValidatorStatusPollBackoff {
+				backoff = maxValidatorStatusPollBackoff
+			}
+			continue
+		}
+		if failing {
+			log.Info("Resumed polling validator statuses from the beacon node")
+		}
+		failing = false
+		backoff = interval
+		timer.Reset(interval)
+	}
+}
+
+func (s *Server) pollValidatorStatusesOnce(ctx context.Context) error {
+	if !s.walletInitialized || s.keymanager == nil || s.beaconNodeInfoFetcher == nil {
+		return nil
+	}
+	pubKeys, err := s.keymanager.FetchValidatingPublicKeys(ctx)
+	if err != nil {
+		return err
+	}
+	statuses, err := s.beaconNodeInfoFetcher.ValidatorStatuses(ctx, pubKeys)
+	if err != nil {
+		return err
+	}
+	s.validatorStatuses.update(statuses, timeutils.Now())
+	return nil
+}
@@ -0,0 +1,151 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/prysmaticlabs/prysm/proto/validator/accounts/v2"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"google.golang.org/grpc"
+)
+
+type mockPubKeysKeymanager struct {
+	pubKeys [][48]byte
+}
+
+func (m *mockPubKeysKeymanager) FetchValidatingPublicKeys(_ context.Context) ([][48]byte, error) {
+	return m.pubKeys, nil
+}
+
+func (m *mockPubKeysKeymanager) FetchAllValidatingPublicKeys(_ context.Context) ([][48]byte, error) {
+	return m.pubKeys, nil
+}
+
+func (m *mockPubKeysKeymanager) Sign(_ context.Context, _ *pb.SignRequest) (bls.Signature, error) {
+	return nil, errors.New("not implemented")
+}
+
+type mockValidatorStatusStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	changes chan *pb.ValidatorStatusChange
+}
+
+func (m *mockValidatorStatusStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockValidatorStatusStream) Send(change *pb.ValidatorStatusChange) error {
+	m.changes <- change
+	return nil
+}
+
+func TestDiffValidatorStatuses(t *testing.T) {
+	keyA, keyB, keyC := [48]byte{1}, [48]byte{2}, [48]byte{3}
+	now := time.Unix(1600000000, 0)
+	prev := map[[48]byte]ethpb.ValidatorStatus{
+		keyA: ethpb.ValidatorStatus_PENDING,
+		keyB: ethpb.ValidatorStatus_ACTIVE,
+	}
+	curr := map[[48]byte]ethpb.ValidatorStatus{
+		keyC: ethpb.ValidatorStatus_DEPOSITED,
+		keyB: ethpb.ValidatorStatus_ACTIVE,
+		keyA: ethpb.ValidatorStatus_ACTIVE,
+	}
+	assert.DeepEqual(t, []*pb.ValidatorStatusChange{
+		{
+			PublicKey:      keyA[:],
+			PreviousStatus: "PENDING",
+			Status:         "ACTIVE",
+			Timestamp:      1600000000,
+		},
+		{
+			PublicKey: keyC[:],
+			Status:    "DEPOSITED",
+			Timestamp: 1600000000,
+		},
+	}, diffValidatorStatuses(prev, curr, now))
+	assert.Equal(t, 0, len(diffValidatorStatuses(curr, curr, now)))
+}
+
+func TestServer_StreamValidatorStatusChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	keyA, keyB := [48]byte{1}, [48]byte{2}
+	fetcher := &mockBeaconInfoFetcher{
+		statuses: map[[48]byte]ethpb.ValidatorStatus{
+			keyA: ethpb.ValidatorStatus_ACTIVE,
+			keyB: ethpb.ValidatorStatus_PENDING,
+		},
+	}
+	s := &Server{
+		ctx:                   ctx,
+		walletInitialized:     true,
+		keymanager:            &mockPubKeysKeymanager{pubKeys: [][48]byte{keyA, keyB}},
+		beaconNodeInfoFetcher: fetcher,
+	}
+	require.NoError(t, s.pollValidatorStatusesOnce(ctx))
+
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	stream := &mockValidatorStatusStream{
+		ctx:     streamCtx,
+		changes: make(chan *pb.ValidatorStatusChange, 10),
+	}
+	done := make(chan error)
+	go func() {
+		done <- s.StreamValidatorStatusChanges(&ptypes.Empty{}, stream)
+	}()
+
+	// The last known statuses are sent first.
+	for _, want := range []string{"ACTIVE", "PENDING"} {
+		change := receiveStatusChange(t, stream.changes)
+		assert.Equal(t, want, change.Status)
+		assert.Equal(t, "", change.PreviousStatus)
+	}
+
+	// A failed poll, such as while the beacon node is unreachable, broadcasts nothing.
+	fetcher.err = errors.New("connection refused")
+	assert.ErrorContains(t, "connection refused", s.pollValidatorStatusesOnce(ctx))
+
+	// Once polling succeeds again, only the changes are broadcast.
+	fetcher.err = nil
+	fetcher.statuses = map[[48]byte]ethpb.ValidatorStatus{
+		keyA: ethpb.ValidatorStatus_SLASHING,
+		keyB: ethpb.ValidatorStatus_PENDING,
+	}
+	require.NoError(t, s.pollValidatorStatusesOnce(ctx))
+	change := receiveStatusChange(t, stream.changes)
+	assert.DeepEqual(t, keyA[:], change.PublicKey)
+	assert.Equal(t, "ACTIVE", change.PreviousStatus)
+	assert.Equal(t, "SLASHING", change.Status)
+	select {
+	case change := <-stream.changes:
+		t.Fatalf("Unexpected status change %v", change)
+	default:
+	}
+
+	// The stream ends once the client cancels it.
+	cancelStream()
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, "Context canceled", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream did not end after it was canceled")
+	}
+}
+
+func receiveStatusChange(t *testing.T, changes chan *pb.ValidatorStatusChange) *pb.ValidatorStatusChange {
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("Did not receive a status change")
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/rand"
 	"github.com/prysmaticlabs/prysm/validator/accounts"
 	"github.com/prysmaticlabs/prysm/validator/accounts/wallet"
+	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/prysmaticlabs/prysm/validator/keymanager/imported"
 	"github.com/tyler-smith/go-bip39"
@@ -202,6 +203,11 @@ func (s *Server) ImportKeystores(
 	if req.KeystoresImported == nil || len(req.KeystoresImported) < 1 {
 		return nil, status.Error(codes.InvalidArgument, "No keystores included for import")
 	}
+	// Enforce the maximum wallet size before decrypting anything, as a huge
+	// bundle of keystores could otherwise exhaust memory.
+	if err := s.checkWalletSize(ctx, km, len(req.KeystoresImported)); err != nil {
+		return nil, err
+	}
 	keystores := make([]*keymanager.Keystore, len(req.KeystoresImported))
 	importedPubKeys := make([][]byte, len(req.KeystoresImported))
 	for i := 0; i < len(req.KeystoresImported); i++ {
@@ -231,6 +237,29 @@ func (s *Server) ImportKeystores(
 	}, nil
 }
 
+// Ensures adding the given number of accounts to the wallet does not
+// exceed the configured maximum wallet size.
+func (s *Server) checkWalletSize(ctx context.Context, km keymanager.IKeymanager, numNewAccounts int) error {
+	maxWalletSize := s.maxWalletSize
+	if maxWalletSize <= 0 {
+		maxWalletSize = flags.DefaultMaxWalletSize
+	}
+	existingKeys, err := km.FetchAllValidatingPublicKeys(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not fetch existing accounts: %v", err)
+	}
+	if len(existingKeys)+numNewAccounts > maxWalletSize {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"Adding %d accounts to a wallet with %d accounts would exceed the maximum wallet size of %d",
+			numNewAccounts,
+			len(existingKeys),
+			maxWalletSize,
+		)
+	}
+	return nil
+}
+
 // Initialize a wallet and send it over a global feed.
 func (s *Server) initializeWallet(ctx context.Context, cfg *wallet.Config) error {
 	// We first ensure the user has a wallet.
@@ -282,6 +282,82 @@ func TestServer_ImportKeystores_OK(t *testing.T) {
 	assert.Equal(t, 3, len(keys))
 }
 
+func TestServer_ImportKeystores_MaxWalletSize(t *testing.T) {
+	imported.ResetCaches()
+	localWalletDir := setupWalletDir(t)
+	defaultWalletPath = localWalletDir
+	ctx := context.Background()
+	strongPass := "29384283xasjasd32%%&*@*#*"
+	w, err := accounts.CreateWalletWithKeymanager(ctx, &accounts.CreateWalletConfig{
+		WalletCfg: &wallet.Config{
+			WalletDir:      defaultWalletPath,
+			KeymanagerKind: keymanager.Imported,
+			WalletPassword: strongPass,
+		},
+		SkipMnemonicConfirm: true,
+	})
+	require.NoError(t, err)
+	km, err := w.InitializeKeymanager(ctx)
+	require.NoError(t, err)
+	ss := &Server{
+		keymanager:            km,
+		wallet:                w,
+		walletInitializedFeed: new(event.Feed),
+		maxWalletSize:         3,
+	}
+
+	encryptor := keystorev4.New()
+	createKeystores := func(n int) []string {
+		keystores := make([]string, n)
+		for i := 0; i < n; i++ {
+			privKey, err := bls.RandKey()
+			require.NoError(t, err)
+			id, err := uuid.NewRandom()
+			require.NoError(t, err)
+			cryptoFields, err := encryptor.Encrypt(privKey.Marshal(), strongPass)
+			require.NoError(t, err)
+			item := &keymanager.Keystore{
+				Crypto:  cryptoFields,
+				ID:      id.String(),
+				Version: encryptor.Version(),
+				Pubkey:  fmt.Sprintf("%x", privKey.PublicKey().Marshal()),
+				Name:    encryptor.Name(),
+			}
+			encodedFile, err := json.MarshalIndent(item, "", "\t")
+			require.NoError(t, err)
+			keystores[i] = string(encodedFile)
+		}
+		return keystores
+	}
+
+	// Importing just under the limit succeeds.
+	_, err = ss.ImportKeystores(ctx, &pb.ImportKeystoresRequest{
+		KeystoresPassword: strongPass,
+		KeystoresImported: createKeystores(2),
+	})
+	require.NoError(t, err)
+
+	// Importing past the limit is rejected before any keystore is decrypted.
+	_, err = ss.ImportKeystores(ctx, &pb.ImportKeystoresRequest{
+		KeystoresPassword: strongPass,
+		KeystoresImported: createKeystores(2),
+	})
+	assert.ErrorContains(t, "would exceed the maximum wallet size of 3", err)
+	keys, err := km.FetchAllValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(keys))
+
+	// Filling the wallet exactly up to the limit succeeds.
+	_, err = ss.ImportKeystores(ctx, &pb.ImportKeystoresRequest{
+		KeystoresPassword: strongPass,
+		KeystoresImported: createKeystores(1),
+	})
+	require.NoError(t, err)
+	keys, err = km.FetchAllValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(keys))
+}
+
 func Test_writeWalletPasswordToDisk(t *testing.T) {
 	walletDir := setupWalletDir(t)
 	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{
@@ -2,14 +2,34 @@ package interchangeformat
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/validator/db"
 )
 
 // ExportStandardProtectionJSON extracts all slashing protection data from a validator database
-// and packages it into an EIP-3076 compliant, standard
-func ExportStandardProtectionJSON(ctx context.Context, validatorDB db.Database) (*EIPSlashingProtectionFormat, error) {
+// and writes it to w as minified, EIP-3076 compliant JSON. Validators which have neither signed
+// blocks nor attestations have no history to export, so they are left out.
+func ExportStandardProtectionJSON(ctx context.Context, validatorDB db.Database, w io.Writer) error {
+	interchangeJSON, err := exportStandardProtection(ctx, validatorDB)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(interchangeJSON)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal slashing protection JSON")
+	}
+	if _, err := w.Write(encoded); err != nil {
+		return errors.Wrap(err, "could not write slashing protection JSON")
+	}
+	return nil
+}
+
+func exportStandardProtection(ctx context.Context, validatorDB db.Database) (*EIPSlashingProtectionFormat, error) {
 	interchangeJSON := &EIPSlashingProtectionFormat{}
 	genesisValidatorsRoot, err := validatorDB.GenesisValidatorsRoot(ctx)
 	if err != nil {
@@ -27,10 +47,24 @@ func ExportStandardProtectionJSON(ctx context.Context, validatorDB db.Database)
 	if err != nil {
 		return nil, err
 	}
-	dataByPubKey := make(map[[48]byte]*ProtectionData)
+	attestedPublicKeys, err := validatorDB.AttestedPublicKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seenPublicKeys := make(map[[48]byte]bool)
+	publicKeys := make([][48]byte, 0, len(proposedPublicKeys)+len(attestedPublicKeys))
+	for _, pubKey := range append(proposedPublicKeys, attestedPublicKeys...) {
+		if seenPublicKeys[pubKey] {
+			continue
+		}
+		seenPublicKeys[pubKey] = true
+		publicKeys = append(publicKeys, pubKey)
+	}
 
-	// Extract the signed proposals by public keys.
-	for _, pubKey := range proposedPublicKeys {
+	// Extract the signed proposals and attestations by public keys, into a
+	// slice as expected by the EIP-3076 JSON standard.
+	dataList := make([]*ProtectionData, 0, len(publicKeys))
+	for _, pubKey := range publicKeys {
 		pubKeyHex, err := pubKeyToHexString(pubKey[:])
 		if err != nil {
 			return nil, err
@@ -39,22 +73,68 @@ func ExportStandardProtectionJSON(ctx context.Context, validatorDB db.Database)
 		if err != nil {
 			return nil, err
 		}
-		dataByPubKey[pubKey] = &ProtectionData{
+		signedAttestations, err := getSignedAttestationsByPubKey(ctx, validatorDB, pubKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(signedBlocks) == 0 && len(signedAttestations) == 0 {
+			continue
+		}
+		dataList = append(dataList, &ProtectionData{
 			Pubkey:             pubKeyHex,
 			SignedBlocks:       signedBlocks,
-			SignedAttestations: nil,
-		}
-	}
-
-	// Next we turn our map into a slice as expected by the EIP-3076 JSON standard.
-	dataList := make([]*ProtectionData, 0)
-	for _, item := range dataByPubKey {
-		dataList = append(dataList, item)
+			SignedAttestations: signedAttestations,
+		})
 	}
 	interchangeJSON.Data = dataList
 	return interchangeJSON, nil
 }
 
+// The attesting history only keeps the latest weak subjectivity period of target epochs,
+// in which targets without an attestation are marked as empty.
+func getSignedAttestationsByPubKey(ctx context.Context, validatorDB db.Database, pubKey [48]byte) ([]*SignedAttestation, error) {
+	histories, err := validatorDB.AttestationHistoryForPubKeysV2(ctx, [][48]byte{pubKey})
+	if err != nil {
+		return nil, err
+	}
+	history, ok := histories[pubKey]
+	if !ok {
+		return nil, fmt.Errorf("no attesting history for key %#x", pubKey)
+	}
+	latestEpochWritten, err := history.GetLatestEpochWritten(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	oldestTarget := uint64(0)
+	if latestEpochWritten >= wsPeriod {
+		oldestTarget = latestEpochWritten - wsPeriod + 1
+	}
+	signedAttestations := make([]*SignedAttestation, 0)
+	for target := oldestTarget; target <= latestEpochWritten; target++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		data, err := history.GetTargetData(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		if data.IsEmpty() {
+			continue
+		}
+		signingRootHex, err := rootToHexString(data.SigningRoot)
+		if err != nil {
+			return nil, err
+		}
+		signedAttestations = append(signedAttestations, &SignedAttestation{
+			SourceEpoch: fmt.Sprintf("%d", data.Source),
+			TargetEpoch: fmt.Sprintf("%d", target),
+			SigningRoot: signingRootHex,
+		})
+	}
+	return signedAttestations, nil
+}
+
 func getSignedBlocksByPubKey(ctx context.Context, validatorDB db.Database, pubKey [48]byte) ([]*SignedBlock, error) {
 	lowestSignedSlot, err := validatorDB.LowestSignedProposal(ctx, pubKey)
 	if err != nil {
@@ -1,15 +1,49 @@
 package interchangeformat
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+	"github.com/prysmaticlabs/prysm/validator/db/kv"
 	dbtest "github.com/prysmaticlabs/prysm/validator/db/testing"
 )
 
+func TestExportStandardProtectionJSON(t *testing.T) {
+	proposer := [48]byte{1}
+	attester := [48]byte{2}
+	noHistory := [48]byte{3}
+	ctx := context.Background()
+	validatorDB := dbtest.SetupDB(t, [][48]byte{proposer, attester, noHistory})
+	genesisValidatorsRoot := [32]byte{4}
+	require.NoError(t, validatorDB.SaveGenesisValidatorsRoot(ctx, genesisValidatorsRoot[:]))
+
+	blockRoot := [32]byte{5}
+	require.NoError(t, validatorDB.SaveProposalHistoryForSlot(ctx, proposer, 10, blockRoot[:]))
+	attestationRoot := [32]byte{6}
+	history, err := kv.MarkAllAsAttestedSinceLatestWrittenEpoch(
+		ctx, kv.NewAttestationHistoryArray(0), 3, &kv.HistoryData{Source: 2, SigningRoot: attestationRoot[:]},
+	)
+	require.NoError(t, err)
+	require.NoError(t, validatorDB.SaveAttestationHistoryForPubKeyV2(ctx, attester, history))
+
+	// The output is minified, validators with only one kind of history have an empty
+	// list of the other, and validators without any history are left out.
+	buf := new(bytes.Buffer)
+	require.NoError(t, ExportStandardProtectionJSON(ctx, validatorDB, buf))
+	wanted := fmt.Sprintf(
+		`{"metadata":{"interchange_format_version":"5","genesis_validators_root":"%#x"},"data":[`+
+			`{"pubkey":"%#x","signed_blocks":[{"slot":"10","signing_root":"%#x"}],"signed_attestations":[]},`+
+			`{"pubkey":"%#x","signed_blocks":[],"signed_attestations":[{"source_epoch":"2","target_epoch":"3","signing_root":"%#x"}]}`+
+			`]}`,
+		genesisValidatorsRoot, proposer, blockRoot, attester, attestationRoot,
+	)
+	assert.Equal(t, wanted, buf.String())
+}
+
 func Test_getSignedBlocksByPubKey(t *testing.T) {
 	pubKeys := [][48]byte{
 		{1},
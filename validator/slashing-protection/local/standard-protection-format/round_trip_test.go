@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil/require"
 	dbtest "github.com/prysmaticlabs/prysm/validator/db/testing"
 )
@@ -31,15 +33,35 @@ func TestImportExport_RoundTrip(t *testing.T) {
 	require.NoError(t, err)
 
 	// Next up, we export our slashing protection database into the EIP standard file.
-	// Next, we attempt to import it into our validator database.
-	eipStandard, err := ExportStandardProtectionJSON(ctx, validatorDB)
-	require.NoError(t, err)
+	exported := new(bytes.Buffer)
+	require.NoError(t, ExportStandardProtectionJSON(ctx, validatorDB, exported))
+	compacted := new(bytes.Buffer)
+	require.NoError(t, json.Compact(compacted, exported.Bytes()))
+	require.DeepEqual(t, compacted.Bytes(), exported.Bytes(), "Exported JSON is not minified")
+	eipStandard := &EIPSlashingProtectionFormat{}
+	require.NoError(t, json.Unmarshal(exported.Bytes(), eipStandard))
 
-	// TODO(#7813): We have only implemented the export functionality
-	// for proposals history at the moment, so we do not check attesting history.
-	for i := range wanted.Data {
-		wanted.Data[i].SignedAttestations = nil
+	// Targets without an attestation are marked with a far future source epoch in the
+	// attesting histories, so they are not exported. Neither are validators left without
+	// any history.
+	farFutureEpoch := fmt.Sprintf("%d", params.BeaconConfig().FarFutureEpoch)
+	wantedData := make([]*ProtectionData, 0, len(wanted.Data))
+	for _, item := range wanted.Data {
+		signedAttestations := make([]*SignedAttestation, 0, len(item.SignedAttestations))
+		for _, att := range item.SignedAttestations {
+			if att.SourceEpoch != farFutureEpoch {
+				signedAttestations = append(signedAttestations, att)
+			}
+		}
+		item.SignedAttestations = signedAttestations
+		if item.SignedBlocks == nil {
+			item.SignedBlocks = make([]*SignedBlock, 0)
+		}
+		if len(item.SignedBlocks) > 0 || len(item.SignedAttestations) > 0 {
+			wantedData = append(wantedData, item)
+		}
 	}
+	wanted.Data = wantedData
 
 	// We compare the metadata fields from import to export.
 	require.Equal(t, wanted.Metadata, eipStandard.Metadata)
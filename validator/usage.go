@@ -81,22 +81,45 @@ var appHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			flags.BeaconRPCProviderFlag,
 			flags.BeaconRPCGatewayProviderFlag,
+			flags.VerificationBeaconRPCProviderFlag,
+			flags.VerificationBeaconCertFlag,
+			flags.VerificationHeadSlotToleranceFlag,
+			flags.ClockSkewTimeServerFlag,
+			flags.ClockSkewWarningThresholdFlag,
+			flags.ClockSkewRefusalThresholdFlag,
 			flags.CertFlag,
 			flags.EnableWebFlag,
+			flags.PersistJWTSecretFlag,
 			flags.DisablePenaltyRewardLogFlag,
 			flags.GraffitiFlag,
 			flags.EnableRPCFlag,
 			flags.RPCHost,
 			flags.RPCPort,
+			flags.RPCUnixSocketFlag,
+			flags.RPCMaxConnectionIdleFlag,
+			flags.RPCShutdownTimeoutFlag,
+			flags.RPCMaxMsgSizeFlag,
+			flags.RPCKeepaliveTimeFlag,
+			flags.RPCKeepaliveTimeoutFlag,
+			flags.RPCRateLimitFlag,
+			flags.RPCRateLimitBurstFlag,
+			flags.RPCUnauthenticatedRateLimitFlag,
+			flags.RPCUnauthenticatedRateLimitBurstFlag,
+			flags.DisableRPCRateLimitFlag,
+			flags.RPCCertFlag,
+			flags.RPCKeyFlag,
+			flags.RPCClientCAFlag,
 			flags.GRPCGatewayPort,
 			flags.GRPCGatewayHost,
 			flags.GrpcRetriesFlag,
 			flags.GrpcRetryDelayFlag,
 			flags.GPRCGatewayCorsDomain,
 			flags.GrpcHeadersFlag,
+			flags.MaxWalletSizeFlag,
 			flags.SlasherRPCProviderFlag,
 			flags.SlasherCertFlag,
 			flags.DisableAccountMetricsFlag,
+			flags.SlashingWarningMarginFlag,
 			flags.WalletDirFlag,
 			flags.WalletPasswordFileFlag,
 		},